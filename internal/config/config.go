@@ -118,6 +118,14 @@ func PGONamespace() string {
 	return os.Getenv("PGO_NAMESPACE")
 }
 
+// PasswordDirectory returns the directory from which PGO reads passwords that
+// are managed outside of PGO, based on the PGO_PASSWORD_DIRECTORY env var.
+// Passwords are read from a subdirectory named for the namespace of each
+// cluster. If no env var is found, returns "" and no passwords are read from files.
+func PasswordDirectory() string {
+	return os.Getenv("PGO_PASSWORD_DIRECTORY")
+}
+
 // VerifyImageValues checks that all container images required by the
 // spec are defined. If any are undefined, a list is returned in an error.
 func VerifyImageValues(cluster *v1beta1.PostgresCluster) error {
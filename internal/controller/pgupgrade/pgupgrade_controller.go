@@ -91,6 +91,8 @@ func (r *PGUpgradeReconciler) findUpgradesForPostgresCluster(
 //+kubebuilder:rbac:groups="batch",resources="jobs",verbs={list}
 //+kubebuilder:rbac:groups="",resources="endpoints",verbs={get}
 //+kubebuilder:rbac:groups="",resources="endpoints",verbs={delete}
+//+kubebuilder:rbac:groups="",resources="configmaps",verbs={get}
+//+kubebuilder:rbac:groups="",resources="configmaps",verbs={delete}
 
 // Reconcile does the work to move the current state of the world toward the
 // desired state described in a [v1beta1.PGUpgrade] identified by req.
@@ -448,15 +450,21 @@ func (r *PGUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// The upgrade job generates a new system identifier for this cluster.
-	// Clear the old identifier from Patroni by deleting its DCS Endpoints.
-	// This is safe to do this when all Patroni processes are stopped
-	// (ClusterShutdown) and PGO has identified a leader to start first
+	// Clear the old identifier from Patroni by deleting its DCS Endpoints
+	// or ConfigMaps. This is safe to do this when all Patroni processes are
+	// stopped (ClusterShutdown) and PGO has identified a leader to start first
 	// (ClusterPrimary).
 	// - https://github.com/zalando/patroni/blob/v2.1.2/docs/existing_data.rst
-	//
-	// TODO(cbandy): This works only when using Kubernetes Endpoints for DCS.
-	if len(world.PatroniEndpoints) > 0 {
+	if len(world.PatroniEndpoints) > 0 || len(world.PatroniConfigMaps) > 0 {
+		objects := make([]client.Object, 0,
+			len(world.PatroniEndpoints)+len(world.PatroniConfigMaps))
 		for _, object := range world.PatroniEndpoints {
+			objects = append(objects, object)
+		}
+		for _, object := range world.PatroniConfigMaps {
+			objects = append(objects, object)
+		}
+		for _, object := range objects {
 			uid := object.GetUID()
 			version := object.GetResourceVersion()
 			exactly := client.Preconditions{UID: &uid, ResourceVersion: &version}
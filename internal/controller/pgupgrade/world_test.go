@@ -92,6 +92,33 @@ func TestPopulatePatroniEndpoint(t *testing.T) {
 	})
 }
 
+func TestPopulatePatroniConfigMaps(t *testing.T) {
+	configmaps := []corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					LabelPatroni: "west",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"different-label": "north",
+				},
+			},
+		},
+	}
+
+	world := NewWorld()
+	world.populatePatroniConfigMaps(configmaps)
+
+	// Only the first has the correct label.
+	assert.DeepEqual(t, world.PatroniConfigMaps, []*corev1.ConfigMap{
+		&configmaps[0],
+	})
+}
+
 func TestPopulateShutdown(t *testing.T) {
 	t.Run("NoCluster", func(t *testing.T) {
 		world := NewWorld()
@@ -8,12 +8,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
@@ -53,6 +55,47 @@ func (r *Reconciler) reconcileClusterConfigMap(
 	return clusterConfigMap, err
 }
 
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get,create,delete,patch}
+
+// reconcilePostgresHBASecret writes the Secret that contains the HBA records
+// of pgHBAs that cannot be stored in Patroni configuration. The Secret is
+// deleted when no users need it.
+func (r *Reconciler) reconcilePostgresHBASecret(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pgHBAs postgres.HBAs,
+) error {
+	existing := &corev1.Secret{ObjectMeta: naming.PostgresHBASecret(cluster)}
+	err := errors.WithStack(
+		r.Client.Get(ctx, client.ObjectKeyFromObject(existing), existing))
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	if !postgres.IncludesConfidentialHBAs(cluster) {
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, existing))
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	intent := &corev1.Secret{ObjectMeta: naming.PostgresHBASecret(cluster)}
+	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	intent.Annotations = naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil())
+	intent.Labels = naming.Merge(cluster.Spec.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster: cluster.Name,
+		})
+
+	intent.Type = corev1.SecretTypeOpaque
+	postgres.ConfidentialHBASecret(pgHBAs, intent)
+
+	err = errors.WithStack(r.setControllerReference(cluster, intent))
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, intent))
+	}
+	return err
+}
+
 // patroniLogSize attempts to parse the defined log file storage limit, if configured.
 // If a value is set, this enables volume based log storage and triggers the
 // relevant Patroni configuration. If the value given is less than 25M, the log
@@ -278,23 +321,140 @@ func (r *Reconciler) generateClusterReplicaService(
 		naming.LabelRole:    naming.RolePatroniReplica,
 	}
 
+	// A selector cannot exclude Pods by label. When some replicas should not
+	// receive connections, allocate an IP address and manage the Endpoints
+	// ourselves. See [Reconciler.generateClusterReplicaEndpoints].
+	// - https://docs.k8s.io/concepts/services-networking/service/#services-without-selectors
+	for i := range cluster.Spec.InstanceSets {
+		if excludeFromReplicaService(&cluster.Spec.InstanceSets[i]) ||
+			len(cluster.Spec.InstanceSets[i].Fence) > 0 {
+			service.Spec.Selector = nil
+		}
+	}
+
 	err := errors.WithStack(r.setControllerReference(cluster, service))
 
 	return service, err
 }
 
+// excludeFromReplicaService returns whether or not instances of set should
+// be left out of the replica Service.
+func excludeFromReplicaService(set *v1beta1.PostgresInstanceSetSpec) bool {
+	return initialize.FromPointer(set.ApplyDelaySeconds) > 0 ||
+		(set.Patroni != nil && initialize.FromPointer(set.Patroni.NoLoadBalance))
+}
+
+// generateClusterReplicaEndpoints returns a v1.Endpoints that resolves to the
+// Pods Patroni has labeled as replicas, except those of instance sets that are
+// excluded from the replica Service and those of fenced instances.
+func (r *Reconciler) generateClusterReplicaEndpoints(
+	service *corev1.Service, instances *observedInstances,
+) *corev1.Endpoints {
+	// Endpoints for a Service have the same name as the Service. Copy labels,
+	// annotations, and ownership, too.
+	endpoints := &corev1.Endpoints{}
+	service.ObjectMeta.DeepCopyInto(&endpoints.ObjectMeta)
+	endpoints.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Endpoints"))
+
+	// Consider instances in a consistent order so that the Endpoints change
+	// only when their Pods do.
+	var consider []*Instance
+	for _, instance := range instances.forCluster {
+		if instance.Spec == nil || excludeFromReplicaService(instance.Spec) ||
+			instance.Spec.FencedInstance(instance.Name) != nil {
+			continue
+		}
+		if terminating, known := instance.IsTerminating(); terminating || !known {
+			continue
+		}
+		if instance.Pods[0].Labels[naming.LabelRole] == naming.RolePatroniReplica &&
+			instance.Pods[0].Status.PodIP != "" {
+			consider = append(consider, instance)
+		}
+	}
+	sort.Slice(consider, func(i, j int) bool { return consider[i].Name < consider[j].Name })
+
+	// The PostgreSQL port can differ between Pods during a rolling update.
+	// Group addresses by the port number of their PostgreSQL ContainerPort.
+	subsets := make(map[int32]*corev1.EndpointSubset)
+	for _, instance := range consider {
+		pod := instance.Pods[0]
+
+		var port *corev1.ContainerPort
+		for i := range pod.Spec.Containers {
+			for j := range pod.Spec.Containers[i].Ports {
+				if pod.Spec.Containers[i].Ports[j].Name == naming.PortPostgreSQL {
+					port = &pod.Spec.Containers[i].Ports[j]
+				}
+			}
+		}
+		if port == nil {
+			continue
+		}
+
+		subset := subsets[port.ContainerPort]
+		if subset == nil {
+			subset = &corev1.EndpointSubset{
+				Ports: []corev1.EndpointPort{{
+					Name:     naming.PortPostgreSQL,
+					Port:     port.ContainerPort,
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}
+			subsets[port.ContainerPort] = subset
+		}
+
+		address := corev1.EndpointAddress{
+			IP: pod.Status.PodIP,
+			TargetRef: &corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+		}
+		if pod.Spec.NodeName != "" {
+			address.NodeName = initialize.String(pod.Spec.NodeName)
+		}
+
+		if ready, known := instance.IsReady(); ready && known {
+			subset.Addresses = append(subset.Addresses, address)
+		} else {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, address)
+		}
+	}
+
+	ports := make([]int32, 0, len(subsets))
+	for port := range subsets {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	for _, port := range ports {
+		endpoints.Subsets = append(endpoints.Subsets, *subsets[port])
+	}
+
+	return endpoints
+}
+
+// +kubebuilder:rbac:groups="",resources="endpoints",verbs={create,patch}
 // +kubebuilder:rbac:groups="",resources="services",verbs={create,patch}
 
 // reconcileClusterReplicaService writes the Service that exposes PostgreSQL
-// replica instances.
+// replica instances. When that Service has no selector, it also writes the
+// Endpoints of the Service.
 func (r *Reconciler) reconcileClusterReplicaService(
-	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 ) (*corev1.Service, error) {
 	service, err := r.generateClusterReplicaService(cluster)
 
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, service))
 	}
+	if err == nil && service.Spec.Selector == nil {
+		err = errors.WithStack(r.apply(ctx,
+			r.generateClusterReplicaEndpoints(service, instances)))
+	}
 	return service, err
 }
 
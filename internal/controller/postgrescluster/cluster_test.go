@@ -13,6 +13,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -23,6 +24,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
 	"github.com/crunchydata/postgres-operator/internal/testing/events"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
@@ -784,6 +786,123 @@ postgres-operator.crunchydata.com/cluster: pg2
 postgres-operator.crunchydata.com/role: replica
 		`))
 	})
+
+	t.Run("NoLoadBalance", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "one"},
+			{Name: "two", Patroni: &v1beta1.PatroniInstanceSetSpec{
+				NoLoadBalance: initialize.Bool(true),
+			}},
+		}
+
+		service, err := reconciler.generateClusterReplicaService(cluster)
+		assert.NilError(t, err)
+		assert.Assert(t, service.Spec.Selector == nil)
+		assert.Equal(t, service.Spec.Type, corev1.ServiceTypeClusterIP)
+	})
+
+	t.Run("Fenced", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "one", Fence: []v1beta1.InstanceFence{{Name: "one-aaaa"}}},
+		}
+
+		service, err := reconciler.generateClusterReplicaService(cluster)
+		assert.NilError(t, err)
+		assert.Assert(t, service.Spec.Selector == nil)
+	})
+}
+
+func TestGenerateClusterReplicaEndpoints(t *testing.T) {
+	t.Parallel()
+
+	reconciler := &Reconciler{}
+
+	service := &corev1.Service{}
+	service.Namespace, service.Name = "ns1", "pg2-replicas"
+	service.Labels = map[string]string{"some": "label"}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", Fence: []v1beta1.InstanceFence{{Name: "one-ffff"}}},
+		{Name: "two", Patroni: &v1beta1.PatroniInstanceSetSpec{
+			NoLoadBalance: initialize.Bool(true),
+		}},
+		{Name: "three", ApplyDelaySeconds: initialize.Int32(3600)},
+	}
+
+	pod := func(set, name, role, ip string, port int32, ready corev1.ConditionStatus) corev1.Pod {
+		var pod corev1.Pod
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Labels = map[string]string{
+			naming.LabelInstanceSet: set,
+			naming.LabelInstance:    name,
+			naming.LabelRole:        role,
+		}
+		pod.Spec.NodeName = "node1"
+		pod.Spec.Containers = []corev1.Container{{
+			Name:  naming.ContainerDatabase,
+			Ports: []corev1.ContainerPort{{Name: naming.PortPostgreSQL, ContainerPort: port}},
+		}}
+		pod.Status.PodIP = ip
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}
+		return pod
+	}
+
+	observed := newObservedInstances(cluster, nil, []corev1.Pod{
+		pod("one", "one-bbbb", naming.RolePatroniReplica, "10.0.0.2", 5432, corev1.ConditionFalse),
+		pod("one", "one-aaaa", naming.RolePatroniReplica, "10.0.0.1", 5432, corev1.ConditionTrue),
+		pod("one", "one-cccc", naming.RolePatroniLeader, "10.0.0.3", 5432, corev1.ConditionTrue),
+		pod("one", "one-dddd", naming.RolePatroniReplica, "10.0.0.4", 6543, corev1.ConditionTrue),
+		pod("two", "two-aaaa", naming.RolePatroniReplica, "10.0.0.5", 5432, corev1.ConditionTrue),
+		pod("three", "three-aaaa", naming.RolePatroniReplica, "10.0.0.6", 5432, corev1.ConditionTrue),
+		pod("one", "one-eeee", naming.RolePatroniReplica, "", 5432, corev1.ConditionFalse),
+		pod("one", "one-ffff", naming.RolePatroniReplica, "10.0.0.7", 5432, corev1.ConditionTrue),
+	})
+
+	endpoints := reconciler.generateClusterReplicaEndpoints(service, observed)
+
+	assert.Assert(t, cmp.MarshalMatches(endpoints, `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  creationTimestamp: null
+  labels:
+    some: label
+  name: pg2-replicas
+  namespace: ns1
+subsets:
+- addresses:
+  - ip: 10.0.0.1
+    nodeName: node1
+    targetRef:
+      kind: Pod
+      name: one-aaaa-0
+      namespace: ns1
+  notReadyAddresses:
+  - ip: 10.0.0.2
+    nodeName: node1
+    targetRef:
+      kind: Pod
+      name: one-bbbb-0
+      namespace: ns1
+  ports:
+  - name: postgres
+    port: 5432
+    protocol: TCP
+- addresses:
+  - ip: 10.0.0.4
+    nodeName: node1
+    targetRef:
+      kind: Pod
+      name: one-dddd-0
+      namespace: ns1
+  ports:
+  - name: postgres
+    port: 6543
+    protocol: TCP
+	`))
 }
 
 func TestPatroniLogSize(t *testing.T) {
@@ -857,3 +976,50 @@ func TestPatroniLogSize(t *testing.T) {
 		assert.Equal(t, recorder.Events[0].Note, "Configured Patroni log storage limit is too small. File size will default to 25M.")
 	})
 }
+
+func TestReconcilePostgresHBASecret(t *testing.T) {
+	ctx := context.Background()
+	_, cc := setupKubernetes(t)
+	require.ParallelCapacity(t, 0)
+
+	reconciler := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := testCluster()
+	cluster.Namespace = setupNamespace(t, cc).Name
+	cluster.Spec.PostgresVersion = 16
+	assert.NilError(t, cc.Create(ctx, cluster))
+
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name + "-pg-hba"}
+
+	// Nothing is written when no user has a bind password.
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster, postgres.HBAs{}))
+	assert.Assert(t, apierrors.IsNotFound(cc.Get(ctx, key, &corev1.Secret{})))
+
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{{
+		Name: "alice",
+		Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{
+				Server: "ldap.example.com", BaseDN: "dc=example",
+				BindPassword: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ldap"},
+					Key:                  "password",
+				},
+			},
+		},
+	}}
+
+	var hbas postgres.HBAs
+	postgres.UserHBAs(cluster.Spec.Users, map[string]string{"alice": "hunter2"}, &hbas)
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster, hbas))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, cc.Get(ctx, key, secret))
+	assert.Assert(t, cmp.Contains(string(secret.Data["pg_hba.conf"]), `ldapbindpasswd="hunter2"`))
+	assert.Equal(t, secret.Labels[naming.LabelCluster], cluster.Name)
+	assert.Assert(t, metav1.IsControlledBy(secret, cluster))
+
+	// The Secret is deleted when no user needs it.
+	cluster.Spec.Users = nil
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster, postgres.HBAs{}))
+	assert.Assert(t, apierrors.IsNotFound(cc.Get(ctx, key, &corev1.Secret{})))
+}
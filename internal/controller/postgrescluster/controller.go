@@ -32,6 +32,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/kubernetes"
 	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
 	"github.com/crunchydata/postgres-operator/internal/pgaudit"
 	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
 	"github.com/crunchydata/postgres-operator/internal/pgbouncer"
@@ -56,8 +57,14 @@ type Reconciler struct {
 		ctx context.Context, namespace, pod, container string,
 		stdin io.Reader, stdout, stderr io.Writer, command ...string,
 	) error
+	PatroniClient func(
+		ctx context.Context, cluster *v1beta1.PostgresCluster, pod *corev1.Pod,
+	) (*patroni.Client, error)
 	Recorder     record.EventRecorder
 	Registration registration.Registration
+
+	// patroniTransports are used by newPatroniClient to reuse connections.
+	patroniTransports patroni.Transports
 }
 
 // +kubebuilder:rbac:groups="",resources="events",verbs={create,patch}
@@ -155,6 +162,14 @@ func (r *Reconciler) Reconcile(
 		return runtime.ErrorWithBackoff(tracing.Escape(span, err))
 	}
 
+	// Decide whether a standby cluster should keep following its source until
+	// it is safe to promote.
+	standbyHeld := holdStandbyPromotion(cluster)
+
+	// Follow spec.standby while promotion is held or this site is demoted,
+	// and stop following it while this site is promoted.
+	standby := standbyInEffect(ctx, cluster)
+
 	var (
 		clusterConfigMap             *corev1.ConfigMap
 		clusterReplicationSecret     *corev1.Secret
@@ -235,15 +250,26 @@ func (r *Reconciler) Reconcile(
 	pgHBAs := postgres.NewHBAs()
 	pgmonitor.PostgreSQLHBAs(cluster, &pgHBAs)
 	pgbouncer.PostgreSQL(cluster, &pgHBAs)
+	r.setPostgresHBAs(cluster, &pgHBAs)
 
 	pgParameters := postgres.NewParameters()
 	pgaudit.PostgreSQLParameters(&pgParameters)
-	pgbackrest.PostgreSQL(cluster, &pgParameters, backupsSpecFound)
+	pgbackrest.PostgreSQL(cluster, &pgParameters, backupsSpecFound, standby)
 	pgmonitor.PostgreSQLParameters(cluster, &pgParameters)
 
 	// Set huge_pages = try if a hugepages resource limit > 0, otherwise set "off"
 	postgres.SetHugePages(cluster, &pgParameters)
 
+	// Set krb_server_keyfile when any user authenticates with GSSAPI.
+	postgres.SetKerberosKeytab(cluster, &pgParameters)
+
+	// Apply the valid parameters of the spec beneath the mandatory ones.
+	r.setPostgresParameters(cluster, &pgParameters)
+
+	if err == nil {
+		err = r.setPostgresUserHBAs(ctx, cluster, &pgHBAs)
+	}
+
 	if err == nil {
 		rootCA, err = r.reconcileRootCertificate(ctx, cluster)
 	}
@@ -270,6 +296,9 @@ func (r *Reconciler) Reconcile(
 	if err == nil {
 		instances, err = r.observeInstances(ctx, cluster)
 	}
+	if err == nil {
+		observePendingRestart(cluster, instances)
+	}
 
 	result := reconcile.Result{}
 
@@ -282,6 +311,29 @@ func (r *Reconciler) Reconcile(
 	if err == nil {
 		err = r.reconcilePatroniSwitchover(ctx, cluster, instances)
 	}
+	if err == nil {
+		var requeue time.Duration
+		if requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, instances); err == nil &&
+			requeue > 0 && (result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+	if err == nil {
+		if requeue := r.reconcileReplicationSlots(ctx, cluster, instances); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+	if err == nil {
+		err = r.reconcileStandbyPromotion(ctx, cluster, instances, standbyHeld)
+	}
+	if err == nil {
+		var requeue time.Duration
+		if requeue, err = r.reconcileDisasterRecovery(ctx, cluster, instances); err == nil &&
+			requeue > 0 && (result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
 	// reconcile the Pod service before reconciling any data source in case it is necessary
 	// to start Pods during data source reconciliation that require network connections (e.g.
 	// if it is necessary to start a dedicated repo host to bootstrap a new cluster using its
@@ -314,6 +366,9 @@ func (r *Reconciler) Reconcile(
 	if err == nil {
 		clusterConfigMap, err = r.reconcileClusterConfigMap(ctx, cluster, pgHBAs, pgParameters)
 	}
+	if err == nil {
+		err = r.reconcilePostgresHBASecret(ctx, cluster, pgHBAs)
+	}
 	if err == nil {
 		clusterReplicationSecret, err = r.reconcileReplicationSecret(ctx, cluster, rootCA)
 	}
@@ -324,7 +379,7 @@ func (r *Reconciler) Reconcile(
 		primaryService, err = r.reconcileClusterPrimaryService(ctx, cluster, patroniLeaderService)
 	}
 	if err == nil {
-		replicaService, err = r.reconcileClusterReplicaService(ctx, cluster)
+		replicaService, err = r.reconcileClusterReplicaService(ctx, cluster, instances)
 	}
 	if err == nil {
 		primaryCertificate, err = r.reconcileClusterCertificate(ctx, rootCA, cluster, primaryService, replicaService)
@@ -333,7 +388,7 @@ func (r *Reconciler) Reconcile(
 		err = r.reconcilePatroniDistributedConfiguration(ctx, cluster)
 	}
 	if err == nil {
-		err = r.reconcilePatroniDynamicConfiguration(ctx, cluster, instances, pgHBAs, pgParameters)
+		err = r.reconcilePatroniDynamicConfiguration(ctx, cluster, instances, pgHBAs, pgParameters, standby)
 	}
 	if err == nil {
 		monitoringSecret, err = r.reconcileMonitoringSecret(ctx, cluster)
@@ -349,15 +404,32 @@ func (r *Reconciler) Reconcile(
 			ctx, cluster, clusterConfigMap, clusterReplicationSecret, rootCA,
 			clusterPodService, instanceServiceAccount, instances, patroniLeaderService,
 			primaryCertificate, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, pgParameters, standby,
 		)
 	}
+	if err == nil {
+		var requeue time.Duration
+		r.reconcileReplicateFrom(cluster)
+		if requeue, err = r.reconcilePatroniTags(ctx, cluster, instances); err == nil &&
+			requeue > 0 && (result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+	if err == nil {
+		r.reconcileFence(cluster, instances)
+		err = r.reconcileFencedPrimary(ctx, cluster, instances)
+	}
 
 	if err == nil {
 		err = r.reconcilePostgresDatabases(ctx, cluster, instances)
 	}
 	if err == nil {
-		err = r.reconcilePostgresUsers(ctx, cluster, instances)
+		var next time.Duration
+		if next, err = r.reconcilePostgresUsers(ctx, cluster, instances, rootCA); err == nil && next > 0 {
+			if result.RequeueAfter == 0 || next < result.RequeueAfter {
+				result.RequeueAfter = next
+			}
+		}
 	}
 
 	if err == nil {
@@ -393,6 +465,13 @@ func (r *Reconciler) Reconcile(
 		// Pods takes precedence.
 		err = r.handlePatroniRestarts(ctx, cluster, instances)
 	}
+	if err == nil {
+		var requeue time.Duration
+		if requeue, err = r.reconcileRestartRequest(ctx, cluster, instances); err == nil &&
+			requeue > 0 && (result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
 
 	// at this point everything reconciled successfully, and we can update the
 	// observedGeneration
@@ -477,6 +556,9 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 			return err
 		}
 	}
+	if r.PatroniClient == nil {
+		r.PatroniClient = r.newPatroniClient
+	}
 
 	return builder.ControllerManagedBy(mgr).
 		For(&v1beta1.PostgresCluster{}).
@@ -494,6 +576,9 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 		Owns(&batchv1.CronJob{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Watches(&corev1.Pod{}, r.watchPods()).
+		Watches(&corev1.Secret{}, r.watchPasswordSecrets()).
+		Watches(&corev1.ConfigMap{}, r.watchDatabaseInitSQLScripts()).
+		Watches(&corev1.Secret{}, r.watchDatabaseInitSQLScripts()).
 		Watches(&appsv1.StatefulSet{},
 			r.controllerRefHandlerFuncs()). // watch all StatefulSets
 		Complete(r)
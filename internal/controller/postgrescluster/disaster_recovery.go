@@ -0,0 +1,314 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/feature"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// ConditionDisasterRecoveryPeerReachable is the type used in a condition to
+	// indicate whether or not the disaster recovery peer of a PostgresCluster
+	// could be read during the last reconcile
+	ConditionDisasterRecoveryPeerReachable = "DisasterRecoveryPeerReachable"
+
+	// disasterRecoveryInterval is how often the peer of a PostgresCluster is
+	// read when nothing else causes a reconcile.
+	disasterRecoveryInterval = 30 * time.Second
+)
+
+// newPeerClient returns a client for the Kubernetes API described by kubeconfig.
+var newPeerClient = func(kubeconfig []byte) (client.Reader, error) {
+	config, err := peerRESTConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(config, client.Options{Scheme: runtime.Scheme})
+	return c, errors.WithStack(err)
+}
+
+// peerRESTConfig returns the server and credentials of the current context in
+// kubeconfig. Only the server, certificate authority, bearer token, and client
+// certificate embedded in kubeconfig are used. Anything that would read files
+// or run commands in the operator is rejected.
+func peerRESTConfig(kubeconfig []byte) (*rest.Config, error) {
+	raw, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	current := raw.Contexts[raw.CurrentContext]
+	if current == nil {
+		return nil, errors.Errorf("kubeconfig context %q not found", raw.CurrentContext)
+	}
+	cluster := raw.Clusters[current.Cluster]
+	if cluster == nil || cluster.Server == "" {
+		return nil, errors.Errorf("kubeconfig cluster %q has no server", current.Cluster)
+	}
+	if cluster.CertificateAuthority != "" {
+		return nil, errors.New("kubeconfig must embed certificate-authority-data rather than refer to a file")
+	}
+
+	config := &rest.Config{Host: cluster.Server}
+	config.CAData = cluster.CertificateAuthorityData
+	config.ServerName = cluster.TLSServerName
+
+	if user := raw.AuthInfos[current.AuthInfo]; user != nil {
+		switch {
+		case user.Exec != nil:
+			return nil, errors.New("kubeconfig must not contain exec credentials")
+		case user.AuthProvider != nil:
+			return nil, errors.New("kubeconfig must not contain an auth-provider")
+		case user.TokenFile != "", user.ClientCertificate != "", user.ClientKey != "":
+			return nil, errors.New("kubeconfig must embed its token and client certificate rather than refer to files")
+		}
+
+		config.BearerToken = user.Token
+		config.CertData = user.ClientCertificateData
+		config.KeyData = user.ClientKeyData
+	}
+
+	return config, nil
+}
+
+// siteDemotionInEffect returns true when cluster should follow its disaster
+// recovery peer regardless of spec.standby.enabled. A site demotion remains in
+// effect until spec.standby.enabled is true.
+func siteDemotionInEffect(ctx context.Context, cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.DisasterRecovery != nil && feature.Enabled(ctx, feature.DisasterRecovery) &&
+		cluster.Status.DisasterRecovery != nil && cluster.Status.DisasterRecovery.SiteDemotion != nil &&
+		cluster.Spec.Standby != nil && !cluster.Spec.Standby.Enabled
+}
+
+// sitePromotionInEffect returns true when cluster should stop following its
+// disaster recovery peer regardless of spec.standby.enabled. A site promotion
+// remains in effect until spec.standby.enabled is false.
+func sitePromotionInEffect(ctx context.Context, cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.DisasterRecovery != nil && feature.Enabled(ctx, feature.DisasterRecovery) &&
+		cluster.Status.DisasterRecovery != nil && cluster.Status.DisasterRecovery.SitePromotion != nil &&
+		cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// reconcileDisasterRecovery observes the disaster recovery peer of cluster.
+// It records a site failover when this standby cluster is promoted and a site
+// demotion when its peer was promoted more recently than this cluster. It
+// records a site promotion when this standby cluster cannot reach its primary
+// peer for spec.disasterRecovery.failoverAfterSeconds. It returns how long to
+// wait before observing the peer again.
+func (r *Reconciler) reconcileDisasterRecovery(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (time.Duration, error) {
+	spec := cluster.Spec.DisasterRecovery
+	if spec == nil || !feature.Enabled(ctx, feature.DisasterRecovery) {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionDisasterRecoveryPeerReachable)
+		cluster.Status.DisasterRecovery = nil
+		return 0, nil
+	}
+	if cluster.Status.DisasterRecovery == nil {
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{}
+	}
+	status := cluster.Status.DisasterRecovery
+	now := metav1.Now()
+
+	// Determine the role of this cluster using the Pod that Patroni has
+	// chosen as its leader.
+	var leader *corev1.Pod
+	for _, instance := range instances.forCluster {
+		if running, known := instance.IsRunning(naming.ContainerDatabase); running &&
+			known && len(instance.Pods) == 1 {
+			pod := instance.Pods[0]
+
+			if patroni.PodIsPrimary(pod) || patroni.PodIsStandbyLeader(pod) {
+				leader = pod
+			}
+		}
+	}
+	if leader != nil && patroni.PodIsPrimary(leader) {
+		if status.Role == "standby" {
+			status.SiteFailover = &v1beta1.DisasterRecoveryEvent{
+				ObservedGeneration: cluster.GetGeneration(), Time: now,
+			}
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "SiteFailover",
+				"This cluster was promoted; peer %s/%s will follow it once it is reachable",
+				spec.Peer.Namespace, spec.Peer.Name)
+		}
+		status.Role = "primary"
+		status.ReplicationLagSeconds = nil
+	}
+	if leader != nil && patroni.PodIsStandbyLeader(leader) {
+		status.Role = "standby"
+	}
+
+	// A site demotion is no longer necessary once the spec says this cluster
+	// is a standby, and a site promotion once the spec says it is not.
+	if cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled {
+		status.SiteDemotion = nil
+	} else {
+		status.SitePromotion = nil
+	}
+
+	// Observe the peer and replication lag at most once per interval. Other
+	// changes to cluster cause reconciles that should not contact the peer.
+	if last := status.LastPeerContactTime; last != nil &&
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionDisasterRecoveryPeerReachable) {
+		if wait := disasterRecoveryInterval - now.Sub(last.Time); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	// A standby reports how far behind it is.
+	if leader != nil && patroni.PodIsStandbyLeader(leader) {
+		status.ReplicationLagSeconds = r.standbyReplicationLag(ctx, leader)
+	}
+
+	peer, err := r.getDisasterRecoveryPeer(ctx, cluster)
+	if err != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ConditionDisasterRecoveryPeerReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Unreachable",
+			Message: err.Error(),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+
+		// Promote this standby cluster when its primary peer has been
+		// unreachable for too long. Do nothing when the peer has never been
+		// reached or when a site promotion is already in effect.
+		if status.Role == "standby" && status.PeerRole == "primary" && status.SitePromotion == nil &&
+			spec.FailoverAfterSeconds != nil && status.LastPeerContactTime != nil &&
+			now.Sub(status.LastPeerContactTime.Time) >= time.Duration(*spec.FailoverAfterSeconds)*time.Second &&
+			cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled {
+
+			status.SitePromotion = &v1beta1.DisasterRecoveryEvent{
+				ObservedGeneration: cluster.GetGeneration(), Time: now,
+			}
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "SitePromotion",
+				"Promoting this cluster; peer %s/%s has been unreachable since %s."+
+					" Set spec.standby.enabled to false to keep it promoted.",
+				spec.Peer.Namespace, spec.Peer.Name, status.LastPeerContactTime.Format(time.RFC3339))
+		}
+
+		return disasterRecoveryInterval, nil
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    ConditionDisasterRecoveryPeerReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reachable",
+		Message: "Read peer " + spec.Peer.Namespace + "/" + spec.Peer.Name,
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+	status.LastPeerContactTime = &now
+	status.PeerRole = ""
+
+	if peer.Status.DisasterRecovery != nil {
+		status.PeerRole = peer.Status.DisasterRecovery.Role
+
+		// Follow the peer again when it was promoted after this cluster was
+		// promoted automatically.
+		if failover, promotion := peer.Status.DisasterRecovery.SiteFailover, status.SitePromotion; failover != nil &&
+			promotion != nil && promotion.Time.Before(&failover.Time) {
+			status.SitePromotion = nil
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "SiteDemotion",
+				"Demoting this cluster; peer %s/%s was promoted at %s",
+				spec.Peer.Namespace, spec.Peer.Name, failover.Time.Format(time.RFC3339))
+		}
+
+		// Follow the peer when it was promoted after this cluster. The standby
+		// spec of this cluster describes how to reach the peer.
+		if failover := peer.Status.DisasterRecovery.SiteFailover; failover != nil &&
+			status.Role == "primary" && status.PeerRole == "primary" && status.SiteDemotion == nil &&
+			(status.SiteFailover == nil || status.SiteFailover.Time.Before(&failover.Time)) &&
+			(cluster.Spec.Standby == nil || !cluster.Spec.Standby.Enabled) {
+
+			if cluster.Spec.Standby == nil ||
+				(cluster.Spec.Standby.Host == "" && cluster.Spec.Standby.RepoName == "") {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "SiteDemotionUnavailable",
+					"Peer %s/%s was promoted, but spec.standby does not describe how to follow it",
+					spec.Peer.Namespace, spec.Peer.Name)
+			} else {
+				status.SiteDemotion = &v1beta1.DisasterRecoveryEvent{
+					ObservedGeneration: cluster.GetGeneration(), Time: now,
+				}
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "SiteDemotion",
+					"Demoting this cluster; peer %s/%s was promoted at %s."+
+						" Set spec.standby.enabled to keep following it.",
+					spec.Peer.Namespace, spec.Peer.Name, failover.Time.Format(time.RFC3339))
+			}
+		}
+	}
+
+	return disasterRecoveryInterval, nil
+}
+
+// getDisasterRecoveryPeer reads the disaster recovery peer of cluster using
+// the kubeconfig in its Secret.
+func (r *Reconciler) getDisasterRecoveryPeer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*v1beta1.PostgresCluster, error) {
+	spec := cluster.Spec.DisasterRecovery.Peer
+
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = cluster.Namespace, spec.KubeconfigSecret.Name
+	err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+
+	var peerClient client.Reader
+	if err == nil {
+		if kubeconfig, ok := secret.Data[spec.KubeconfigSecret.Key]; ok {
+			peerClient, err = newPeerClient(kubeconfig)
+		} else {
+			err = errors.Errorf("key %q not found in Secret %q", spec.KubeconfigSecret.Key, secret.Name)
+		}
+	}
+
+	peer := &v1beta1.PostgresCluster{}
+	if err == nil {
+		err = errors.WithStack(peerClient.Get(ctx,
+			client.ObjectKey{Namespace: spec.Namespace, Name: spec.Name}, peer))
+	}
+	return peer, err
+}
+
+// standbyReplicationLag returns the number of seconds since the last transaction
+// replayed by the PostgreSQL server in pod. It returns nil when that cannot be
+// determined.
+func (r *Reconciler) standbyReplicationLag(ctx context.Context, pod *corev1.Pod) *int64 {
+	exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+		return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+	}
+
+	row, err := queryRow(ctx, postgres.Executor(exec), ``+
+		`SELECT pg_catalog.floor(EXTRACT(epoch FROM`+
+		` pg_catalog.clock_timestamp() - pg_catalog.pg_last_xact_replay_timestamp()));`)
+
+	if err == nil && len(row) == 1 {
+		if seconds, err := strconv.ParseInt(row[0], 10, 64); err == nil {
+			return &seconds
+		}
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/feature"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestSiteDemotionInEffect(t *testing.T) {
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{feature.DisasterRecovery: true}))
+	ctx := feature.NewContext(context.Background(), gate)
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Generation = 2
+	cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: false, RepoName: "repo1"}
+	cluster.Spec.DisasterRecovery = &v1beta1.DisasterRecoverySpec{}
+	cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{}
+	assert.Assert(t, !siteDemotionInEffect(ctx, cluster), "expected nothing without a demotion")
+
+	cluster.Status.DisasterRecovery.SiteDemotion = &v1beta1.DisasterRecoveryEvent{
+		ObservedGeneration: 1, Time: metav1.Now(),
+	}
+	assert.Assert(t, siteDemotionInEffect(ctx, cluster), "expected demotion after the spec changed")
+	assert.Assert(t, !cluster.Spec.Standby.Enabled, "expected no change to spec")
+
+	assert.Assert(t, !siteDemotionInEffect(context.Background(), cluster),
+		"expected nothing when the feature is disabled")
+
+	cluster.Spec.Standby.Enabled = true
+	assert.Assert(t, !siteDemotionInEffect(ctx, cluster), "expected nothing once the spec is a standby")
+}
+
+func TestPeerRESTConfig(t *testing.T) {
+	kubeconfig := func(user string) []byte {
+		return []byte(`
+apiVersion: v1
+kind: Config
+current-context: peer
+contexts:
+- name: peer
+  context: { cluster: east, user: operator }
+clusters:
+- name: east
+  cluster:
+    server: https://east.example.com:6443
+    certificate-authority-data: ` + base64.StdEncoding.EncodeToString([]byte("ca")) + `
+users:
+- name: operator
+  user: ` + user)
+	}
+
+	t.Run("Embedded", func(t *testing.T) {
+		config, err := peerRESTConfig(kubeconfig(`{ token: secret }`))
+		assert.NilError(t, err)
+		assert.Equal(t, config.Host, "https://east.example.com:6443")
+		assert.DeepEqual(t, config.CAData, []byte("ca"))
+		assert.Equal(t, config.BearerToken, "secret")
+	})
+
+	for _, tt := range []struct{ name, user, message string }{
+		{
+			name:    "Exec",
+			user:    `{ exec: { apiVersion: client.authentication.k8s.io/v1, command: sh } }`,
+			message: "exec",
+		},
+		{
+			name:    "AuthProvider",
+			user:    `{ auth-provider: { name: oidc } }`,
+			message: "auth-provider",
+		},
+		{
+			name:    "TokenFile",
+			user:    `{ tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token }`,
+			message: "files",
+		},
+		{
+			name:    "ClientCertificate",
+			user:    `{ client-certificate: /etc/tls.crt, client-key: /etc/tls.key }`,
+			message: "files",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := peerRESTConfig(kubeconfig(tt.user))
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+
+	t.Run("MissingContext", func(t *testing.T) {
+		_, err := peerRESTConfig([]byte(`{ apiVersion: v1, kind: Config, current-context: nope }`))
+		assert.ErrorContains(t, err, `"nope" not found`)
+	})
+}
+
+func TestReconcileDisasterRecovery(t *testing.T) {
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{feature.DisasterRecovery: true}))
+	ctx := feature.NewContext(context.Background(), gate)
+
+	leader := func(role string) *observedInstances {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", "hippo-instance1-abcd-0"
+		pod.Annotations = map[string]string{"status": `{"role":"` + role + `"}`}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &observedInstances{forCluster: []*Instance{{
+			Name: "hippo-instance1-abcd", Pods: []*corev1.Pod{pod},
+		}}}
+	}
+
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = "ns1", "peer-kubeconfig"
+	secret.Data = map[string][]byte{"config": []byte("some-kubeconfig")}
+
+	newCluster := func() *v1beta1.PostgresCluster {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name, cluster.Generation = "ns1", "hippo", 3
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, RepoName: "repo1"}
+		cluster.Spec.DisasterRecovery = &v1beta1.DisasterRecoverySpec{
+			Peer: v1beta1.DisasterRecoveryPeer{
+				KubeconfigSecret: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "peer-kubeconfig"},
+					Key:                  "config",
+				},
+				Namespace: "ns2", Name: "hippo",
+			},
+		}
+		return cluster
+	}
+
+	peerReader := func(t *testing.T, peer *v1beta1.PostgresCluster) {
+		original := newPeerClient
+		t.Cleanup(func() { newPeerClient = original })
+
+		newPeerClient = func(kubeconfig []byte) (client.Reader, error) {
+			assert.Equal(t, string(kubeconfig), "some-kubeconfig")
+			if peer == nil {
+				return nil, errors.New("boom")
+			}
+			return fake.NewClientBuilder().WithScheme(runtime.Scheme).WithObjects(peer).Build(), nil
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Spec.DisasterRecovery = nil
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{Role: "primary"}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionDisasterRecoveryPeerReachable, Status: metav1.ConditionTrue, Reason: "Reachable",
+		})
+
+		r := &Reconciler{}
+		requeue, err := r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, cluster.Status.DisasterRecovery == nil)
+		assert.Equal(t, len(cluster.Status.Conditions), 0)
+	})
+
+	t.Run("StandbyLag", func(t *testing.T) {
+		cluster := newCluster()
+		peer := &v1beta1.PostgresCluster{}
+		peer.Namespace, peer.Name = "ns2", "hippo"
+		peer.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{Role: "primary"}
+		peerReader(t, peer)
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+		r.PodExec = func(
+			_ context.Context, namespace, pod, container string,
+			_ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			assert.Equal(t, namespace, "ns1")
+			assert.Equal(t, pod, "hippo-instance1-abcd-0")
+			assert.Equal(t, container, "database")
+			_, err := stdout.Write([]byte("12\n"))
+			return err
+		}
+
+		requeue, err := r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, disasterRecoveryInterval)
+
+		status := cluster.Status.DisasterRecovery
+		assert.Equal(t, status.Role, "standby")
+		assert.Equal(t, status.PeerRole, "primary")
+		assert.Assert(t, status.ReplicationLagSeconds != nil)
+		assert.Equal(t, *status.ReplicationLagSeconds, int64(12))
+		assert.Assert(t, status.LastPeerContactTime != nil)
+		assert.Assert(t, meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			ConditionDisasterRecoveryPeerReachable))
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		cluster := newCluster()
+		recent := metav1.NewTime(time.Now().Add(-10 * time.Second))
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{
+			LastPeerContactTime: &recent, ReplicationLagSeconds: initialize.Pointer(int64(5)),
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionDisasterRecoveryPeerReachable, Status: metav1.ConditionTrue, Reason: "Reachable",
+		})
+
+		original := newPeerClient
+		t.Cleanup(func() { newPeerClient = original })
+		newPeerClient = func([]byte) (client.Reader, error) {
+			t.Fatal("expected no peer client")
+			return nil, nil
+		}
+
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		r.PodExec = func(
+			context.Context, string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+		) error {
+			t.Fatal("expected no exec")
+			return nil
+		}
+
+		requeue, err := r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+		assert.Assert(t, requeue > 0 && requeue <= 20*time.Second, "got %v", requeue)
+		assert.Equal(t, cluster.Status.DisasterRecovery.Role, "standby")
+		assert.Equal(t, *cluster.Status.DisasterRecovery.ReplicationLagSeconds, int64(5))
+		assert.Equal(t, cluster.Status.DisasterRecovery.LastPeerContactTime, &recent)
+	})
+
+	t.Run("SiteFailover", func(t *testing.T) {
+		cluster := newCluster()
+		recent := metav1.NewTime(time.Now().Add(-time.Minute))
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{LastPeerContactTime: &recent}
+		peerReader(t, nil)
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+		r.PodExec = func(
+			context.Context, string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+		) error {
+			return errors.New("no lag")
+		}
+
+		// The peer is unreachable, but this cluster is not promoted.
+		_, err := r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+		assert.Assert(t, cluster.Status.DisasterRecovery.SiteFailover == nil)
+		assert.Assert(t, cluster.Status.DisasterRecovery.ReplicationLagSeconds == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+
+		reachable := meta.FindStatusCondition(cluster.Status.Conditions, ConditionDisasterRecoveryPeerReachable)
+		assert.Assert(t, reachable != nil)
+		assert.Equal(t, reachable.Status, metav1.ConditionFalse)
+		assert.Equal(t, reachable.Reason, "Unreachable")
+
+		// Someone promoted this cluster.
+		cluster.Generation++
+		cluster.Spec.Standby.Enabled = false
+
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Equal(t, cluster.Status.DisasterRecovery.Role, "primary")
+		assert.Assert(t, cluster.Status.DisasterRecovery.SiteFailover != nil)
+		assert.Equal(t, cluster.Status.DisasterRecovery.SiteFailover.ObservedGeneration, int64(4))
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "SiteFailover")
+
+		// The promotion is recorded once.
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 1)
+	})
+
+	t.Run("SiteDemotion", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Spec.Standby.Enabled = false
+
+		peer := &v1beta1.PostgresCluster{}
+		peer.Namespace, peer.Name = "ns2", "hippo"
+		peer.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{
+			Role: "primary",
+			SiteFailover: &v1beta1.DisasterRecoveryEvent{
+				ObservedGeneration: 1, Time: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		}
+		peerReader(t, peer)
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+
+		_, err := r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+
+		status := cluster.Status.DisasterRecovery
+		assert.Equal(t, status.Role, "primary")
+		assert.Equal(t, status.PeerRole, "primary")
+		assert.Assert(t, status.SiteDemotion != nil)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "SiteDemotion")
+		assert.Assert(t, siteDemotionInEffect(ctx, cluster))
+
+		// The demotion is recorded once and remains after the spec changes.
+		cluster.Generation++
+		status.LastPeerContactTime = nil
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, siteDemotionInEffect(ctx, cluster))
+
+		// The demotion is no longer necessary once the spec is a standby.
+		cluster.Spec.Standby.Enabled = true
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+		assert.Assert(t, status.SiteDemotion == nil)
+		assert.Equal(t, status.Role, "standby")
+	})
+
+	t.Run("SitePromotion", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Spec.DisasterRecovery.FailoverAfterSeconds = initialize.Pointer(int32(60))
+		cluster.Status.Patroni.SystemIdentifier = "1234"
+		cluster.Default()
+		earlier := metav1.NewTime(time.Now().Add(-30 * time.Second))
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{
+			LastPeerContactTime: &earlier, PeerRole: "primary",
+		}
+		peerReader(t, nil)
+
+		var configuration map[string]any
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+		r.PatroniClient = setupPatroniClient(t, func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, req.Method+" "+req.URL.Path, "PUT /config")
+			assert.NilError(t, json.NewDecoder(req.Body).Decode(&configuration))
+		})
+		r.PodExec = func(
+			context.Context, string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+		) error {
+			return errors.New("no lag")
+		}
+
+		// The peer has not been unreachable for long enough.
+		_, err := r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+		assert.Assert(t, cluster.Status.DisasterRecovery.SitePromotion == nil)
+		assert.Assert(t, standbyInEffect(ctx, cluster))
+		assert.Equal(t, len(recorder.Events), 0)
+		assert.NilError(t, r.reconcilePatroniDynamicConfiguration(ctx, cluster,
+			leader("standby_leader"), postgres.NewHBAs(), postgres.NewParameters(),
+			standbyInEffect(ctx, cluster)))
+		assert.Assert(t, configuration["standby_cluster"] != nil, "got %v", configuration)
+
+		earlier = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("standby_leader"))
+		assert.NilError(t, err)
+
+		status := cluster.Status.DisasterRecovery
+		assert.Assert(t, status.SitePromotion != nil)
+		assert.Equal(t, status.SitePromotion.ObservedGeneration, int64(3))
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "SitePromotion")
+		assert.Assert(t, cluster.Spec.Standby.Enabled, "expected no change to spec")
+
+		// Patroni is told to stop following the peer.
+		assert.Assert(t, !standbyInEffect(ctx, cluster))
+		configuration = nil
+		assert.NilError(t, r.reconcilePatroniDynamicConfiguration(ctx, cluster,
+			leader("standby_leader"), postgres.NewHBAs(), postgres.NewParameters(),
+			standbyInEffect(ctx, cluster)))
+		assert.Assert(t, configuration != nil)
+		assert.Assert(t, configuration["standby_cluster"] == nil, "got %v", configuration)
+
+		// The promotion is recorded once and remains after the spec changes.
+		cluster.Generation++
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[1].Reason, "SiteFailover")
+		assert.Equal(t, status.Role, "primary")
+		assert.Assert(t, sitePromotionInEffect(ctx, cluster))
+
+		// The promotion is no longer necessary once the spec is not a standby.
+		cluster.Spec.Standby.Enabled = false
+		_, err = r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Assert(t, status.SitePromotion == nil)
+		assert.Assert(t, !standbyInEffect(ctx, cluster))
+	})
+
+	t.Run("SitePromotionFollowsPeer", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{
+			Role: "primary",
+			SitePromotion: &v1beta1.DisasterRecoveryEvent{
+				ObservedGeneration: 3, Time: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		}
+
+		peer := &v1beta1.PostgresCluster{}
+		peer.Namespace, peer.Name = "ns2", "hippo"
+		peer.Status.DisasterRecovery = &v1beta1.DisasterRecoveryStatus{
+			Role: "primary",
+			SiteFailover: &v1beta1.DisasterRecoveryEvent{
+				ObservedGeneration: 1, Time: metav1.NewTime(time.Now().Add(-time.Minute)),
+			},
+		}
+		peerReader(t, peer)
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+
+		_, err := r.reconcileDisasterRecovery(ctx, cluster, leader("master"))
+		assert.NilError(t, err)
+		assert.Assert(t, cluster.Status.DisasterRecovery.SitePromotion == nil)
+		assert.Assert(t, standbyInEffect(ctx, cluster))
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "SiteDemotion")
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.Spec.DisasterRecovery.Peer.KubeconfigSecret.Key = "other"
+
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		r.Client = fake.NewClientBuilder().WithObjects(secret.DeepCopy()).Build()
+
+		_, err := r.reconcileDisasterRecovery(ctx, cluster, &observedInstances{})
+		assert.NilError(t, err)
+
+		reachable := meta.FindStatusCondition(cluster.Status.Conditions, ConditionDisasterRecoveryPeerReachable)
+		assert.Assert(t, reachable != nil)
+		assert.Equal(t, reachable.Status, metav1.ConditionFalse)
+		assert.Assert(t, strings.Contains(reachable.Message, `key "other" not found`), reachable.Message)
+	})
+}
@@ -6,6 +6,8 @@ package postgrescluster
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"testing"
@@ -23,6 +25,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -53,6 +56,26 @@ func init() {
 	}
 }
 
+// setupPatroniClient returns a function that can be used as
+// [Reconciler.PatroniClient]. Every member of the Patroni cluster is served
+// by handler.
+func setupPatroniClient(t testing.TB, handler http.HandlerFunc) func(
+	context.Context, *v1beta1.PostgresCluster, *corev1.Pod,
+) (*patroni.Client, error) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return func(_ context.Context, _ *v1beta1.PostgresCluster, pod *corev1.Pod) (*patroni.Client, error) {
+		return &patroni.Client{
+			HTTP:   server.Client(),
+			Member: pod.Name,
+			URL:    func(string) string { return server.URL },
+		}, nil
+	}
+}
+
 // setupKubernetes starts or connects to a Kubernetes API and returns a client
 // that uses it. See [require.Kubernetes] for more details.
 func setupKubernetes(t testing.TB) (*rest.Config, client.Client) {
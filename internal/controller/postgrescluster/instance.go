@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,11 +18,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -59,6 +63,34 @@ func (i Instance) IsAvailable() (available bool, known bool) {
 	return ready && !terminating, knownReady && knownTerminating
 }
 
+// IsFenced returns whether or not this instance is out of service according
+// to its spec and its Pod. A fenced instance that should stop PostgreSQL is
+// fenced once it has no Pod. The primary is never fenced; another instance
+// must take over first.
+func (i Instance) IsFenced() (fenced bool, known bool) {
+	var fence *v1beta1.InstanceFence
+	if i.Spec != nil {
+		fence = i.Spec.FencedInstance(i.Name)
+	}
+	if fence == nil {
+		return false, true
+	}
+	if initialize.FromPointer(fence.StopPostgres) {
+		return len(i.Pods) == 0, true
+	}
+	if len(i.Pods) != 1 {
+		return false, false
+	}
+	if primary, _ := i.IsPrimary(); primary {
+		return false, true
+	}
+
+	// Patroni reports the tags it has applied; see [Reconciler.reconcilePatroniTags].
+	tags := patroni.PodTags(i.Pods[0])
+
+	return tags["nofailover"] == true && tags["noloadbalance"] == true, true
+}
+
 // IsPrimary returns whether or not this instance is the Patroni leader.
 func (i Instance) IsPrimary() (primary bool, known bool) {
 	if len(i.Pods) != 1 {
@@ -342,6 +374,18 @@ func (r *Reconciler) observeInstances(
 			if matches, known := instance.PodMatchesPodTemplate(); known && matches {
 				status.UpdatedReplicas++
 			}
+			if fenced, known := instance.IsFenced(); known && fenced {
+				status.FencedInstances = append(status.FencedInstances, instance.Name)
+			}
+			for _, pod := range instance.Pods {
+				// Report the smallest configured delay of any running pod.
+				if value, ok := pod.Annotations[naming.RecoveryMinApplyDelay]; ok {
+					if seconds, err := strconv.ParseInt(value, 10, 32); err == nil &&
+						(status.ConfiguredApplyDelaySeconds == nil || int32(seconds) < *status.ConfiguredApplyDelaySeconds) {
+						status.ConfiguredApplyDelaySeconds = initialize.Int32(int32(seconds))
+					}
+				}
+			}
 			if autogrow {
 				// Store desired pgData volume size for each instance Pod.
 				// The 'suggested-pgdata-pvc-size' annotation value is stored in the PostgresCluster
@@ -591,6 +635,8 @@ func (r *Reconciler) reconcileInstanceSets(
 	clusterVolumes []*corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
 	backupsSpecFound bool,
+	pgParameters postgres.Parameters,
+	standby bool,
 ) error {
 
 	// Go through the observed instances and check if a primary has been determined.
@@ -616,23 +662,29 @@ func (r *Reconciler) reconcileInstanceSets(
 		numInstancePods += len(instances.forCluster[i].Pods)
 	}
 
+	// Determine the instance sets that replicate from other sets. Those that
+	// cannot stream from the primary instead; see [Reconciler.reconcilePatroniTags].
+	upstreams, _ := instanceSetUpstreams(cluster)
+
 	// Range over instance sets to scale up and ensure that each set has
 	// at least the number of replicas defined in the spec. The set can
 	// have more replicas than defined
 	for i := range cluster.Spec.InstanceSets {
 		set := &cluster.Spec.InstanceSets[i]
+		setParameters, _ := instanceSetParameters(cluster, set, pgParameters.Mandatory)
 		_, err := r.scaleUpInstances(
 			ctx, cluster, instances, set,
+			replicateFromMember(instances, upstreams[set.Name]),
 			clusterConfigMap, clusterReplicationSecret,
 			rootCA, clusterPodService, instanceServiceAccount,
 			patroniLeaderService, primaryCertificate,
 			findAvailableInstanceNames(*set, instances, clusterVolumes),
 			numInstancePods, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, setParameters, standby,
 		)
 
 		if err == nil {
-			err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, set)
+			err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, instances, set)
 		}
 		if err != nil {
 			return err
@@ -662,6 +714,165 @@ func (r *Reconciler) reconcileInstanceSets(
 	return err
 }
 
+// instanceSetUpstreams returns the name of the instance set from which each
+// set of cluster replicates. Sets that refer to themselves, to sets that do
+// not exist, or to one another in a cycle are left out and returned as errors.
+func instanceSetUpstreams(cluster *v1beta1.PostgresCluster) (map[string]string, field.ErrorList) {
+	path := field.NewPath("spec", "instances")
+	errs := field.ErrorList{}
+	upstreams := make(map[string]string)
+
+	names := sets.New[string]()
+	for i := range cluster.Spec.InstanceSets {
+		names.Insert(cluster.Spec.InstanceSets[i].Name)
+	}
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		if set.ReplicateFrom == "" {
+			continue
+		}
+		if set.ReplicateFrom == set.Name || !names.Has(set.ReplicateFrom) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("replicateFrom"),
+				set.ReplicateFrom, "must be the name of another instance set"))
+			continue
+		}
+		upstreams[set.Name] = set.ReplicateFrom
+	}
+
+	// Follow each chain of upstreams. A set is in a cycle when its chain
+	// returns to it.
+	cycles := sets.New[string]()
+	for name := range upstreams {
+		seen := sets.New(name)
+		for next, ok := upstreams[name]; ok; next, ok = upstreams[next] {
+			if next == name {
+				cycles.Insert(name)
+				break
+			}
+			if seen.Has(next) {
+				break
+			}
+			seen.Insert(next)
+		}
+	}
+	for i := range cluster.Spec.InstanceSets {
+		if set := &cluster.Spec.InstanceSets[i]; cycles.Has(set.Name) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("replicateFrom"),
+				set.ReplicateFrom, "must not replicate from instance sets in a cycle"))
+			delete(upstreams, set.Name)
+		}
+	}
+
+	return upstreams, errs
+}
+
+// replicateFromMember returns the name of the Patroni member from which
+// replicas should stream when their set replicates from the upstream set.
+// It returns an empty string when upstream is empty or has no instances.
+func replicateFromMember(observed *observedInstances, upstream string) string {
+	var name string
+	if upstream != "" {
+		// Choose the same instance every time so that Pods are not recreated
+		// as instances come and go. The Pod of an instance has the name of its
+		// StatefulSet and an ordinal, and Patroni uses that as the member name.
+		for _, instance := range observed.bySet[upstream] {
+			if instance.Runner != nil && (name == "" || instance.Name < name) {
+				name = instance.Name
+			}
+		}
+	}
+	if name != "" {
+		return name + "-0"
+	}
+	return name
+}
+
+// ConditionReplicateFromValid is the type used in a condition to indicate
+// whether or not the replicateFrom fields of instance sets can be applied.
+const ConditionReplicateFromValid = "ReplicateFromValid"
+
+// reconcileReplicateFrom reports the replicateFrom fields of instance sets in
+// a condition. See [Reconciler.reconcilePatroniTags] for how they are applied.
+func (r *Reconciler) reconcileReplicateFrom(cluster *v1beta1.PostgresCluster) {
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicateFromValid)
+
+	var configured bool
+	for i := range cluster.Spec.InstanceSets {
+		configured = configured || cluster.Spec.InstanceSets[i].ReplicateFrom != ""
+	}
+	if !configured {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionReplicateFromValid)
+		return
+	}
+
+	_, invalid := instanceSetUpstreams(cluster)
+	condition := metav1.Condition{
+		Type:   ConditionReplicateFromValid,
+		Status: metav1.ConditionTrue,
+		Reason: "Valid",
+
+		ObservedGeneration: cluster.GetGeneration(),
+	}
+	if len(invalid) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidReplicateFrom"
+		condition.Message = invalid.ToAggregate().Error()
+
+		if previous == nil || previous.Status != condition.Status || previous.Message != condition.Message {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidReplicateFrom", condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// patroniTagsInterval is how long to wait before checking that Patroni has
+// applied a change to its tags. Kubernetes updates the files of ConfigMap
+// volumes periodically.
+const patroniTagsInterval = 30 * time.Second
+
+// reconcilePatroniTags reloads Patroni members that report tags other than
+// those of their instance. Patroni applies tags when it reloads its
+// configuration file, so instances keep running as their upstream, fencing,
+// and failover settings change. It returns how long to wait before checking
+// again.
+func (r *Reconciler) reconcilePatroniTags(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (time.Duration, error) {
+	upstreams, _ := instanceSetUpstreams(cluster)
+
+	// Compare the tags of every running member to its intent.
+	var outdated []string
+	var running *corev1.Pod
+	for _, instance := range instances.forCluster {
+		if instance.Spec == nil || len(instance.Pods) != 1 {
+			continue
+		}
+		if ok, known := instance.IsRunning(naming.ContainerDatabase); !ok || !known {
+			continue
+		}
+		running = instance.Pods[0]
+
+		if matches, known := patroni.PodHasTags(running, instance.Spec,
+			replicateFromMember(instances, upstreams[instance.Spec.Name]),
+			instance.Spec.FencedInstance(instance.Name) != nil,
+		); known && !matches {
+			outdated = append(outdated, running.Name)
+		}
+	}
+	if len(outdated) == 0 {
+		return 0, nil
+	}
+
+	api, err := r.PatroniClient(ctx, cluster, running)
+	for i := 0; err == nil && i < len(outdated); i++ {
+		err = api.Reload(ctx, outdated[i])
+	}
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return patroniTagsInterval, nil
+}
+
 // +kubebuilder:rbac:groups="policy",resources="poddisruptionbudgets",verbs={list}
 
 // cleanupPodDisruptionBudgets removes pdbs that do not have an
@@ -788,6 +999,14 @@ func (r *Reconciler) rolloutInstance(
 	primary, known := instance.IsPrimary()
 	primary = primary && known
 
+	// Count the other instances that Patroni may promote.
+	var candidates int
+	for _, other := range instances.forCluster {
+		if other != instance && (other.Spec == nil || patroni.InstanceSetCanFailover(other.Spec)) {
+			candidates++
+		}
+	}
+
 	// When the cluster has more than one instance participating in failover,
 	// perform a controlled switchover to one of those instances. Patroni will
 	// choose the best candidate and demote the primary. It stops PostgreSQL
@@ -799,14 +1018,21 @@ func (r *Reconciler) rolloutInstance(
 	//
 	// NOTE(cbandy): The StatefulSet controlling this Pod reflects this change
 	// in its Status and triggers another reconcile.
-	if primary && len(instances.forCluster) > 1 {
+	if primary && candidates > 0 {
 		ctx, span := tracing.Start(ctx, "patroni-change-primary")
 		defer span.End()
 
-		success, err := patroni.Executor(exec).ChangePrimaryAndWait(ctx, pod.Name, "")
+		api, err := r.PatroniClient(ctx, cluster, pod)
+		var success bool
+		if err == nil {
+			success, err = api.ChangePrimaryAndWait(ctx, pod.Name, "")
+		}
 		if err = errors.WithStack(err); err == nil && !success {
 			err = errors.New("unable to switchover")
 		}
+		if err == nil {
+			cluster.Status.Patroni.LeaderChangeRequested = instance.Name
+		}
 
 		return tracing.Escape(span, err)
 	}
@@ -894,6 +1120,12 @@ func (r *Reconciler) rolloutInstances(
 	ctx, span := tracing.Start(ctx, "rollout-instances")
 	defer span.End()
 
+	// Redeploying a primary while Patroni is paused causes downtime rather
+	// than a switchover. Wait for Patroni to resume.
+	if patroniPaused(cluster) {
+		return nil
+	}
+
 	for _, set := range cluster.Spec.InstanceSets {
 		numSpecified += int(*set.Replicas)
 	}
@@ -905,6 +1137,14 @@ func (r *Reconciler) rolloutInstances(
 			continue
 		}
 
+		// Skip instances that are fenced and stopped. They should not be
+		// redeployed and are not expected to be available.
+		if fence := instance.Spec.FencedInstance(instance.Name); fence != nil &&
+			initialize.FromPointer(fence.StopPostgres) && len(instance.Pods) == 0 {
+			numSpecified--
+			continue
+		}
+
 		// Skip instances that are or might be terminating. They should not be
 		// redeployed right now and cannot count toward availability.
 		if terminating, known := instance.IsTerminating(); !known || terminating {
@@ -952,6 +1192,112 @@ func (r *Reconciler) rolloutInstances(
 	return tracing.Escape(span, err)
 }
 
+// ConditionFenceValid is the type used in a condition to indicate whether or
+// not the fence fields of instance sets name instances of those sets.
+const ConditionFenceValid = "FenceValid"
+
+// reconcileFence reports the fence fields of instance sets in a condition. A
+// fence that names no instance of its set has no effect.
+func (r *Reconciler) reconcileFence(
+	cluster *v1beta1.PostgresCluster, observed *observedInstances,
+) {
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, ConditionFenceValid)
+
+	var configured bool
+	for i := range cluster.Spec.InstanceSets {
+		configured = configured || len(cluster.Spec.InstanceSets[i].Fence) > 0
+	}
+	if !configured {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionFenceValid)
+		return
+	}
+
+	path := field.NewPath("spec", "instances")
+	invalid := field.ErrorList{}
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		for j := range set.Fence {
+			if !slices.ContainsFunc(observed.bySet[set.Name], func(instance *Instance) bool {
+				return instance.Name == set.Fence[j].Name
+			}) {
+				invalid = append(invalid, field.NotFound(
+					path.Index(i).Child("fence").Index(j).Child("name"), set.Fence[j].Name))
+			}
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   ConditionFenceValid,
+		Status: metav1.ConditionTrue,
+		Reason: "Valid",
+
+		ObservedGeneration: cluster.GetGeneration(),
+	}
+	if len(invalid) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidFence"
+		condition.Message = invalid.ToAggregate().Error()
+
+		if previous == nil || previous.Status != condition.Status || previous.Message != condition.Message {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidFence", condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// reconcileFencedPrimary changes the primary when spec says to fence it.
+// Patroni chooses another instance that is not fenced. Nothing happens when
+// no other instance can take over; the primary remains in service.
+func (r *Reconciler) reconcileFencedPrimary(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	// Patroni does not change its leader on its own while paused.
+	if patroniPaused(cluster) {
+		return nil
+	}
+
+	var primary *Instance
+	var candidates int
+	for _, instance := range instances.forCluster {
+		running, known := instance.IsRunning(naming.ContainerDatabase)
+		if !running || !known || len(instance.Pods) != 1 {
+			continue
+		}
+		if isPrimary, _ := instance.IsPrimary(); isPrimary {
+			if instance.Spec != nil && instance.Spec.FencedInstance(instance.Name) != nil {
+				primary = instance
+			}
+		} else if instance.Spec != nil && patroni.InstanceSetCanFailover(instance.Spec) &&
+			instance.Spec.FencedInstance(instance.Name) == nil {
+			candidates++
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+	if candidates == 0 {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "FencedPrimary",
+			"Unable to fence primary instance %q; no other instance can take over", primary.Name)
+		return nil
+	}
+
+	pod := primary.Pods[0]
+	api, err := r.PatroniClient(ctx, cluster, pod)
+	if err == nil {
+		var success bool
+		success, err = api.ChangePrimaryAndWait(ctx, pod.Name, "")
+		if err == nil && !success {
+			err = errors.New("unable to switchover")
+		}
+	}
+	if err == nil {
+		cluster.Status.Patroni.LeaderChangeRequested = primary.Name
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "FencedPrimary",
+			"Changed the primary so that instance %q can be fenced", primary.Name)
+	}
+	return errors.WithStack(err)
+}
+
 // scaleDownInstances removes extra instances from a cluster until it matches
 // the spec. This function can delete the primary instance and force the
 // cluster to failover under two conditions:
@@ -1051,6 +1397,7 @@ func (r *Reconciler) scaleUpInstances(
 	cluster *v1beta1.PostgresCluster,
 	observed *observedInstances,
 	set *v1beta1.PostgresInstanceSetSpec,
+	replicateFrom string,
 	clusterConfigMap *corev1.ConfigMap,
 	clusterReplicationSecret *corev1.Secret,
 	rootCA *pki.RootCertificateAuthority,
@@ -1063,6 +1410,8 @@ func (r *Reconciler) scaleUpInstances(
 	clusterVolumes []*corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
 	backupsSpecFound bool,
+	pgParameters postgres.Parameters,
+	standby bool,
 ) ([]*appsv1.StatefulSet, error) {
 	log := logging.FromContext(ctx)
 
@@ -1104,12 +1453,12 @@ func (r *Reconciler) scaleUpInstances(
 	var err error
 	for i := range instances {
 		err = r.reconcileInstance(
-			ctx, cluster, observed.byName[instances[i].Name], set,
+			ctx, cluster, observed.byName[instances[i].Name], set, replicateFrom,
 			clusterConfigMap, clusterReplicationSecret,
 			rootCA, clusterPodService, instanceServiceAccount,
 			patroniLeaderService, primaryCertificate, instances[i],
 			numInstancePods, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, pgParameters, standby,
 		)
 	}
 	if err == nil {
@@ -1121,13 +1470,15 @@ func (r *Reconciler) scaleUpInstances(
 
 // +kubebuilder:rbac:groups="apps",resources="statefulsets",verbs={create,patch}
 
-// reconcileInstance writes instance according to spec of cluster.
+// reconcileInstance writes instance according to spec of cluster. The
+// specified parameters of pgParameters are those of spec.
 // See Reconciler.reconcileInstanceSet.
 func (r *Reconciler) reconcileInstance(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
 	observed *Instance,
 	spec *v1beta1.PostgresInstanceSetSpec,
+	replicateFrom string,
 	clusterConfigMap *corev1.ConfigMap,
 	clusterReplicationSecret *corev1.Secret,
 	rootCA *pki.RootCertificateAuthority,
@@ -1140,6 +1491,8 @@ func (r *Reconciler) reconcileInstance(
 	clusterVolumes []*corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
 	backupsSpecFound bool,
+	pgParameters postgres.Parameters,
+	standby bool,
 ) error {
 	log := logging.FromContext(ctx).WithValues("instance", instance.Name)
 	ctx = logging.NewContext(ctx, log)
@@ -1153,6 +1506,15 @@ func (r *Reconciler) reconcileInstance(
 		generateInstanceStatefulSetIntent(ctx, cluster, spec,
 			clusterPodService.Name, instanceServiceAccount.Name, instance,
 			numInstancePods)
+
+		// Keep a fenced primary running until another instance takes over.
+		// See [Reconciler.reconcileFencedPrimary].
+		if observed != nil && spec.FencedInstance(instance.Name) != nil &&
+			(cluster.Spec.Shutdown == nil || !*cluster.Spec.Shutdown) {
+			if primary, _ := observed.IsPrimary(); primary {
+				instance.Spec.Replicas = initialize.Int32(1)
+			}
+		}
 	}
 
 	var (
@@ -1164,7 +1526,8 @@ func (r *Reconciler) reconcileInstance(
 	)
 
 	if err == nil {
-		instanceConfigMap, err = r.reconcileInstanceConfigMap(ctx, cluster, spec, instance)
+		instanceConfigMap, err = r.reconcileInstanceConfigMap(
+			ctx, cluster, spec, pgParameters, replicateFrom, standby, instance)
 	}
 	if err == nil {
 		instanceCertificates, err = r.reconcileInstanceCertificates(
@@ -1193,7 +1556,7 @@ func (r *Reconciler) reconcileInstance(
 
 		err = patroni.InstancePod(
 			ctx, cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
-			spec, instanceCertificates, instanceConfigMap, &instance.Spec.Template)
+			spec, pgParameters, instanceCertificates, instanceConfigMap, &instance.Spec.Template)
 	}
 
 	// Add pgMonitor resources to the instance Pod spec
@@ -1329,6 +1692,12 @@ func generateInstanceStatefulSetIntent(_ context.Context,
 		sts.Spec.Replicas = initialize.Int32(1)
 	}
 
+	// A fenced instance may be stopped while keeping its volumes.
+	if fence := spec.FencedInstance(sts.Name); fence != nil &&
+		initialize.FromPointer(fence.StopPostgres) {
+		sts.Spec.Replicas = initialize.Int32(0)
+	}
+
 	// Restart containers any time they stop, die, are killed, etc.
 	// - https://docs.k8s.io/concepts/workloads/pods/pod-lifecycle/#restart-policy
 	sts.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
@@ -1374,10 +1743,11 @@ func addPGBackRestToInstancePodSpec(
 // +kubebuilder:rbac:groups="",resources="configmaps",verbs={create,patch}
 
 // reconcileInstanceConfigMap writes the ConfigMap that contains generated
-// files (etc) that apply to instance of cluster.
+// files (etc) that apply to instance of cluster. The instance follows
+// spec.standby only when standby is true, regardless of spec.standby.enabled.
 func (r *Reconciler) reconcileInstanceConfigMap(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, spec *v1beta1.PostgresInstanceSetSpec,
-	instance *appsv1.StatefulSet,
+	pgParameters postgres.Parameters, replicateFrom string, standby bool, instance *appsv1.StatefulSet,
 ) (*corev1.ConfigMap, error) {
 	instanceConfigMap := &corev1.ConfigMap{ObjectMeta: naming.InstanceConfigMap(instance)}
 	instanceConfigMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
@@ -1398,7 +1768,8 @@ func (r *Reconciler) reconcileInstanceConfigMap(
 		})
 
 	if err == nil {
-		err = patroni.InstanceConfigMap(ctx, cluster, spec, instanceConfigMap)
+		err = patroni.InstanceConfigMap(ctx, cluster, spec, pgParameters, replicateFrom,
+			spec.FencedInstance(instance.Name) != nil, standby, instanceConfigMap)
 	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, instanceConfigMap))
@@ -1479,13 +1850,26 @@ func (r *Reconciler) reconcileInstanceCertificates(
 func (r *Reconciler) reconcileInstanceSetPodDisruptionBudget(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
+	instances *observedInstances,
 	spec *v1beta1.PostgresInstanceSetSpec,
 ) error {
 	if spec.Replicas == nil {
 		// Replicas should always have a value because of defaults in the spec
 		return errors.New("Replicas should be defined")
 	}
-	minAvailable := getMinAvailable(spec.MinAvailable, *spec.Replicas)
+
+	// Fenced instances are out of service; leave them out of the budget. An
+	// instance of the spec is fenced only after it is observed to be, so the
+	// primary stays in the budget until another instance takes over.
+	replicas := *spec.Replicas
+	var fenced []string
+	for _, instance := range instances.bySet[spec.Name] {
+		if ok, known := instance.IsFenced(); known && ok {
+			fenced = append(fenced, instance.Name)
+		}
+	}
+	replicas = max(0, replicas-int32(len(fenced))) //nolint:gosec
+	minAvailable := getMinAvailable(spec.MinAvailable, replicas)
 
 	meta := naming.InstanceSet(cluster, spec)
 	meta.Labels = naming.Merge(cluster.Spec.Metadata.GetLabelsOrNil(),
@@ -1498,13 +1882,22 @@ func (r *Reconciler) reconcileInstanceSetPodDisruptionBudget(
 		spec.Metadata.GetAnnotationsOrNil())
 
 	selector := naming.ClusterInstanceSet(cluster.Name, spec.Name)
+	if len(fenced) > 0 {
+		sort.Strings(fenced)
+		selector.MatchExpressions = append(selector.MatchExpressions,
+			metav1.LabelSelectorRequirement{
+				Key:      naming.LabelInstance,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   fenced,
+			})
+	}
 	pdb, err := r.generatePodDisruptionBudget(cluster, meta, minAvailable, selector)
 
 	// If 'minAvailable' is set to '0', we will not reconcile the PDB. If one
 	// already exists, we will remove it.
 	var scaled int
 	if err == nil {
-		scaled, err = intstr.GetScaledValueFromIntOrPercent(minAvailable, int(*spec.Replicas), true)
+		scaled, err = intstr.GetScaledValueFromIntOrPercent(minAvailable, int(replicas), true)
 	}
 	if err == nil && scaled <= 0 {
 		err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(pdb), pdb))
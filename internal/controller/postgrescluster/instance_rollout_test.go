@@ -7,6 +7,7 @@ package postgrescluster
 import (
 	"context"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -18,7 +19,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -118,44 +118,35 @@ func TestReconcilerRolloutInstance(t *testing.T) {
 		observed := &observedInstances{forCluster: instances}
 
 		t.Run("Success", func(t *testing.T) {
-			execCalls := 0
+			calls := 0
 			reconciler := &Reconciler{}
-			reconciler.PodExec = func(
-				ctx context.Context, namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
-			) error {
-				execCalls++
-
-				// Execute on the Pod.
-				assert.Equal(t, namespace, "ns1")
-				assert.Equal(t, pod, "the-pod")
-				assert.Equal(t, container, "database")
+			reconciler.PatroniClient = setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
 
 				// A switchover to any viable candidate.
-				assert.DeepEqual(t, command[:2], []string{"patronictl", "switchover"})
-				assert.Assert(t, sets.NewString(command...).Has("--primary=the-pod"))
-				assert.Assert(t, sets.NewString(command...).Has("--candidate="))
-
-				// Indicate success through stdout.
-				_, _ = stdout.Write([]byte("switched over"))
+				assert.Equal(t, r.Method, http.MethodPost)
+				assert.Equal(t, r.URL.Path, "/switchover")
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, string(body), `{"leader":"the-pod"}`)
 
-				return nil
-			}
+				// Indicate success through the response.
+				_, _ = w.Write([]byte(`Successfully switched over to "other"`))
+			})
 
 			assert.NilError(t, reconciler.rolloutInstance(ctx, cluster, observed, instances[0]))
-			assert.Equal(t, execCalls, 1, "expected PodExec to be called")
+			assert.Equal(t, calls, 1, "expected Patroni to be called")
 		})
 
 		t.Run("Failure", func(t *testing.T) {
 			reconciler := &Reconciler{}
-			reconciler.PodExec = func(
-				ctx context.Context, _, _, _ string, _ io.Reader, _, _ io.Writer, _ ...string,
-			) error {
-				// Nothing useful in stdout.
-				return nil
-			}
+			reconciler.PatroniClient = setupPatroniClient(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte("candidate name does not match with sync_standby"))
+			})
 
 			err := reconciler.rolloutInstance(ctx, cluster, observed, instances[0])
 			assert.ErrorContains(t, err, "switchover")
+			assert.ErrorContains(t, err, "412")
 		})
 	})
 }
@@ -284,6 +275,69 @@ func TestReconcilerRolloutInstances(t *testing.T) {
 		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
 		assert.Equal(t, len(redeploys), 1)
 		assert.Equal(t, redeploys[0].Name, "one")
+
+		t.Run("Paused", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Status.Patroni.Paused = initialize.Bool(true)
+
+			assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed,
+				func(context.Context, *Instance) error {
+					t.Fatal("expected no redeploys")
+					return nil
+				}))
+		})
+	})
+
+	// One healthy instance that does not match its PodTemplate and one fenced
+	// instance that is stopped.
+	t.Run("FencedStopped", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+			Name: "00", Replicas: initialize.Int32(2),
+			Fence: []v1beta1.InstanceFence{{Name: "two", StopPostgres: initialize.Bool(true)}},
+		}}
+		instances := []*Instance{
+			{
+				Name: "one",
+				Spec: &cluster.Spec.InstanceSets[0],
+				Pods: []*corev1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"controller-revision-hash":               "beta",
+							"postgres-operator.crunchydata.com/role": "master",
+						},
+					},
+					Status: corev1.PodStatus{
+						Conditions: []corev1.PodCondition{{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionTrue,
+						}},
+					},
+				}},
+				Runner: &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Generation: 1,
+					},
+					Status: appsv1.StatefulSetStatus{
+						ObservedGeneration: 1,
+						UpdateRevision:     "gamma",
+					},
+				},
+			},
+			{
+				Name:   "two",
+				Spec:   &cluster.Spec.InstanceSets[0],
+				Runner: &appsv1.StatefulSet{},
+			},
+		}
+		observed := &observedInstances{forCluster: instances}
+
+		var redeploys []*Instance
+
+		ctx := logSpanAttributes(t, ctx)
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1)
+		assert.Equal(t, redeploys[0].Name, "one")
 	})
 
 	// Two ready instances do not match PodTemplate, no primary.
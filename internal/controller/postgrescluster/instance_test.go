@@ -8,6 +8,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -21,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -135,6 +138,61 @@ func TestInstanceIsWritable(t *testing.T) {
 	assert.Assert(t, !writable)
 }
 
+func TestInstanceIsFenced(t *testing.T) {
+	instance := Instance{Name: "some-instance"}
+
+	// No spec
+	fenced, known := instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, !fenced)
+
+	// Not fenced
+	instance.Spec = &v1beta1.PostgresInstanceSetSpec{
+		Fence: []v1beta1.InstanceFence{{Name: "other-instance"}},
+	}
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, !fenced)
+
+	// No pods
+	instance.Spec.Fence = append(instance.Spec.Fence, v1beta1.InstanceFence{Name: "some-instance"})
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, !known)
+	assert.Assert(t, !fenced)
+
+	// Patroni has not reloaded since fencing
+	instance.Pods = []*corev1.Pod{{}}
+	instance.Pods[0].Annotations = map[string]string{"status": `{"tags":{"nofailover":true}}`}
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, !fenced)
+
+	// Patroni reloaded after fencing
+	instance.Pods[0].Annotations["status"] = `{"tags":{"nofailover":true,"noloadbalance":true}}`
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, fenced)
+
+	// Primary
+	instance.Pods[0].Labels = map[string]string{naming.LabelRole: naming.RolePatroniLeader}
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, !fenced)
+	instance.Pods[0].Labels = nil
+
+	// Stopping; pod still exists
+	instance.Spec.Fence[1].StopPostgres = initialize.Bool(true)
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, !fenced)
+
+	// Stopped
+	instance.Pods = nil
+	fenced, known = instance.IsFenced()
+	assert.Assert(t, known)
+	assert.Assert(t, fenced)
+}
+
 func TestNewObservedInstances(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		cluster := new(v1beta1.PostgresCluster)
@@ -1540,6 +1598,30 @@ func TestGenerateInstanceStatefulSetIntent(t *testing.T) {
 		run: func(t *testing.T, ss *appsv1.StatefulSet) {
 			assert.Equal(t, *ss.Spec.Replicas, int32(1))
 		},
+	}, {
+		name: "fenced",
+		ip: intentParams{
+			spec: &v1beta1.PostgresInstanceSetSpec{
+				Fence: []v1beta1.InstanceFence{{Name: "some-instance"}},
+			},
+			sts: &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "some-instance"}},
+		},
+		run: func(t *testing.T, ss *appsv1.StatefulSet) {
+			assert.Equal(t, *ss.Spec.Replicas, int32(1))
+		},
+	}, {
+		name: "fenced and stopped",
+		ip: intentParams{
+			spec: &v1beta1.PostgresInstanceSetSpec{
+				Fence: []v1beta1.InstanceFence{
+					{Name: "some-instance", StopPostgres: initialize.Bool(true)},
+				},
+			},
+			sts: &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "some-instance"}},
+		},
+		run: func(t *testing.T, ss *appsv1.StatefulSet) {
+			assert.Equal(t, *ss.Spec.Replicas, int32(0))
+		},
 	}, {
 		name: "check imagepullsecret",
 		run: func(t *testing.T, ss *appsv1.StatefulSet) {
@@ -1933,6 +2015,230 @@ func TestFindAvailableInstanceNames(t *testing.T) {
 	}
 }
 
+func TestInstanceSetUpstreams(t *testing.T) {
+	t.Parallel()
+
+	cluster := &v1beta1.PostgresCluster{}
+	upstreams, errs := instanceSetUpstreams(cluster)
+	assert.Equal(t, len(upstreams), 0)
+	assert.Equal(t, len(errs), 0)
+
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "local"},
+		{Name: "remote", ReplicateFrom: "local"},
+		{Name: "farther", ReplicateFrom: "remote"},
+		{Name: "self", ReplicateFrom: "self"},
+		{Name: "missing", ReplicateFrom: "nope"},
+		{Name: "cycle1", ReplicateFrom: "cycle3"},
+		{Name: "cycle2", ReplicateFrom: "cycle1"},
+		{Name: "cycle3", ReplicateFrom: "cycle2"},
+		{Name: "tail", ReplicateFrom: "cycle1"},
+	}
+
+	upstreams, errs = instanceSetUpstreams(cluster)
+	assert.DeepEqual(t, upstreams, map[string]string{
+		"farther": "remote",
+		"remote":  "local",
+		"tail":    "cycle1",
+	})
+	assert.Equal(t, errs.ToAggregate().Error(), "["+strings.Join([]string{
+		`spec.instances[3].replicateFrom: Invalid value: "self": must be the name of another instance set`,
+		`spec.instances[4].replicateFrom: Invalid value: "nope": must be the name of another instance set`,
+		`spec.instances[5].replicateFrom: Invalid value: "cycle3": must not replicate from instance sets in a cycle`,
+		`spec.instances[6].replicateFrom: Invalid value: "cycle1": must not replicate from instance sets in a cycle`,
+		`spec.instances[7].replicateFrom: Invalid value: "cycle2": must not replicate from instance sets in a cycle`,
+	}, ", ")+"]")
+}
+
+func TestReplicateFromMember(t *testing.T) {
+	t.Parallel()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "local"}, {Name: "remote"},
+	}
+
+	runner := func(set, name string) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{}
+		sts.Name = name
+		sts.Labels = map[string]string{
+			naming.LabelInstanceSet: set,
+			naming.LabelInstance:    name,
+		}
+		return sts
+	}
+
+	pod := &corev1.Pod{}
+	pod.Name = "local-aaaa-0"
+	pod.Labels = map[string]string{
+		naming.LabelInstanceSet: "local",
+		naming.LabelInstance:    "local-aaaa",
+	}
+
+	observed := newObservedInstances(cluster, []appsv1.StatefulSet{
+		*runner("local", "local-cccc"),
+		*runner("local", "local-bbbb"),
+		*runner("remote", "remote-aaaa"),
+	}, []corev1.Pod{*pod})
+
+	assert.Equal(t, replicateFromMember(observed, ""), "")
+	assert.Equal(t, replicateFromMember(observed, "other"), "")
+	assert.Equal(t, replicateFromMember(observed, "local"), "local-bbbb-0",
+		"expected the first instance with a StatefulSet")
+	assert.Equal(t, replicateFromMember(observed, "remote"), "remote-aaaa-0")
+}
+
+func TestReconcileReplicateFrom(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "local"}, {Name: "remote", ReplicateFrom: "local"},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		r.reconcileReplicateFrom(cluster)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicateFromValid)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[1].ReplicateFrom = "nope"
+
+		for range 2 {
+			r.reconcileReplicateFrom(cluster)
+		}
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicateFromValid)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Assert(t, cmp.Contains(condition.Message, "replicateFrom"))
+
+		recorder := r.Recorder.(*events.Recorder)
+		assert.Equal(t, len(recorder.Events), 1, "expected one Event until the message changes")
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidReplicateFrom")
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		r.reconcileReplicateFrom(cluster)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicateFromValid) != nil)
+
+		cluster.Spec.InstanceSets[1].ReplicateFrom = ""
+		r.reconcileReplicateFrom(cluster)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicateFromValid) == nil)
+	})
+}
+
+func TestReconcilePatroniTags(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "local"}, {Name: "remote", ReplicateFrom: "local"},
+	}
+
+	// instance returns a running instance of set with a StatefulSet. Patroni
+	// in its Pod reports tags.
+	instance := func(set, name, tags string) (appsv1.StatefulSet, corev1.Pod) {
+		sts := appsv1.StatefulSet{}
+		sts.Name = name
+		sts.Labels = map[string]string{
+			naming.LabelInstanceSet: set,
+			naming.LabelInstance:    name,
+		}
+
+		pod := corev1.Pod{}
+		pod.Name = name + "-0"
+		pod.Labels = sts.Labels
+		pod.Annotations = map[string]string{"status": `{"tags":` + tags + `}`}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return sts, pod
+	}
+
+	// observe returns the instances of cluster when Patroni in the remote
+	// instance reports tags.
+	observe := func(cluster *v1beta1.PostgresCluster, tags string) *observedInstances {
+		localSTS, localPod := instance("local", "local-aaaa", `{}`)
+		remoteSTS, remotePod := instance("remote", "remote-bbbb", tags)
+		return newObservedInstances(cluster,
+			[]appsv1.StatefulSet{localSTS, remoteSTS}, []corev1.Pod{localPod, remotePod})
+	}
+
+	// setup returns a Reconciler that records calls to Patroni.
+	setup := func(t *testing.T, calls *[]string) *Reconciler {
+		return &Reconciler{
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				*calls = append(*calls, r.Method+" "+r.URL.Path)
+			}),
+		}
+	}
+
+	t.Run("Outdated", func(t *testing.T) {
+		var calls []string
+		r := setup(t, &calls)
+
+		requeue, err := r.reconcilePatroniTags(ctx, cluster, observe(cluster, `{}`))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, patroniTagsInterval)
+		assert.DeepEqual(t, calls, []string{"POST /reload"})
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		var calls []string
+		r := setup(t, &calls)
+
+		requeue, err := r.reconcilePatroniTags(ctx, cluster,
+			observe(cluster, `{"replicatefrom":"local-aaaa-0"}`))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("Fenced", func(t *testing.T) {
+		var calls []string
+		r := setup(t, &calls)
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[1].Fence = []v1beta1.InstanceFence{{Name: "remote-bbbb"}}
+
+		// Fencing changes tags without restarting PostgreSQL.
+		requeue, err := r.reconcilePatroniTags(ctx, cluster,
+			observe(cluster, `{"replicatefrom":"local-aaaa-0"}`))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, patroniTagsInterval)
+		assert.DeepEqual(t, calls, []string{"POST /reload"})
+
+		calls = nil
+		requeue, err = r.reconcilePatroniTags(ctx, cluster,
+			observe(cluster, `{"replicatefrom":"local-aaaa-0","nofailover":true,"noloadbalance":true}`))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		var calls []string
+		r := setup(t, &calls)
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[1].ReplicateFrom = ""
+
+		// The remaining tag is reloaded.
+		requeue, err := r.reconcilePatroniTags(ctx, cluster,
+			observe(cluster, `{"replicatefrom":"local-aaaa-0"}`))
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, patroniTagsInterval)
+		assert.DeepEqual(t, calls, []string{"POST /reload"})
+	})
+}
+
 func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 	ctx := context.Background()
 	_, cc := setupKubernetes(t)
@@ -1956,12 +2262,13 @@ func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 	}
 
 	ns := setupNamespace(t, cc)
+	observed := &observedInstances{}
 
 	t.Run("empty", func(t *testing.T) {
 		cluster := &v1beta1.PostgresCluster{}
 		spec := &v1beta1.PostgresInstanceSetSpec{}
 
-		assert.Error(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec),
+		assert.Error(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec),
 			"Replicas should be defined")
 	})
 
@@ -1970,7 +2277,7 @@ func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 		cluster.Namespace = ns.Name
 		spec := &cluster.Spec.InstanceSets[0]
 		spec.MinAvailable = initialize.Pointer(intstr.FromInt32(0))
-		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec))
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec))
 		assert.Assert(t, !foundPDB(cluster, spec))
 	})
 
@@ -1983,18 +2290,72 @@ func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 		assert.NilError(t, r.Client.Create(ctx, cluster))
 		t.Cleanup(func() { assert.Check(t, r.Client.Delete(ctx, cluster)) })
 
-		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec))
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec))
 		assert.Assert(t, foundPDB(cluster, spec))
 
 		t.Run("deleted", func(t *testing.T) {
 			spec.MinAvailable = initialize.Pointer(intstr.FromInt32(0))
-			err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+			err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 			if apierrors.IsConflict(err) {
 				// When running in an existing environment another controller will sometimes update
 				// the object. This leads to an error where the ResourceVersion of the object does
 				// not match what we expect. When we run into this conflict, try to reconcile the
 				// object again.
-				err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+				err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
+			}
+			assert.NilError(t, err, errors.Unwrap(err))
+			assert.Assert(t, !foundPDB(cluster, spec))
+		})
+	})
+
+	t.Run("fenced", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		spec := &cluster.Spec.InstanceSets[0]
+		spec.Replicas = initialize.Int32(3)
+		spec.Fence = []v1beta1.InstanceFence{
+			{Name: "some-instance", StopPostgres: initialize.Bool(true)},
+			{Name: "missing-instance"},
+			{Name: "primary-instance"},
+		}
+
+		primary := &corev1.Pod{}
+		primary.Labels = map[string]string{naming.LabelRole: naming.RolePatroniLeader}
+
+		observed := &observedInstances{bySet: map[string][]*Instance{
+			spec.Name: {
+				{Name: "some-instance", Spec: spec},
+				{Name: "primary-instance", Spec: spec, Pods: []*corev1.Pod{primary}},
+			},
+		}}
+
+		assert.NilError(t, r.Client.Create(ctx, cluster))
+		t.Cleanup(func() { assert.Check(t, r.Client.Delete(ctx, cluster)) })
+
+		// Fences that match no instance and a fenced primary are not counted.
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec))
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		assert.NilError(t, r.Client.Get(ctx,
+			naming.AsObjectKey(naming.InstanceSet(cluster, spec)), pdb))
+		assert.Assert(t, cmp.MarshalMatches(pdb.Spec.Selector.MatchExpressions, `
+- key: postgres-operator.crunchydata.com/instance
+  operator: NotIn
+  values:
+  - some-instance
+		`))
+
+		t.Run("deleted", func(t *testing.T) {
+			// Only one instance remains in service.
+			spec.Fence = append(spec.Fence, v1beta1.InstanceFence{
+				Name: "other-instance", StopPostgres: initialize.Bool(true),
+			})
+			observed.bySet[spec.Name] = append(observed.bySet[spec.Name],
+				&Instance{Name: "other-instance", Spec: spec})
+
+			err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
+			if apierrors.IsConflict(err) {
+				err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 			}
 			assert.NilError(t, err, errors.Unwrap(err))
 			assert.Assert(t, !foundPDB(cluster, spec))
@@ -2010,18 +2371,18 @@ func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 		assert.NilError(t, r.Client.Create(ctx, cluster))
 		t.Cleanup(func() { assert.Check(t, r.Client.Delete(ctx, cluster)) })
 
-		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec))
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec))
 		assert.Assert(t, foundPDB(cluster, spec))
 
 		t.Run("deleted", func(t *testing.T) {
 			spec.MinAvailable = initialize.Pointer(intstr.FromString("0%"))
-			err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+			err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 			if apierrors.IsConflict(err) {
 				// When running in an existing environment another controller will sometimes update
 				// the object. This leads to an error where the ResourceVersion of the object does
 				// not match what we expect. When we run into this conflict, try to reconcile the
 				// object again.
-				err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+				err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 			}
 			assert.NilError(t, err, errors.Unwrap(err))
 			assert.Assert(t, !foundPDB(cluster, spec))
@@ -2030,19 +2391,19 @@ func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
 		t.Run("delete with 00%", func(t *testing.T) {
 			spec.MinAvailable = initialize.Pointer(intstr.FromString("50%"))
 
-			assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec))
+			assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec))
 			assert.Assert(t, foundPDB(cluster, spec))
 
 			t.Run("deleted", func(t *testing.T) {
 				spec.MinAvailable = initialize.Pointer(intstr.FromString("00%"))
-				err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+				err := r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 				if apierrors.IsConflict(err) {
 					// When running in an existing environment another controller will sometimes update
 					// the object. This leads to an error where the ResourceVersion of the object does
 					// not match what we expect. When we run into this conflict, try to reconcile the
 					// object again.
 					t.Log("conflict:", err)
-					err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, spec)
+					err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, observed, spec)
 				}
 				assert.NilError(t, err, "\n%#v", errors.Unwrap(err))
 				assert.Assert(t, !foundPDB(cluster, spec))
@@ -2147,3 +2508,139 @@ func TestCleanupDisruptionBudgets(t *testing.T) {
 		})
 	})
 }
+
+func TestReconcileFence(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", Fence: []v1beta1.InstanceFence{{Name: "one-aaaa"}}},
+		{Name: "two"},
+	}
+	observed := &observedInstances{bySet: map[string][]*Instance{
+		"one": {{Name: "one-aaaa"}},
+		"two": {{Name: "two-bbbb"}},
+	}}
+
+	t.Run("Valid", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		r.reconcileFence(cluster, observed)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionFenceValid)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, len(r.Recorder.(*events.Recorder).Events), 0)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[0].Fence = append(cluster.Spec.InstanceSets[0].Fence,
+			v1beta1.InstanceFence{Name: "one-typo"}, v1beta1.InstanceFence{Name: "two-bbbb"})
+
+		for range 2 {
+			r.reconcileFence(cluster, observed)
+		}
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionFenceValid)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Assert(t, cmp.Contains(condition.Message, `spec.instances[0].fence[1].name: Not found: "one-typo"`))
+		assert.Assert(t, cmp.Contains(condition.Message, `spec.instances[0].fence[2].name: Not found: "two-bbbb"`),
+			"expected an instance of another set to be reported")
+		assert.Assert(t, !strings.Contains(condition.Message, "fence[0]"))
+
+		recorder := r.Recorder.(*events.Recorder)
+		assert.Equal(t, len(recorder.Events), 1, "expected one Event until the message changes")
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidFence")
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+		cluster := cluster.DeepCopy()
+		r.reconcileFence(cluster, observed)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionFenceValid) != nil)
+
+		cluster.Spec.InstanceSets[0].Fence = nil
+		r.reconcileFence(cluster, observed)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionFenceValid) == nil)
+	})
+}
+
+func TestReconcileFencedPrimary(t *testing.T) {
+	ctx := context.Background()
+
+	// running returns a running instance of spec with one Pod.
+	running := func(spec *v1beta1.PostgresInstanceSetSpec, name string, primary bool) *Instance {
+		pod := &corev1.Pod{}
+		pod.Name = name + "-0"
+		pod.Labels = map[string]string{naming.LabelInstance: name}
+		if primary {
+			pod.Labels[naming.LabelRole] = naming.RolePatroniLeader
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}, Spec: spec}
+	}
+
+	setup := func(t *testing.T, calls *[]string) (*Reconciler, *events.Recorder) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		return &Reconciler{
+			Recorder: recorder,
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				*calls = append(*calls, r.Method+" "+r.URL.Path+" "+string(body))
+				_, _ = w.Write([]byte(`Successfully switched over to "b-0"`))
+			}),
+		}, recorder
+	}
+
+	t.Run("NotFenced", func(t *testing.T) {
+		var calls []string
+		r, recorder := setup(t, &calls)
+
+		set := &v1beta1.PostgresInstanceSetSpec{
+			Fence: []v1beta1.InstanceFence{{Name: "b"}},
+		}
+		cluster := &v1beta1.PostgresCluster{}
+		assert.NilError(t, r.reconcileFencedPrimary(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{running(set, "a", true), running(set, "b", false)},
+		}))
+		assert.Assert(t, calls == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Switchover", func(t *testing.T) {
+		var calls []string
+		r, recorder := setup(t, &calls)
+
+		set := &v1beta1.PostgresInstanceSetSpec{
+			Fence: []v1beta1.InstanceFence{{Name: "a", StopPostgres: initialize.Bool(true)}},
+		}
+		cluster := &v1beta1.PostgresCluster{}
+		assert.NilError(t, r.reconcileFencedPrimary(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{running(set, "a", true), running(set, "b", false)},
+		}))
+		assert.DeepEqual(t, calls, []string{`POST /switchover {"leader":"a-0"}`})
+		assert.Equal(t, cluster.Status.Patroni.LeaderChangeRequested, "a")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Type, corev1.EventTypeNormal)
+	})
+
+	t.Run("NoCandidates", func(t *testing.T) {
+		var calls []string
+		r, recorder := setup(t, &calls)
+
+		set := &v1beta1.PostgresInstanceSetSpec{
+			Fence: []v1beta1.InstanceFence{{Name: "a"}, {Name: "b"}},
+		}
+		cluster := &v1beta1.PostgresCluster{}
+		assert.NilError(t, r.reconcileFencedPrimary(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{running(set, "a", true), running(set, "b", false)},
+		}))
+		assert.Assert(t, calls == nil)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Type, corev1.EventTypeWarning)
+	})
+}
@@ -6,8 +6,10 @@ package postgrescluster
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -25,6 +27,7 @@ import (
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={deletecollection}
 // +kubebuilder:rbac:groups="",resources="endpoints",verbs={deletecollection}
 
 func (r *Reconciler) deletePatroniArtifacts(
@@ -34,10 +37,15 @@ func (r *Reconciler) deletePatroniArtifacts(
 	// as Patroni creates them. Would their events cause too many reconciles?
 	// Foreground deletion may force us to adopt and set finalizers anyway.
 
+	var kind client.Object = &corev1.Endpoints{}
+	if !patroni.UseEndpoints(cluster) {
+		kind = &corev1.ConfigMap{}
+	}
+
 	selector, err := naming.AsSelector(naming.ClusterPatronis(cluster))
 	if err == nil {
 		err = errors.WithStack(
-			r.Client.DeleteAllOf(ctx, &corev1.Endpoints{},
+			r.Client.DeleteAllOf(ctx, kind,
 				client.InNamespace(cluster.Namespace),
 				client.MatchingLabelsSelector{Selector: selector},
 			))
@@ -46,6 +54,34 @@ func (r *Reconciler) deletePatroniArtifacts(
 	return err
 }
 
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// newPatroniClient returns a client for the Patroni REST API of pod. It uses
+// the certificates of the instance that pod belongs to.
+func (r *Reconciler) newPatroniClient(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pod *corev1.Pod,
+) (*patroni.Client, error) {
+	instance := &metav1.ObjectMeta{
+		Namespace: pod.Namespace,
+		Name:      pod.Labels[naming.LabelInstance],
+	}
+
+	certificates := &corev1.Secret{ObjectMeta: naming.InstanceCertificates(instance)}
+	err := errors.WithStack(
+		r.Client.Get(ctx, client.ObjectKeyFromObject(certificates), certificates))
+
+	var transport *http.Transport
+	if err == nil {
+		transport, err = r.patroniTransports.Get(certificates)
+	}
+
+	var api *patroni.Client
+	if err == nil {
+		api = patroni.NewClient(cluster, pod.Name, transport)
+	}
+	return api, err
+}
+
 func (r *Reconciler) handlePatroniRestarts(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 ) error {
@@ -87,14 +123,11 @@ func (r *Reconciler) handlePatroniRestarts(
 	// replicas here, replicas will typically restart first because we see them
 	// first.
 	if primaryNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := primaryNeedsRestart.Pods[0]
-			return r.PodExec(ctx, pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
-
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "primary", naming.PatroniScope(cluster)))
+		api, err := r.PatroniClient(ctx, cluster, primaryNeedsRestart.Pods[0])
+		if err == nil {
+			err = api.RestartPendingMembers(ctx, "primary")
+		}
+		return errors.WithStack(err)
 	}
 
 	// When the primary does not need to restart but a replica does, restart all
@@ -112,20 +145,168 @@ func (r *Reconciler) handlePatroniRestarts(
 	// how we decide when to restart.
 	// - https://www.postgresql.org/docs/current/runtime-config-replication.html
 	if replicaNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := replicaNeedsRestart.Pods[0]
-			return r.PodExec(ctx, pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
-
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "replica", naming.PatroniScope(cluster)))
+		api, err := r.PatroniClient(ctx, cluster, replicaNeedsRestart.Pods[0])
+		if err == nil {
+			err = api.RestartPendingMembers(ctx, "replica")
+		}
+		return errors.WithStack(err)
 	}
 
 	// Nothing needs to restart.
 	return nil
 }
 
+// restartRequestInterval is how often progress is checked while instances
+// restart according to spec.restartRequestedAt.
+const restartRequestInterval = 10 * time.Second
+
+// reconcileRestartRequest restarts PostgreSQL in every instance that started
+// before spec.restartRequestedAt. It restarts one instance at a time: replicas
+// first, then the primary after a switchover. It returns how long to wait
+// before checking progress again.
+func (r *Reconciler) reconcileRestartRequest(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (time.Duration, error) {
+	requested := cluster.Spec.RestartRequestedAt
+	if requested == nil {
+		cluster.Status.RestartRequestedAt = nil
+		return 0, nil
+	}
+	if cluster.Status.RestartRequestedAt.Equal(requested) {
+		return 0, nil
+	}
+
+	// Every instance starts before a time in the future, so it would restart
+	// again and again until then. Consider such a request done without
+	// restarting anything.
+	if requested.After(time.Now()) {
+		cluster.Status.RestartRequestedAt = requested.DeepCopy()
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidRestartRequest",
+			"Ignoring spec.restartRequestedAt; %s is in the future", requested.UTC().Format(time.RFC3339))
+		return 0, nil
+	}
+
+	// Patroni does not change its leader on its own while paused, and
+	// restarting the primary then causes downtime. Wait for it to resume.
+	if patroniPaused(cluster) {
+		return restartRequestInterval, nil
+	}
+
+	restarted := make(map[string]int32)
+	var primary *Instance
+	var replicas []*Instance
+
+	for _, instance := range instances.forCluster {
+		// Skip instances that have no set in cluster spec. They are about to
+		// be removed.
+		if instance.Spec == nil {
+			continue
+		}
+
+		// Skip instances that are fenced or otherwise stopped. PostgreSQL
+		// starts after the request when they start again.
+		if fence := instance.Spec.FencedInstance(instance.Name); fence != nil &&
+			initialize.FromPointer(fence.StopPostgres) {
+			continue
+		}
+		if instance.Runner != nil && instance.Runner.Spec.Replicas != nil &&
+			*instance.Runner.Spec.Replicas == 0 {
+			continue
+		}
+
+		// Wait for every other instance to be running.
+		if terminating, known := instance.IsTerminating(); terminating || !known {
+			return restartRequestInterval, nil
+		}
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running ||
+			!known || len(instance.Pods) != 1 {
+			return restartRequestInterval, nil
+		}
+
+		pod := instance.Pods[0]
+		api, err := r.PatroniClient(ctx, cluster, pod)
+
+		var member *patroni.MemberStatus
+		if err == nil {
+			member, err = api.GetMember(ctx, pod.Name)
+		}
+		var started time.Time
+		if err == nil {
+			started, err = member.PostmasterStarted()
+		}
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+
+		if !started.Before(requested.Time) {
+			restarted[instance.Spec.Name]++
+		} else if isPrimary, _ := instance.IsPrimary(); isPrimary {
+			primary = instance
+		} else {
+			replicas = append(replicas, instance)
+		}
+	}
+
+	for i := range cluster.Status.InstanceSets {
+		status := &cluster.Status.InstanceSets[i]
+		status.RestartedReplicas = restarted[status.Name]
+	}
+
+	// Restart one replica at a time.
+	if len(replicas) > 0 {
+		pod := replicas[0].Pods[0]
+		api, err := r.PatroniClient(ctx, cluster, pod)
+		if err == nil {
+			err = api.Restart(ctx, pod.Name)
+		}
+		if err == nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RestartedInstance",
+				"Restarted PostgreSQL in instance %q", replicas[0].Name)
+		}
+		return restartRequestInterval, errors.WithStack(err)
+	}
+
+	// When every replica has restarted, change the primary. The former primary
+	// restarts as it follows the new one. Restart it in place when no other
+	// instance can take over.
+	if primary != nil {
+		var candidates int
+		for _, other := range instances.forCluster {
+			if other != primary && len(other.Pods) > 0 && (other.Spec == nil ||
+				(patroni.InstanceSetCanFailover(other.Spec) && other.Spec.FencedInstance(other.Name) == nil)) {
+				candidates++
+			}
+		}
+
+		pod := primary.Pods[0]
+		api, err := r.PatroniClient(ctx, cluster, pod)
+		if err == nil && candidates > 0 {
+			var success bool
+			success, err = api.ChangePrimaryAndWait(ctx, pod.Name, "")
+			if err == nil && !success {
+				err = errors.New("unable to switchover")
+			}
+			if err == nil {
+				cluster.Status.Patroni.LeaderChangeRequested = primary.Name
+			}
+		} else if err == nil {
+			err = api.Restart(ctx, pod.Name)
+		}
+		if err == nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RestartedInstance",
+				"Restarted PostgreSQL in primary instance %q", primary.Name)
+		}
+		return restartRequestInterval, errors.WithStack(err)
+	}
+
+	// Every instance has restarted.
+	cluster.Status.RestartRequestedAt = requested.DeepCopy()
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RestartCompleted",
+		"Restarted PostgreSQL in every instance started before %s", requested.UTC().Format(time.RFC3339))
+
+	return 0, nil
+}
+
 // +kubebuilder:rbac:groups="",resources="services",verbs={create,patch}
 
 // reconcilePatroniDistributedConfiguration sets labels and ownership on the
@@ -133,6 +314,11 @@ func (r *Reconciler) handlePatroniRestarts(
 func (r *Reconciler) reconcilePatroniDistributedConfiguration(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
 ) error {
+	// Patroni keeps ConfigMaps without any help.
+	if !patroni.UseEndpoints(cluster) {
+		return nil
+	}
+
 	// When using Endpoints for DCS, Patroni needs a Service to ensure that the
 	// Endpoints object is not removed by Kubernetes at startup. Patroni will
 	// create this object if it has permission to do so, but it won't set any
@@ -171,9 +357,12 @@ func (r *Reconciler) reconcilePatroniDistributedConfiguration(
 
 // +kubebuilder:rbac:resources="pods",verbs={get,list}
 
+// reconcilePatroniDynamicConfiguration replaces the dynamic configuration of
+// Patroni. Patroni follows the source described in spec.standby only when
+// standby is true, regardless of spec.standby.enabled.
 func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
-	pgHBAs postgres.HBAs, pgParameters postgres.Parameters,
+	pgHBAs postgres.HBAs, pgParameters postgres.Parameters, standby bool,
 ) error {
 	if !patroni.ClusterBootstrapped(cluster) {
 		// Patroni has not yet bootstrapped. Dynamic configuration happens through
@@ -197,20 +386,27 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 		return nil
 	}
 
-	// NOTE(cbandy): Despite the guards above, calling PodExec may still fail
-	// due to a missing or stopped container.
+	// NOTE(cbandy): Despite the guards above, calling the Patroni API may
+	// still fail due to a missing or stopped container.
 
-	exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
-		return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+	spec := &cluster.Spec
+	if spec.Standby != nil && spec.Standby.Enabled != standby {
+		spec = cluster.Spec.DeepCopy()
+		spec.Standby.Enabled = standby
 	}
 
-	return errors.WithStack(
-		patroni.Executor(exec).ReplaceConfiguration(ctx,
-			patroni.DynamicConfiguration(&cluster.Spec, pgHBAs, pgParameters)))
+	api, err := r.PatroniClient(ctx, cluster, pod)
+	if err == nil {
+		err = api.ReplaceConfiguration(ctx,
+			patroni.DynamicConfiguration(spec, pgHBAs, pgParameters))
+	}
+	return errors.WithStack(err)
 }
 
 // generatePatroniLeaderLeaseService returns a v1.Service that exposes the
-// Patroni leader when Patroni is using Endpoints for its leader elections.
+// Patroni leader. When Patroni is using Endpoints for its leader elections,
+// Patroni manages the Endpoints of this Service. Otherwise, it selects the
+// Pod that Patroni labels as leader.
 func (r *Reconciler) generatePatroniLeaderLeaseService(
 	cluster *v1beta1.PostgresCluster) (*corev1.Service, error,
 ) {
@@ -241,6 +437,15 @@ func (r *Reconciler) generatePatroniLeaderLeaseService(
 	// - https://docs.k8s.io/concepts/services-networking/service/#services-without-selectors
 	service.Spec.Selector = nil
 
+	// When using ConfigMaps, Patroni does not manage Endpoints. Let Kubernetes
+	// manage them by selecting the Pod with the Patroni leader role.
+	if !patroni.UseEndpoints(cluster) {
+		service.Spec.Selector = map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    naming.RolePatroniLeader,
+		}
+	}
+
 	// The TargetPort must be the name (not the number) of the PostgreSQL
 	// ContainerPort. This name allows the port number to differ between
 	// instances, which can happen during a rolling update.
@@ -281,8 +486,8 @@ func (r *Reconciler) generatePatroniLeaderLeaseService(
 // +kubebuilder:rbac:groups="",resources="services",verbs={create,patch}
 
 // reconcilePatroniLeaderLease sets labels and ownership on the objects Patroni
-// creates for its leader elections. When Patroni is using Endpoints for this,
-// the returned Service resolves to the elected leader. Otherwise, it is nil.
+// creates for its leader elections. The returned Service resolves to the
+// elected leader.
 func (r *Reconciler) reconcilePatroniLeaderLease(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
 ) (*corev1.Service, error) {
@@ -297,6 +502,7 @@ func (r *Reconciler) reconcilePatroniLeaderLease(
 	return service, err
 }
 
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={get}
 // +kubebuilder:rbac:groups="",resources="endpoints",verbs={get}
 
 // reconcilePatroniStatus populates cluster.Status.Patroni with observations.
@@ -314,14 +520,30 @@ func (r *Reconciler) reconcilePatroniStatus(
 		}
 	}
 
-	dcs := &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+	// Patroni stores the same annotations on Endpoints and ConfigMaps.
+	var dcs client.Object = &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+	if !patroni.UseEndpoints(cluster) {
+		dcs = &corev1.ConfigMap{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+	}
 	err := errors.WithStack(client.IgnoreNotFound(
 		r.Client.Get(ctx, client.ObjectKeyFromObject(dcs), dcs)))
 
 	if err == nil {
-		if dcs.Annotations["initialize"] != "" {
+		// Patroni stores its dynamic configuration as JSON in an annotation.
+		// The "pause" key is true while in maintenance mode.
+		// - https://github.com/zalando/patroni/blob/v3.3.0/patroni/dcs/kubernetes.py#L1222
+		var config struct {
+			Pause bool `json:"pause"`
+		}
+		if value, ok := dcs.GetAnnotations()["config"]; !ok {
+			cluster.Status.Patroni.Paused = nil
+		} else if json.Unmarshal([]byte(value), &config) == nil {
+			cluster.Status.Patroni.Paused = &config.Pause
+		}
+
+		if dcs.GetAnnotations()["initialize"] != "" {
 			// After bootstrap, Patroni writes the cluster system identifier to DCS.
-			cluster.Status.Patroni.SystemIdentifier = dcs.Annotations["initialize"]
+			cluster.Status.Patroni.SystemIdentifier = dcs.GetAnnotations()["initialize"]
 		} else if readyInstance {
 			// While we typically expect a value for the initialize key to be present in the
 			// Endpoints above by the time the StatefulSet for any instance indicates "ready"
@@ -333,11 +555,148 @@ func (r *Reconciler) reconcilePatroniStatus(
 			log.Info("detected ready instance but no initialize value")
 			requeue = time.Second
 		}
+
+		// Patroni stores the history of PostgreSQL timelines as JSON in an
+		// annotation. Each entry begins with the number of a timeline that ended.
+		// - https://github.com/zalando/patroni/blob/v3.3.0/patroni/dcs/kubernetes.py
+		var timeline int64
+		if dcs.GetAnnotations()["initialize"] != "" {
+			var history [][]json.RawMessage
+			var ended int64
+			timeline = 1
+
+			if json.Unmarshal([]byte(dcs.GetAnnotations()["history"]), &history) == nil &&
+				len(history) > 0 && len(history[len(history)-1]) > 0 &&
+				json.Unmarshal(history[len(history)-1][0], &ended) == nil {
+				timeline = ended + 1
+			}
+		}
+
+		r.observePatroniLeader(cluster, observedInstances, timeline)
 	}
 
 	return requeue, err
 }
 
+// patroniLeaderHistoryLimit is the number of leader changes kept in status.
+const patroniLeaderHistoryLimit = 10
+
+// observePatroniLeader compares the Patroni leader and timeline to those in
+// cluster status. It records any change in the leader history and emits an
+// Event describing it. A timeline of zero means it is not known.
+func (r *Reconciler) observePatroniLeader(
+	cluster *v1beta1.PostgresCluster, observed *observedInstances, timeline int64,
+) {
+	status := &cluster.Status.Patroni
+
+	// Patroni labels the Pod of its leader. The leader of a standby cluster
+	// is reported only in the status annotation.
+	var leader string
+	for _, instance := range observed.forCluster {
+		if primary, known := instance.IsPrimary(); primary && known {
+			leader = instance.Name
+		} else if len(instance.Pods) > 0 && patroni.PodIsStandbyLeader(instance.Pods[0]) {
+			leader = instance.Name
+		}
+	}
+
+	// There might be no leader during an election. The first leader is not a
+	// change worth recording.
+	if leader == "" {
+		return
+	}
+	if status.Leader == "" {
+		status.Leader, status.Timeline = leader, timeline
+		return
+	}
+
+	var change *v1beta1.PatroniLeaderChange
+	switch {
+	case leader != status.Leader:
+		change = &v1beta1.PatroniLeaderChange{
+			PreviousLeader: status.Leader,
+			Leader:         leader,
+			Reason:         patroniLeaderChangeReason(cluster, status.Leader),
+		}
+
+		// Patroni writes the timeline history shortly after it labels a new
+		// leader. Leave the timeline unknown until then.
+		if timeline > status.Timeline {
+			change.Timeline = timeline
+		}
+
+	case timeline > status.Timeline && status.Timeline > 0:
+		// Attribute the new timeline to a change recorded before Patroni
+		// wrote it.
+		if n := len(status.LeaderHistory); n > 0 &&
+			status.LeaderHistory[n-1].Leader == leader &&
+			status.LeaderHistory[n-1].Timeline == 0 {
+			status.LeaderHistory[n-1].Timeline = timeline
+		} else {
+			// The leader changed and changed back between observations.
+			change = &v1beta1.PatroniLeaderChange{
+				PreviousLeader: leader,
+				Leader:         leader,
+				Timeline:       timeline,
+				Reason:         v1beta1.PatroniLeaderChangeUnknown,
+			}
+		}
+	}
+
+	if change != nil {
+		status.LeaderChangeRequested = ""
+	}
+	status.Leader = leader
+	if timeline > 0 {
+		status.Timeline = timeline
+	}
+
+	if change != nil {
+		change.Time = metav1.Now()
+		status.LeaderHistory = append(status.LeaderHistory, *change)
+		if n := len(status.LeaderHistory); n > patroniLeaderHistoryLimit {
+			status.LeaderHistory = status.LeaderHistory[n-patroniLeaderHistoryLimit:]
+		}
+
+		eventType, reason := corev1.EventTypeWarning, "LeaderChanged"
+		switch change.Reason {
+		case v1beta1.PatroniLeaderChangeFailover:
+			reason = "Failover"
+		case v1beta1.PatroniLeaderChangeSwitchover:
+			eventType, reason = corev1.EventTypeNormal, "Switchover"
+		}
+		if change.Timeline > 0 {
+			r.Recorder.Eventf(cluster, eventType, reason,
+				"Leader changed from %q to %q on timeline %d",
+				change.PreviousLeader, change.Leader, change.Timeline)
+		} else {
+			r.Recorder.Eventf(cluster, eventType, reason,
+				"Leader changed from %q to %q", change.PreviousLeader, change.Leader)
+		}
+	}
+}
+
+// patroniLeaderChangeReason explains why the Patroni leader changed from
+// previous. Changes the operator asked for are switchovers. Patroni changes the
+// leader on its own only when the leader fails, so everything else is a failover.
+func patroniLeaderChangeReason(cluster *v1beta1.PostgresCluster, previous string) string {
+	if requested := cluster.Status.Patroni.LeaderChangeRequested; requested != "" {
+		if requested == previous {
+			return v1beta1.PatroniLeaderChangeSwitchover
+		}
+		return v1beta1.PatroniLeaderChangeUnknown
+	}
+	return v1beta1.PatroniLeaderChangeFailover
+}
+
+// patroniPaused returns true when Patroni of cluster is or will soon be in
+// maintenance mode.
+func patroniPaused(cluster *v1beta1.PostgresCluster) bool {
+	return (cluster.Spec.Patroni != nil && cluster.Spec.Patroni.Pause != nil &&
+		*cluster.Spec.Patroni.Pause) ||
+		(cluster.Status.Patroni.Paused != nil && *cluster.Status.Patroni.Paused)
+}
+
 // reconcileReplicationSecret creates a secret containing the TLS
 // certificate, key and CA certificate for use with the replication and
 // pg_rewind accounts in Postgres.
@@ -465,6 +824,13 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		return nil
 	}
 
+	// Patroni does not change its leader on its own while paused. Wait for it
+	// to resume before doing so here.
+	if patroniPaused(cluster) {
+		log.Info("Waiting for Patroni to resume before switchover")
+		return nil
+	}
+
 	// If we've reached this point, we assume a switchover request or in progress
 	// and need to make sure the prerequisites are met, e.g., more than one pod,
 	// a running instance to issue the switchover command to, etc.
@@ -504,7 +870,7 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		log.V(1).Info("TargetInstance not provided")
 	}
 
-	// Find a running Pod that can be used to define a PodExec function.
+	// Find a running Pod that can answer calls to the Patroni API.
 	var runningPod *corev1.Pod
 	for _, instance := range instances.forCluster {
 		if running, known := instance.IsRunning(naming.ContainerDatabase); running &&
@@ -517,10 +883,9 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	if runningPod == nil {
 		return errors.New("Could not find a running pod when attempting switchover.")
 	}
-	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer,
-		command ...string) error {
-		return r.PodExec(ctx, runningPod.Namespace, runningPod.Name, naming.ContainerDatabase, stdin,
-			stdout, stderr, command...)
+	api, err := r.PatroniClient(ctx, cluster, runningPod)
+	if err != nil {
+		return errors.WithStack(err)
 	}
 
 	// To ensure idempotency, the operator verifies that the timeline reported by Patroni
@@ -528,10 +893,10 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	// TODO(benjaminjb): consider pulling the timeline from the pod annotation; manual experiments
 	// have shown that the annotation on the Leader pod is up to date during a switchover, but
 	// missing from the Replica pods.
-	timeline, err := patroni.Executor(exec).GetTimeline(ctx)
+	timeline, err := api.GetTimeline(ctx)
 
 	if err != nil {
-		return err
+		return errors.WithStack(err)
 	}
 
 	if timeline == 0 {
@@ -561,29 +926,29 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		return nil
 	}
 
-	// We have the pod executor, now we need to figure out which API call to use
+	// We have the Patroni client, now we need to figure out which API call to use
 	// In the default case we will be using SwitchoverAndWait. This API call uses
-	// a Patronictl switchover to move to the target instance.
-	action := func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-		success, err := exec.SwitchoverAndWait(ctx, next)
+	// a Patroni switchover to move to the target instance.
+	action := func(ctx context.Context, api *patroni.Client, next string) (bool, error) {
+		success, err := api.SwitchoverAndWait(ctx, next)
 		return success, errors.WithStack(err)
 	}
 
 	if spec.Type == v1beta1.PatroniSwitchoverTypeFailover {
 		// When a failover has been requested we use FailoverAndWait to change the primary.
-		action = func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-			success, err := exec.FailoverAndWait(ctx, next)
+		action = func(ctx context.Context, api *patroni.Client, next string) (bool, error) {
+			success, err := api.FailoverAndWait(ctx, next)
 			return success, errors.WithStack(err)
 		}
 	}
 
-	// If target instance has not been provided, we will pass in an empty string to patronictl
+	// If target instance has not been provided, we will pass in an empty string to Patroni
 	nextPrimary := ""
 	if targetInstance != nil {
 		nextPrimary = targetInstance.Pods[0].Name
 	}
 
-	success, err := action(ctx, exec, nextPrimary)
+	success, err := action(ctx, api, nextPrimary)
 	if err = errors.WithStack(err); err == nil && !success {
 		err = errors.New("unable to switchover")
 	}
@@ -591,9 +956,144 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	// If we've reached this point, a switchover has successfully been triggered
 	// and we set the status accordingly.
 	if err == nil {
+		cluster.Status.Patroni.LeaderChangeRequested = cluster.Status.Patroni.Leader
 		cluster.Status.Patroni.Switchover = initialize.String(annotation)
 		cluster.Status.Patroni.SwitchoverTimeline = nil
 	}
 
 	return err
 }
+
+// reinitializeInterval is how often progress is checked while Patroni
+// reinitializes a replica.
+const reinitializeInterval = 10 * time.Second
+
+// reconcilePatroniReinitialize asks Patroni to reinitialize the replica in
+// spec.patroni.reinitialize when the trigger annotation changes. It records
+// progress in status and returns how long to wait before checking it again.
+func (r *Reconciler) reconcilePatroniReinitialize(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (time.Duration, error) {
+	// If reinitialize is not enabled, clear out the status fields which might
+	// have been set by previous requests. Like switchover, this gives the user
+	// a way to recover and try again.
+	if cluster.Spec.Patroni == nil ||
+		cluster.Spec.Patroni.Reinitialize == nil ||
+		!cluster.Spec.Patroni.Reinitialize.Enabled {
+		cluster.Status.Patroni.Reinitialize = nil
+		cluster.Status.Patroni.ReinitializeProgress = nil
+		return 0, nil
+	}
+
+	annotation := cluster.GetAnnotations()[naming.PatroniReinitialize]
+	spec := cluster.Spec.Patroni.Reinitialize
+	status := cluster.Status.Patroni.Reinitialize
+	progress := cluster.Status.Patroni.ReinitializeProgress
+
+	// Nothing to do when the request has been completed. Keep the details of
+	// a request that failed or was rejected.
+	if annotation == "" || (status != nil && *status == annotation) {
+		if progress == nil || progress.Request != annotation ||
+			progress.State == v1beta1.PatroniReinitializeRunning || progress.State == "" {
+			cluster.Status.Patroni.ReinitializeProgress = nil
+		}
+		return 0, nil
+	}
+
+	// finish records the end of the request with state and message.
+	finish := func(instance, state, message string) {
+		cluster.Status.Patroni.Reinitialize = initialize.String(annotation)
+		if progress == nil || progress.Request != annotation || progress.Instance != instance {
+			progress = &v1beta1.PatroniReinitializeProgress{
+				Request: annotation, Instance: instance, StartTime: metav1.Now(),
+			}
+		}
+		progress.State, progress.Message = state, message
+		cluster.Status.Patroni.ReinitializeProgress = progress
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "Reinitialize"+state,
+			"Unable to reinitialize instance %q: %s", instance, message)
+	}
+
+	var target *Instance
+	for _, instance := range instances.forCluster {
+		if instance.Name == spec.TargetInstance {
+			target = instance
+		}
+	}
+	if target == nil {
+		finish(spec.TargetInstance, v1beta1.PatroniReinitializeRejected,
+			"the instance was not found in the cluster")
+		return 0, nil
+	}
+	if running, known := target.IsRunning(naming.ContainerDatabase); !running ||
+		!known || len(target.Pods) != 1 {
+		return reinitializeInterval, nil
+	}
+	if primary, _ := target.IsPrimary(); primary {
+		finish(target.Name, v1beta1.PatroniReinitializeRejected,
+			"the instance is the primary")
+		return 0, nil
+	}
+
+	pod := target.Pods[0]
+	api, err := r.PatroniClient(ctx, cluster, pod)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	// Start a new request when none is in progress for this annotation.
+	// Patroni responds 503 when it cannot reinitialize the member.
+	// - https://patroni.readthedocs.io/en/latest/rest_api.html
+	if progress == nil || progress.Request != annotation || progress.Instance != target.Name {
+		member, err := api.GetMember(ctx, pod.Name)
+		if err == nil {
+			err = api.Reinitialize(ctx, pod.Name)
+		}
+
+		var response *patroni.Error
+		if errors.As(err, &response) && response.StatusCode == http.StatusServiceUnavailable {
+			finish(target.Name, v1beta1.PatroniReinitializeRejected,
+				strings.TrimSpace(response.Message))
+			return 0, nil
+		}
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+
+		cluster.Status.Patroni.ReinitializeProgress = &v1beta1.PatroniReinitializeProgress{
+			Request: annotation, Instance: target.Name, StartTime: metav1.Now(),
+			PostmasterStartTime: member.PostmasterStartTime,
+			State:               v1beta1.PatroniReinitializeRunning,
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ReinitializeStarted",
+			"Reinitializing instance %q", target.Name)
+		return reinitializeInterval, nil
+	}
+
+	// The request is complete when PostgreSQL has started again after being
+	// created, is following the primary, and has no restart pending or
+	// requested. Compare the start times reported by Patroni rather than
+	// clocks of different machines.
+	member, err := api.GetMember(ctx, pod.Name)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if member.State == "crashed" || member.State == "start failed" {
+		finish(target.Name, v1beta1.PatroniReinitializeFailed,
+			"PostgreSQL is "+member.State)
+		return 0, nil
+	}
+	if (member.State != "running" && member.State != "streaming") ||
+		member.PostmasterStartTime == "" ||
+		member.PostmasterStartTime == progress.PostmasterStartTime ||
+		member.PendingRestart || member.ScheduledRestart != nil {
+		return reinitializeInterval, nil
+	}
+
+	cluster.Status.Patroni.Reinitialize = initialize.String(annotation)
+	cluster.Status.Patroni.ReinitializeProgress = nil
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ReinitializeCompleted",
+		"Reinitialized instance %q", target.Name)
+
+	return 0, nil
+}
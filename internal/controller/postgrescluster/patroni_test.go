@@ -8,7 +8,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
@@ -24,9 +25,12 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -474,6 +478,8 @@ func TestReconcilePatroniStatus(t *testing.T) {
 		if writeAnnotation {
 			endpoints.ObjectMeta.Annotations = make(map[string]string)
 			endpoints.ObjectMeta.Annotations["initialize"] = systemIdentifier
+			endpoints.ObjectMeta.Annotations["config"] = `{"pause":true,"ttl":30}`
+			endpoints.ObjectMeta.Annotations["history"] = `[[1,25165984,"no recovery target specified","2024-01-02T03:04:05+00:00","one"],[2,33554592,"no recovery target specified","2024-01-03T03:04:05+00:00","two"]]`
 		}
 		assert.NilError(t, tClient.Create(ctx, endpoints, &client.CreateOptions{}))
 
@@ -482,6 +488,9 @@ func TestReconcilePatroniStatus(t *testing.T) {
 		}
 		for i := 0; i < readyReplicas; i++ {
 			instance.Pods = append(instance.Pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{naming.LabelRole: naming.RolePatroniLeader},
+				},
 				Status: corev1.PodStatus{
 					Conditions: []corev1.PodCondition{{
 						Type:    corev1.PodReady,
@@ -521,10 +530,239 @@ func TestReconcilePatroniStatus(t *testing.T) {
 				assert.NilError(t, err)
 				assert.Equal(t, requeue, time.Duration(0))
 			}
+			if tc.writeAnnotation {
+				assert.DeepEqual(t, postgresCluster.Status.Patroni.Paused, initialize.Bool(true))
+			} else {
+				assert.Assert(t, postgresCluster.Status.Patroni.Paused == nil)
+			}
+
+			// The timeline follows the last entry of Patroni's history.
+			if tc.writeAnnotation {
+				assert.Equal(t, postgresCluster.Status.Patroni.Leader, observedInstances.forCluster[0].Name)
+				assert.Equal(t, postgresCluster.Status.Patroni.Timeline, int64(3))
+			}
 		})
 	}
 }
 
+func TestObservePatroniLeader(t *testing.T) {
+	t.Parallel()
+
+	instance := func(name, role string, ready bool) *Instance {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Labels = map[string]string{naming.LabelRole: role}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type: corev1.PodReady, Status: corev1.ConditionTrue,
+			}}
+		}
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}, Runner: &appsv1.StatefulSet{}}
+	}
+	observe := func(instances ...*Instance) *observedInstances {
+		observed := &observedInstances{byName: map[string]*Instance{}}
+		for _, instance := range instances {
+			observed.byName[instance.Name] = instance
+			observed.forCluster = append(observed.forCluster, instance)
+		}
+		return observed
+	}
+
+	t.Run("NoLeader", func(t *testing.T) {
+		cluster := testCluster()
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+
+		r.observePatroniLeader(cluster, observe(
+			instance("one", naming.RolePatroniReplica, true),
+		), 2)
+		assert.Equal(t, cluster.Status.Patroni.Leader, "")
+		assert.Equal(t, cluster.Status.Patroni.Timeline, int64(0))
+	})
+
+	t.Run("First", func(t *testing.T) {
+		cluster := testCluster()
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		r.observePatroniLeader(cluster, observe(
+			instance("one", naming.RolePatroniLeader, true),
+		), 1)
+		assert.Equal(t, cluster.Status.Patroni.Leader, "one")
+		assert.Equal(t, cluster.Status.Patroni.Timeline, int64(1))
+		assert.Equal(t, len(cluster.Status.Patroni.LeaderHistory), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Switchover", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Status.Patroni.Leader, cluster.Status.Patroni.Timeline = "one", 1
+		cluster.Status.Patroni.LeaderChangeRequested = "one"
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		observed := observe(
+			instance("one", naming.RolePatroniReplica, true),
+			instance("two", naming.RolePatroniLeader, true),
+		)
+
+		// Patroni labels the new leader before it writes the new timeline.
+		r.observePatroniLeader(cluster, observed, 1)
+		r.observePatroniLeader(cluster, observed, 2)
+
+		assert.Equal(t, cluster.Status.Patroni.Leader, "two")
+		assert.Equal(t, cluster.Status.Patroni.Timeline, int64(2))
+		assert.Equal(t, cluster.Status.Patroni.LeaderChangeRequested, "")
+		assert.Equal(t, len(cluster.Status.Patroni.LeaderHistory), 1)
+
+		change := cluster.Status.Patroni.LeaderHistory[0]
+		assert.Equal(t, change.PreviousLeader, "one")
+		assert.Equal(t, change.Leader, "two")
+		assert.Equal(t, change.Timeline, int64(2))
+		assert.Equal(t, change.Reason, "Switchover")
+		assert.Assert(t, !change.Time.IsZero())
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Type, "Normal")
+		assert.Equal(t, recorder.Events[0].Reason, "Switchover")
+		assert.Equal(t, recorder.Events[0].Note, `Leader changed from "one" to "two"`)
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Status.Patroni.Leader, cluster.Status.Patroni.Timeline = "one", 3
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		// The previous leader is healthy, but the operator did not ask for
+		// the change.
+		r.observePatroniLeader(cluster, observe(
+			instance("one", naming.RolePatroniReplica, true),
+			instance("two", naming.RolePatroniLeader, true),
+		), 4)
+
+		assert.Equal(t, len(cluster.Status.Patroni.LeaderHistory), 1)
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[0].Reason, "Failover")
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[0].Timeline, int64(4))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Type, "Warning")
+		assert.Equal(t, recorder.Events[0].Reason, "Failover")
+		assert.Equal(t, recorder.Events[0].Note, `Leader changed from "one" to "two" on timeline 4`)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Status.Patroni.Leader, cluster.Status.Patroni.Timeline = "one", 3
+		cluster.Status.Patroni.LeaderChangeRequested = "other"
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		// The operator asked to replace a different leader.
+		r.observePatroniLeader(cluster, observe(
+			instance("two", naming.RolePatroniLeader, true),
+		), 4)
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[0].Reason, "Unknown")
+
+		// The timeline changed while the leader did not.
+		r.observePatroniLeader(cluster, observe(
+			instance("two", naming.RolePatroniLeader, true),
+		), 6)
+		assert.Equal(t, len(cluster.Status.Patroni.LeaderHistory), 2)
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[1].PreviousLeader, "two")
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[1].Timeline, int64(6))
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[1].Reason, "Unknown")
+
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[1].Reason, "LeaderChanged")
+	})
+
+	t.Run("StandbyLeader", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Status.Patroni.Leader, cluster.Status.Patroni.Timeline = "one", 1
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		standby := instance("two", naming.RolePatroniReplica, true)
+		standby.Pods[0].Annotations = map[string]string{
+			"status": `{"role":"standby_leader"}`,
+		}
+
+		r.observePatroniLeader(cluster, observe(
+			instance("one", naming.RolePatroniReplica, true), standby,
+		), 1)
+		assert.Equal(t, cluster.Status.Patroni.Leader, "two")
+		assert.Equal(t, len(cluster.Status.Patroni.LeaderHistory), 1)
+		assert.Equal(t, cluster.Status.Patroni.LeaderHistory[0].Reason, "Failover")
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		cluster := testCluster()
+		r := &Reconciler{Recorder: events.NewRecorder(t, runtime.Scheme)}
+
+		one := observe(instance("one", naming.RolePatroniLeader, true))
+		two := observe(instance("two", naming.RolePatroniLeader, true))
+		for i := int64(1); i <= 15; i++ {
+			if i%2 == 0 {
+				r.observePatroniLeader(cluster, two, i)
+			} else {
+				r.observePatroniLeader(cluster, one, i)
+			}
+		}
+
+		history := cluster.Status.Patroni.LeaderHistory
+		assert.Equal(t, len(history), 10)
+		assert.Equal(t, history[0].Timeline, int64(6))
+		assert.Equal(t, history[9].Timeline, int64(15))
+	})
+}
+
+func TestPatroniPaused(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	assert.Assert(t, !patroniPaused(cluster))
+
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{Pause: initialize.Bool(false)}
+	assert.Assert(t, !patroniPaused(cluster))
+
+	cluster.Spec.Patroni.Pause = initialize.Bool(true)
+	assert.Assert(t, patroniPaused(cluster), "expected paused by spec")
+
+	// Remains paused until Patroni resumes.
+	cluster.Spec.Patroni.Pause = initialize.Bool(false)
+	cluster.Status.Patroni.Paused = initialize.Bool(true)
+	assert.Assert(t, patroniPaused(cluster), "expected paused by status")
+
+	cluster.Status.Patroni.Paused = initialize.Bool(false)
+	assert.Assert(t, !patroniPaused(cluster))
+}
+
+func TestReconcilePatroniSwitchoverPaused(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	r := &Reconciler{
+		PatroniClient: setupPatroniClient(t, func(http.ResponseWriter, *http.Request) {
+			called = true
+		}),
+	}
+
+	cluster := testCluster()
+	cluster.Annotations = map[string]string{naming.PatroniSwitchover: "trigger"}
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		Pause:      initialize.Bool(true),
+		Switchover: &v1beta1.PatroniSwitchover{Enabled: true},
+	}
+
+	assert.NilError(t, r.reconcilePatroniSwitchover(context.Background(), cluster, &observedInstances{}))
+	assert.Assert(t, !called, "expected no calls to Patroni")
+	assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
+}
+
 func TestReconcilePatroniSwitchover(t *testing.T) {
 	_, client := setupKubernetes(t)
 	require.ParallelCapacity(t, 0)
@@ -533,26 +771,24 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 	var timelineCallNoLeader, timelineCall bool
 	r := Reconciler{
 		Client: client,
-		PodExec: func(ctx context.Context, namespace, pod, container string,
-			stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+		PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, req *http.Request) {
 			called = true
 			switch {
-			case timelineCall:
-				timelineCall = false
-				_, _ = stdout.Write([]byte(`[{"Cluster": "hippo-ha", "Member": "hippo-instance1-67mc-0", "Host": "hippo-instance1-67mc-0.hippo-pods", "Role": "Leader", "State": "running", "TL": 4}, {"Cluster": "hippo-ha", "Member": "hippo-instance1-ltcf-0", "Host": "hippo-instance1-ltcf-0.hippo-pods", "Role": "Replica", "State": "running", "TL": 4, "Lag in MB": 0}]`))
-			case timelineCallNoLeader:
-				_, _ = stdout.Write([]byte(`[{"Cluster": "hippo-ha", "Member": "hippo-instance1-ltcf-0", "Host": "hippo-instance1-ltcf-0.hippo-pods", "Role": "Replica", "State": "running", "TL": 4, "Lag in MB": 0}]`))
+			case req.URL.Path == "/cluster" && timelineCallNoLeader:
+				_, _ = w.Write([]byte(`{"members": [{"name": "hippo-instance1-ltcf-0", "host": "hippo-instance1-ltcf-0.hippo-pods", "role": "replica", "state": "streaming", "timeline": 4, "lag": 0}]}`))
+			case req.URL.Path == "/cluster" && (timelineCall || !callError):
+				_, _ = w.Write([]byte(`{"members": [{"name": "hippo-instance1-67mc-0", "host": "hippo-instance1-67mc-0.hippo-pods", "role": "leader", "state": "running", "timeline": 4}, {"name": "hippo-instance1-ltcf-0", "host": "hippo-instance1-ltcf-0.hippo-pods", "role": "replica", "state": "streaming", "timeline": 4, "lag": 0}]}`))
 			case callError:
-				return errors.New("boom")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("boom"))
 			case callFails:
-				_, _ = stdout.Write([]byte("bang"))
+				_, _ = w.Write([]byte("bang"))
 			case failover:
-				_, _ = stdout.Write([]byte("failed over"))
+				_, _ = w.Write([]byte(`Successfully failed over to "pod"`))
 			default:
-				_, _ = stdout.Write([]byte("switched over"))
+				_, _ = w.Write([]byte(`Successfully switched over to "pod"`))
 			}
-			return nil
-		},
+		}),
 	}
 
 	ctx := context.Background()
@@ -780,7 +1016,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		timelineCall, timelineCallNoLeader = false, false
 		called, failover, callError, callFails = false, false, true, false
 		err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
-		assert.Error(t, err, "boom")
+		assert.ErrorContains(t, err, "boom")
 		assert.Assert(t, called)
 		assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
 	})
@@ -923,7 +1159,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		timelineCall, timelineCallNoLeader = true, false
 		called, failover, callError, callFails = false, false, true, false
 		err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
-		assert.Error(t, err, "boom")
+		assert.ErrorContains(t, err, "boom")
 		assert.Assert(t, called)
 		assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
 	})
@@ -1003,3 +1239,498 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		assert.Assert(t, cluster.Status.Patroni.SwitchoverTimeline == nil)
 	})
 }
+
+func TestReconcileRestartRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	requested := metav1.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// runningInstance returns an instance of set with one running Pod.
+	runningInstance := func(set, name string, primary bool) *Instance {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Labels = map[string]string{naming.LabelInstance: name}
+		if primary {
+			pod.Labels[naming.LabelRole] = naming.RolePatroniLeader
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &Instance{
+			Name: name, Pods: []*corev1.Pod{pod}, Runner: &appsv1.StatefulSet{},
+			Spec: &v1beta1.PostgresInstanceSetSpec{Name: set},
+		}
+	}
+
+	// setup returns a Reconciler that calls the Patroni REST API of each
+	// member at a different path. Members report the start times in started.
+	setup := func(t *testing.T, started map[string]string, calls *[]string) *Reconciler {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			member, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+			*calls = append(*calls, r.Method+" "+member+" "+path)
+
+			switch path {
+			case "patroni":
+				_, _ = fmt.Fprintf(w, `{"state":"running","postmaster_start_time":%q}`, started[member])
+			case "switchover":
+				_, _ = w.Write([]byte(`Successfully switched over to "someone"`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		return &Reconciler{
+			Recorder: events.NewRecorder(t, runtime.Scheme),
+			PatroniClient: func(_ context.Context, _ *v1beta1.PostgresCluster, pod *corev1.Pod) (*patroni.Client, error) {
+				return &patroni.Client{
+					HTTP: server.Client(), Member: pod.Name,
+					URL: func(member string) string { return server.URL + "/" + member },
+				}, nil
+			},
+		}
+	}
+
+	before := "2025-03-01 11:00:00.123456+00:00"
+	after := "2025-03-01 12:30:00.123456+00:00"
+
+	t.Run("NotRequested", func(t *testing.T) {
+		var calls []string
+		r := setup(t, nil, &calls)
+
+		cluster := testCluster()
+		cluster.Status.RestartRequestedAt = &requested
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, cluster.Status.RestartRequestedAt == nil)
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("AlreadyDone", func(t *testing.T) {
+		var calls []string
+		r := setup(t, nil, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+		cluster.Status.RestartRequestedAt = requested.DeepCopy()
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("Paused", func(t *testing.T) {
+		var calls []string
+		r := setup(t, nil, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{Pause: initialize.Bool(true)}
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, restartRequestInterval)
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("NotRunning", func(t *testing.T) {
+		var calls []string
+		r := setup(t, nil, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+
+		instance := runningInstance("00", "a", true)
+		instance.Pods[0].Status.ContainerStatuses = nil
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{instance},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, restartRequestInterval)
+		assert.Assert(t, calls == nil)
+		assert.Assert(t, cluster.Status.RestartRequestedAt == nil)
+	})
+
+	t.Run("Future", func(t *testing.T) {
+		var calls []string
+		r := setup(t, nil, &calls)
+		recorder := r.Recorder.(*events.Recorder)
+
+		future := metav1.NewTime(time.Now().Add(time.Hour))
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = future.DeepCopy()
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, calls == nil)
+		assert.Assert(t, cluster.Status.RestartRequestedAt.Equal(&future))
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidRestartRequest")
+	})
+
+	t.Run("Fenced", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{"a-0": after}, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+
+		fenced := runningInstance("00", "b", false)
+		fenced.Pods = nil
+		fenced.Spec.Fence = []v1beta1.InstanceFence{{Name: "b", StopPostgres: initialize.Bool(true)}}
+
+		stopped := runningInstance("01", "c", false)
+		stopped.Pods = nil
+		stopped.Runner.Spec.Replicas = initialize.Int32(0)
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true), fenced, stopped},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.DeepEqual(t, calls, []string{"GET a-0 patroni"})
+		assert.Assert(t, cluster.Status.RestartRequestedAt.Equal(&requested))
+	})
+
+	t.Run("ReplicasFirst", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{
+			"a-0": before, "b-0": after, "c-0": before,
+		}, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{{Name: "00"}, {Name: "01"}}
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{
+				runningInstance("00", "a", true),
+				runningInstance("00", "b", false),
+				runningInstance("01", "c", false),
+			},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, restartRequestInterval)
+		assert.DeepEqual(t, calls, []string{
+			"GET a-0 patroni", "GET b-0 patroni", "GET c-0 patroni",
+			"POST c-0 restart",
+		})
+		assert.Equal(t, cluster.Status.InstanceSets[0].RestartedReplicas, int32(1))
+		assert.Equal(t, cluster.Status.InstanceSets[1].RestartedReplicas, int32(0))
+		assert.Assert(t, cluster.Status.RestartRequestedAt == nil)
+	})
+
+	t.Run("PrimaryLast", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{"a-0": before, "b-0": after}, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{
+				runningInstance("00", "a", true),
+				runningInstance("00", "b", false),
+			},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, restartRequestInterval)
+		assert.DeepEqual(t, calls, []string{
+			"GET a-0 patroni", "GET b-0 patroni", "POST a-0 switchover",
+		})
+	})
+
+	t.Run("PrimaryAlone", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{"a-0": before}, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, restartRequestInterval)
+		assert.DeepEqual(t, calls, []string{"GET a-0 patroni", "POST a-0 restart"})
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{"a-0": after, "b-0": after}, &calls)
+		recorder := r.Recorder.(*events.Recorder)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{{Name: "00"}}
+
+		requeue, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{
+				runningInstance("00", "a", true),
+				runningInstance("00", "b", false),
+			},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Equal(t, cluster.Status.InstanceSets[0].RestartedReplicas, int32(2))
+		assert.Assert(t, cluster.Status.RestartRequestedAt.Equal(&requested))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "RestartCompleted")
+	})
+
+	t.Run("BadStartTime", func(t *testing.T) {
+		var calls []string
+		r := setup(t, map[string]string{"a-0": "yesterday"}, &calls)
+
+		cluster := testCluster()
+		cluster.Spec.RestartRequestedAt = requested.DeepCopy()
+
+		_, err := r.reconcileRestartRequest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{runningInstance("00", "a", true)},
+		})
+		assert.ErrorContains(t, err, "yesterday")
+	})
+}
+
+func TestReconcilePatroniReinitialize(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	replica := func(name string) *Instance {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Labels = map[string]string{naming.LabelRole: naming.RolePatroniReplica}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}, Runner: &appsv1.StatefulSet{}}
+	}
+
+	cluster := testCluster()
+	cluster.Annotations = map[string]string{naming.PatroniReinitialize: "trigger"}
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		Reinitialize: &v1beta1.PatroniReinitialize{Enabled: true, TargetInstance: "some-instance"},
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.Reinitialize.Enabled = false
+		cluster.Status.Patroni.Reinitialize = initialize.String("old")
+		cluster.Status.Patroni.ReinitializeProgress = &v1beta1.PatroniReinitializeProgress{}
+
+		r := &Reconciler{}
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+		assert.Assert(t, cluster.Status.Patroni.ReinitializeProgress == nil)
+	})
+
+	t.Run("AlreadyDone", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Patroni.Reinitialize = initialize.String("trigger")
+
+		r := &Reconciler{}
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+	})
+
+	t.Run("AlreadyRejected", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Patroni.Reinitialize = initialize.String("trigger")
+		cluster.Status.Patroni.ReinitializeProgress = &v1beta1.PatroniReinitializeProgress{
+			Request: "trigger", State: v1beta1.PatroniReinitializeRejected,
+		}
+
+		r := &Reconciler{}
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Assert(t, cluster.Status.Patroni.ReinitializeProgress != nil,
+			"expected the details to remain")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{replica("other-instance")},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Equal(t, *cluster.Status.Patroni.Reinitialize, "trigger")
+
+		progress := cluster.Status.Patroni.ReinitializeProgress
+		assert.Equal(t, progress.State, v1beta1.PatroniReinitializeRejected)
+		assert.Assert(t, cmp.Contains(progress.Message, "not found"))
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "ReinitializeRejected")
+	})
+
+	t.Run("Primary", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+
+		instance := replica("some-instance")
+		instance.Pods[0].Labels[naming.LabelRole] = naming.RolePatroniLeader
+
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{instance},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Equal(t, *cluster.Status.Patroni.Reinitialize, "trigger")
+		assert.Equal(t, cluster.Status.Patroni.ReinitializeProgress.State,
+			v1beta1.PatroniReinitializeRejected)
+		assert.Equal(t, cluster.Status.Patroni.ReinitializeProgress.Message,
+			"the instance is the primary")
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "ReinitializeRejected")
+	})
+
+	t.Run("RejectedByPatroni", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{
+			Recorder: recorder,
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/reinitialize" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					_, _ = w.Write([]byte("Cluster has no leader, can not reinitialize\n"))
+					return
+				}
+				_, _ = w.Write([]byte(`{"state":"running"}`))
+			}),
+		}
+
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{replica("some-instance")},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+
+		progress := cluster.Status.Patroni.ReinitializeProgress
+		assert.Equal(t, progress.State, v1beta1.PatroniReinitializeRejected)
+		assert.Equal(t, progress.Message, "Cluster has no leader, can not reinitialize")
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Patroni.ReinitializeProgress = &v1beta1.PatroniReinitializeProgress{
+			Request: "trigger", Instance: "some-instance", State: v1beta1.PatroniReinitializeRunning,
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{
+			Recorder: recorder,
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"state":"start failed"}`))
+			}),
+		}
+
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{replica("some-instance")},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Equal(t, *cluster.Status.Patroni.Reinitialize, "trigger")
+		assert.Equal(t, cluster.Status.Patroni.ReinitializeProgress.State,
+			v1beta1.PatroniReinitializeFailed)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "ReinitializeFailed")
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		observed := &observedInstances{forCluster: []*Instance{replica("some-instance")}}
+
+		var calls []string
+		member := `{"state":"running","postmaster_start_time":"2020-01-01 00:00:00.000+00:00"}`
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{
+			Recorder: recorder,
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, r.Method+" "+r.URL.Path)
+				if r.URL.Path == "/patroni" {
+					_, _ = w.Write([]byte(member))
+				}
+			}),
+		}
+
+		// The first call starts the request.
+		requeue, err := r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, reinitializeInterval)
+		assert.DeepEqual(t, calls, []string{"GET /patroni", "POST /reinitialize"})
+
+		progress := cluster.Status.Patroni.ReinitializeProgress
+		assert.Assert(t, progress != nil)
+		assert.Equal(t, progress.Request, "trigger")
+		assert.Equal(t, progress.Instance, "some-instance")
+		assert.Equal(t, progress.State, v1beta1.PatroniReinitializeRunning)
+		assert.Equal(t, progress.PostmasterStartTime, "2020-01-01 00:00:00.000+00:00")
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+
+		// Patroni is creating the replica.
+		member = `{"state":"creating replica"}`
+		calls = nil
+		requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, reinitializeInterval)
+		assert.DeepEqual(t, calls, []string{"GET /patroni"})
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+
+		// PostgreSQL is streaming but has not restarted.
+		member = `{"state":"streaming","postmaster_start_time":"2020-01-01 00:00:00.000+00:00"}`
+		requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, reinitializeInterval)
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+
+		// PostgreSQL started again but needs another restart.
+		member = `{"state":"streaming","postmaster_start_time":"2020-01-01 00:00:00.001+00:00",` +
+			`"pending_restart":true}`
+		requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, reinitializeInterval)
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+
+		member = `{"state":"streaming","postmaster_start_time":"2020-01-01 00:00:00.001+00:00",` +
+			`"scheduled_restart":{"schedule":"2020-01-01T00:05:00+00:00"}}`
+		requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, reinitializeInterval)
+		assert.Assert(t, cluster.Status.Patroni.Reinitialize == nil)
+
+		// PostgreSQL started again, even within the same second.
+		member = `{"state":"streaming","postmaster_start_time":"2020-01-01 00:00:00.001+00:00"}`
+		requeue, err = r.reconcilePatroniReinitialize(ctx, cluster, observed)
+		assert.NilError(t, err)
+		assert.Equal(t, requeue, time.Duration(0))
+		assert.Equal(t, *cluster.Status.Patroni.Reinitialize, "trigger")
+		assert.Assert(t, cluster.Status.Patroni.ReinitializeProgress == nil)
+
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[0].Reason, "ReinitializeStarted")
+		assert.Equal(t, recorder.Events[1].Reason, "ReinitializeCompleted")
+	})
+}
@@ -854,43 +854,44 @@ func generateBackupJobSpecIntent(ctx context.Context, postgresCluster *v1beta1.P
 
 // +kubebuilder:rbac:groups="",resources="configmaps",verbs={delete,list}
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={list,delete}
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={get}
 // +kubebuilder:rbac:groups="",resources="endpoints",verbs={get}
 // +kubebuilder:rbac:groups="batch",resources="jobs",verbs={list}
 
 // observeRestoreEnv observes the current Kubernetes environment to obtain any resources applicable
 // to performing pgBackRest restores (e.g. when initializing a new cluster using an existing
 // pgBackRest backup, or when restoring in-place).  This includes finding any existing Endpoints
-// created by Patroni (i.e. DCS, leader and failover Endpoints), while then also finding any existing
-// restore Jobs and then updating pgBackRest restore status accordingly.
+// or ConfigMaps created by Patroni (i.e. DCS, leader and failover objects), while then also
+// finding any existing restore Jobs and then updating pgBackRest restore status accordingly.
 func (r *Reconciler) observeRestoreEnv(ctx context.Context,
-	cluster *v1beta1.PostgresCluster) ([]corev1.Endpoints, *batchv1.Job, error) {
+	cluster *v1beta1.PostgresCluster) ([]client.Object, *batchv1.Job, error) {
 
-	// lookup the various patroni endpoints
-	leaderEP, dcsEP, failoverEP := corev1.Endpoints{}, corev1.Endpoints{}, corev1.Endpoints{}
-	currentEndpoints := []corev1.Endpoints{}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniLeaderEndpoints(cluster)),
-		&leaderEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, leaderEP)
+	// lookup the various patroni endpoints or configmaps
+	leader := naming.PatroniLeaderEndpoints(cluster)
+	newObject := func(meta metav1.ObjectMeta) client.Object {
+		return &corev1.Endpoints{ObjectMeta: meta}
 	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniDistributedConfiguration(cluster)),
-		&dcsEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
+	if !patroni.UseEndpoints(cluster) {
+		leader = naming.PatroniLeaderConfigMap(cluster)
+		newObject = func(meta metav1.ObjectMeta) client.Object {
+			return &corev1.ConfigMap{ObjectMeta: meta}
 		}
-	} else {
-		currentEndpoints = append(currentEndpoints, dcsEP)
 	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniTrigger(cluster)),
-		&failoverEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
+
+	currentEndpoints := []client.Object{}
+	for _, meta := range []metav1.ObjectMeta{
+		leader,
+		naming.PatroniDistributedConfiguration(cluster),
+		naming.PatroniTrigger(cluster),
+	} {
+		object := newObject(meta)
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(object), object); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, errors.WithStack(err)
+			}
+		} else {
+			currentEndpoints = append(currentEndpoints, object)
 		}
-	} else {
-		currentEndpoints = append(currentEndpoints, failoverEP)
 	}
 
 	restoreJobs := &batchv1.JobList{}
@@ -985,12 +986,12 @@ func (r *Reconciler) observeRestoreEnv(ctx context.Context,
 
 // prepareForRestore is responsible for reconciling an in place restore for the PostgresCluster.
 // This includes setting a "PreparingForRestore" condition, and then removing all existing
-// instance runners, as well as any Endpoints created by Patroni.  And once the cluster is no
-// longer running, the "PostgresDataInitialized" condition is removed, which will cause the
-// cluster to re-bootstrap using a restored data directory.
+// instance runners, as well as any Endpoints or ConfigMaps created by Patroni.  And once the
+// cluster is no longer running, the "PostgresDataInitialized" condition is removed, which will
+// cause the cluster to re-bootstrap using a restored data directory.
 func (r *Reconciler) prepareForRestore(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, observed *observedInstances,
-	currentEndpoints []corev1.Endpoints, restoreJob *batchv1.Job, restoreID string) error {
+	currentEndpoints []client.Object, restoreJob *batchv1.Job, restoreID string) error {
 
 	setPreparingClusterCondition := func(resource string) {
 		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
@@ -1092,9 +1093,9 @@ func (r *Reconciler) prepareForRestore(ctx context.Context,
 	}
 
 	setPreparingClusterCondition("removing DCS")
-	// delete any Endpoints
+	// delete any Endpoints or ConfigMaps
 	for i := range currentEndpoints {
-		if err := r.Client.Delete(ctx, &currentEndpoints[i]); client.IgnoreNotFound(err) != nil {
+		if err := r.Client.Delete(ctx, currentEndpoints[i]); client.IgnoreNotFound(err) != nil {
 			return errors.WithStack(err)
 		}
 	}
@@ -3004,7 +3005,7 @@ func (r *Reconciler) reconcilePGBackRestCronJob(
 	// started will continue.
 	// - https://docs.k8s.io/reference/kubernetes-api/workload-resources/cron-job-v1beta1/#CronJobSpec
 	suspend := (cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown) ||
-		(cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled)
+		standbyInEffect(ctx, cluster)
 
 	pgBackRestCronJob := &batchv1.CronJob{
 		ObjectMeta: objectmeta,
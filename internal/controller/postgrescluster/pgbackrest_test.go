@@ -3238,13 +3238,13 @@ func TestPrepareForRestore(t *testing.T) {
 	for _, dedicated := range []bool{true, false} {
 		testCases := []struct {
 			desc            string
-			createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints)
+			createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object)
 			fakeObserved    *observedInstances
 			result          testResult
 		}{{
 			desc: "remove restore jobs",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
 				job := generateJob(cluster.Name)
 				assert.NilError(t, r.Client.Create(ctx, job))
 				return job, nil
@@ -3262,7 +3262,7 @@ func TestPrepareForRestore(t *testing.T) {
 		}, {
 			desc: "remove patroni endpoints",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
 				fakeLeaderEP := corev1.Endpoints{}
 				fakeLeaderEP.ObjectMeta = naming.PatroniLeaderEndpoints(cluster)
 				fakeLeaderEP.ObjectMeta.Namespace = namespace
@@ -3275,7 +3275,7 @@ func TestPrepareForRestore(t *testing.T) {
 				fakeFailoverEP.ObjectMeta = naming.PatroniTrigger(cluster)
 				fakeFailoverEP.ObjectMeta.Namespace = namespace
 				assert.NilError(t, r.Client.Create(ctx, &fakeFailoverEP))
-				return nil, []corev1.Endpoints{fakeLeaderEP, fakeDCSEP, fakeFailoverEP}
+				return nil, []client.Object{&fakeLeaderEP, &fakeDCSEP, &fakeFailoverEP}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -3290,8 +3290,8 @@ func TestPrepareForRestore(t *testing.T) {
 		}, {
 			desc: "cluster fully prepared",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
-				return nil, []corev1.Endpoints{}
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
+				return nil, []client.Object{}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -3315,8 +3315,8 @@ func TestPrepareForRestore(t *testing.T) {
 				}}},
 			}},
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
-				return nil, []corev1.Endpoints{}
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
+				return nil, []client.Object{}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -11,9 +11,14 @@ import (
 	"io"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -21,15 +26,19 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crunchydata/postgres-operator/internal/config"
 	"github.com/crunchydata/postgres-operator/internal/feature"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
 	"github.com/crunchydata/postgres-operator/internal/pgaudit"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgis"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	pgpassword "github.com/crunchydata/postgres-operator/internal/postgres/password"
@@ -40,10 +49,29 @@ import (
 // generatePostgresUserSecret returns a Secret containing a password and
 // connection details for the first database in spec. When existing is nil or
 // lacks a password or verifier, a new password and verifier are generated.
+// A new password is also generated when the rotation policy in spec calls for
+// one; see [v1beta1.PostgresPasswordRotationSpec]. When the password of spec
+// comes from an external source, no password is generated or stored and the
+// existing verifier is kept; see [Reconciler.setPostgresUserPasswordFromSource].
 func (r *Reconciler) generatePostgresUserSecret(
 	cluster *v1beta1.PostgresCluster, spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
 ) (*corev1.Secret, error) {
+	var rotation *v1beta1.PostgresPasswordRotationSpec
+	if spec.Password != nil {
+		rotation = spec.Password.Rotation
+	}
+	source := spec.Password.GetSource()
+	if source != nil {
+		rotation = nil
+	}
+	var grace time.Duration
+	if rotation != nil && rotation.GracePeriod != nil {
+		grace = rotation.GracePeriod.Duration
+	}
+
+	now := time.Now()
 	username := string(spec.Name)
+	alternate := postgres.AlternateUserName(username)
 	intent := &corev1.Secret{ObjectMeta: naming.PostgresUserSecret(cluster, username)}
 	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
 	initialize.Map(&intent.Data)
@@ -57,16 +85,65 @@ func (r *Reconciler) generatePostgresUserSecret(
 
 	intent.Data["host"] = []byte(hostname)
 	intent.Data["port"] = []byte(port)
-	intent.Data["user"] = []byte(username)
 
-	// Use the existing password and verifier.
+	// The role that has the current password. During a grace period, this
+	// alternates between the user and its alternate role.
+	active := username
+
+	// Use the existing password and verifier. Passwords are considered
+	// rotated when their Secret was created, unless annotated otherwise.
+	var previousExpiresAt, rotatedAt time.Time
 	if existing != nil {
-		intent.Data["password"] = existing.Data["password"]
+		if source == nil {
+			intent.Data["password"] = existing.Data["password"]
+		}
 		intent.Data["verifier"] = existing.Data["verifier"]
+
+		rotatedAt = existing.CreationTimestamp.Time
+		if t, err := time.Parse(time.RFC3339, existing.Annotations[naming.PasswordRotatedAt]); err == nil {
+			rotatedAt = t
+		}
+
+		// Keep the previous verifier until it expires.
+		if grace > 0 {
+			if string(existing.Data["user"]) == alternate {
+				active = alternate
+			}
+			if t, err := time.Parse(time.RFC3339, existing.Annotations[naming.PasswordPreviousExpiresAt]); err == nil &&
+				now.Before(t) && len(existing.Data["previous-verifier"]) > 0 {
+				intent.Data["previous-verifier"] = existing.Data["previous-verifier"]
+				previousExpiresAt = t
+			}
+		}
 	}
 
+	// Discard the current password when it is due for rotation. During a grace
+	// period, the current verifier becomes the previous one and the new
+	// password goes to the other role.
+	if rotation != nil && len(intent.Data["password"]) > 0 {
+		due := rotation.Interval != nil && !now.Before(rotatedAt.Add(rotation.Interval.Duration))
+		requested := rotation.RequestedAt != nil && rotation.RequestedAt.After(rotatedAt) &&
+			!rotation.RequestedAt.After(now)
+
+		if due || requested {
+			if grace > 0 && len(intent.Data["verifier"]) > 0 {
+				intent.Data["previous-verifier"] = intent.Data["verifier"]
+				previousExpiresAt = now.Add(grace)
+
+				if active == username {
+					active = alternate
+				} else {
+					active = username
+				}
+			}
+			intent.Data["password"] = nil
+		}
+	}
+
+	intent.Data["user"] = []byte(active)
+
 	// When password is unset, generate a new one according to the specified policy.
-	if len(intent.Data["password"]) == 0 {
+	if len(intent.Data["password"]) == 0 && source == nil {
 		// NOTE: The tests around ASCII passwords are lacking. When changing
 		// this, make sure that ASCII is the default.
 		generate := util.GenerateASCIIPassword
@@ -83,13 +160,14 @@ func (r *Reconciler) generatePostgresUserSecret(
 		}
 		intent.Data["password"] = []byte(password)
 		intent.Data["verifier"] = nil
+		rotatedAt = now
 	}
 
 	// When a password has been generated or the verifier is empty,
 	// generate a verifier based on the current password.
 	// NOTE(cbandy): We don't have a function to compare a plaintext
 	// password to a SCRAM verifier.
-	if len(intent.Data["verifier"]) == 0 {
+	if len(intent.Data["verifier"]) == 0 && source == nil {
 		verifier, err := pgpassword.NewSCRAMPassword(string(intent.Data["password"])).Build()
 		if err != nil {
 			return nil, errors.WithStack(err)
@@ -98,14 +176,18 @@ func (r *Reconciler) generatePostgresUserSecret(
 	}
 
 	// When a database has been specified, include it and a connection URI.
+	// Connection URIs contain the password, so they are omitted when the
+	// password comes from an external source.
 	// - https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
 	if len(spec.Databases) > 0 {
+		intent.Data["dbname"] = []byte(spec.Databases[0])
+	}
+	if len(spec.Databases) > 0 && source == nil {
 		database := string(spec.Databases[0])
 
-		intent.Data["dbname"] = []byte(database)
 		intent.Data["uri"] = []byte((&url.URL{
 			Scheme: "postgresql",
-			User:   url.UserPassword(username, string(intent.Data["password"])),
+			User:   url.UserPassword(active, string(intent.Data["password"])),
 			Host:   net.JoinHostPort(hostname, port),
 			Path:   database,
 		}).String())
@@ -113,7 +195,7 @@ func (r *Reconciler) generatePostgresUserSecret(
 		// The JDBC driver requires a different URI scheme and query component.
 		// - https://jdbc.postgresql.org/documentation/use/#connection-parameters
 		query := url.Values{}
-		query.Set("user", username)
+		query.Set("user", active)
 		query.Set("password", string(intent.Data["password"]))
 		intent.Data["jdbc-uri"] = []byte((&url.URL{
 			Scheme:   "jdbc:postgresql",
@@ -132,12 +214,12 @@ func (r *Reconciler) generatePostgresUserSecret(
 		intent.Data["pgbouncer-host"] = []byte(hostname)
 		intent.Data["pgbouncer-port"] = []byte(port)
 
-		if len(spec.Databases) > 0 {
+		if len(spec.Databases) > 0 && source == nil {
 			database := string(spec.Databases[0])
 
 			intent.Data["pgbouncer-uri"] = []byte((&url.URL{
 				Scheme: "postgresql",
-				User:   url.UserPassword(username, string(intent.Data["password"])),
+				User:   url.UserPassword(active, string(intent.Data["password"])),
 				Host:   net.JoinHostPort(hostname, port),
 				Path:   database,
 			}).String())
@@ -148,7 +230,7 @@ func (r *Reconciler) generatePostgresUserSecret(
 			// - https://jdbc.postgresql.org/documentation/use/#connection-parameters
 			// - https://www.pgbouncer.org/faq.html#how-to-use-prepared-statements-with-transaction-pooling
 			query := url.Values{}
-			query.Set("user", username)
+			query.Set("user", active)
 			query.Set("password", string(intent.Data["password"]))
 			query.Set("prepareThreshold", "0")
 			intent.Data["pgbouncer-jdbc-uri"] = []byte((&url.URL{
@@ -161,6 +243,27 @@ func (r *Reconciler) generatePostgresUserSecret(
 	}
 
 	intent.Annotations = cluster.Spec.Metadata.GetAnnotationsOrNil()
+
+	// Record how to reclaim the user after it is removed from the spec.
+	switch spec.ReclaimPolicy {
+	case v1beta1.PostgresUserReclaimDrop, v1beta1.PostgresUserReclaimRevoke:
+		intent.Annotations = naming.Merge(intent.Annotations, map[string]string{
+			naming.PostgresUserReclaimPolicy: spec.ReclaimPolicy,
+		})
+	}
+
+	// Record when the password was generated and when the previous one expires.
+	if rotation != nil {
+		intent.Annotations = naming.Merge(intent.Annotations, map[string]string{
+			naming.PasswordRotatedAt: rotatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if len(intent.Data["previous-verifier"]) > 0 {
+		intent.Annotations = naming.Merge(intent.Annotations, map[string]string{
+			naming.PasswordPreviousExpiresAt: previousExpiresAt.UTC().Format(time.RFC3339),
+		})
+	}
+
 	intent.Labels = naming.Merge(
 		cluster.Spec.Metadata.GetLabelsOrNil(),
 		map[string]string{
@@ -221,7 +324,27 @@ func (r *Reconciler) reconcilePostgresDatabases(
 			}
 		}
 	}
+	// Databases in cluster.Spec.Databases are created with their attributes
+	// or not at all, so leave them out of the list of databases to create.
+	declared := sets.Set[string]{}
+	for _, database := range cluster.Spec.Databases {
+		declared.Insert(string(database.Name))
+	}
+	undeclared := sets.List(databases.Difference(declared))
+	databases = databases.Union(declared)
+
+	// Drop databases that were removed from the spec with a "Drop" policy,
+	// unless they are still necessary.
+	var drop []string
+	for _, status := range cluster.Status.Databases {
+		if status.ReclaimPolicy == v1beta1.PostgresDatabaseReclaimDrop &&
+			!databases.Has(status.Name) {
+			drop = append(drop, status.Name)
+		}
+	}
 
+	var installed map[string]map[string]string
+	var observed map[string]v1beta1.PostgresDatabaseStatus
 	var pgAuditOK, postgisInstallOK bool
 	create := func(ctx context.Context, exec postgres.Executor) error {
 		if pgAuditOK = pgaudit.EnableInPostgreSQL(ctx, exec) == nil; !pgAuditOK {
@@ -245,11 +368,31 @@ func (r *Reconciler) reconcilePostgresDatabases(
 				"Unable to install PostGIS")
 		}
 
-		return postgres.CreateDatabasesInPostgreSQL(ctx, exec, sets.List(databases))
+		var err error
+		observed, err = postgres.WriteDatabasesInPostgreSQL(ctx, exec, cluster.Spec.Databases, drop)
+		if err == nil {
+			err = postgres.CreateDatabasesInPostgreSQL(ctx, exec, undeclared)
+		}
+		if err == nil {
+			installed, err = postgres.WriteExtensionsInPostgreSQL(ctx, exec, cluster.Spec.Databases)
+		}
+		return err
 	}
 
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
 	revision, err := safeHash32(func(hasher io.Writer) error {
+		// Extensions are updated to the default versions of a new image, so
+		// include the image when there are any.
+		if slices.ContainsFunc(cluster.Spec.Databases, func(d v1beta1.PostgresDatabaseSpec) bool {
+			return len(d.Extensions) > 0
+		}) {
+			for _, container := range pod.Spec.Containers {
+				if container.Name == naming.ContainerDatabase {
+					_, _ = fmt.Fprint(hasher, container.Image)
+				}
+			}
+		}
+
 		// Discard log messages about executing SQL.
 		return create(logging.NewContext(ctx, logging.Discard()), func(
 			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
@@ -277,23 +420,102 @@ func (r *Reconciler) reconcilePostgresDatabases(
 		log := logging.FromContext(ctx).WithValues("revision", revision)
 		err = errors.WithStack(create(logging.NewContext(ctx, log), podExecutor))
 	}
-	if err == nil && pgAuditOK && postgisInstallOK {
+	// Try again later when any extensions are not as specified.
+	if err == nil && r.setPostgresDatabasesStatus(cluster, observed, installed) &&
+		pgAuditOK && postgisInstallOK {
 		cluster.Status.DatabaseRevision = revision
 	}
 
 	return err
 }
 
+// setPostgresDatabasesStatus reports the state of the databases in
+// cluster.Spec.Databases and the installed versions of their extensions. It
+// emits warning events and returns false when any are not as specified. Dropped
+// databases that still exist remain in the status so they are dropped later.
+func (r *Reconciler) setPostgresDatabasesStatus(
+	cluster *v1beta1.PostgresCluster,
+	observed map[string]v1beta1.PostgresDatabaseStatus,
+	installed map[string]map[string]string,
+) bool {
+	var databases, extensions []string
+	var statuses []v1beta1.PostgresDatabaseStatus
+
+	for _, database := range cluster.Spec.Databases {
+		status, exists := observed[string(database.Name)]
+		status.Name = string(database.Name)
+		status.ReclaimPolicy = database.ReclaimPolicy
+
+		if !exists {
+			databases = append(databases, fmt.Sprintf("%q does not exist", database.Name))
+		} else if database.Owner != "" && status.Owner != string(database.Owner) {
+			databases = append(databases, fmt.Sprintf("%q is not owned by %q", database.Name, database.Owner))
+		}
+
+		for _, extension := range database.Extensions {
+			version := installed[status.Name][string(extension.Name)]
+			status.Extensions = append(status.Extensions, v1beta1.PostgresExtensionStatus{
+				Name:             string(extension.Name),
+				RequestedVersion: extension.Version,
+				InstalledVersion: version,
+			})
+
+			if version == "" || (extension.Version != "" && extension.Version != version) {
+				extensions = append(extensions, fmt.Sprintf("%s in %q", extension.Name, database.Name))
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, previous := range cluster.Status.Databases {
+		if status, exists := observed[previous.Name]; exists &&
+			previous.ReclaimPolicy == v1beta1.PostgresDatabaseReclaimDrop &&
+			!slices.ContainsFunc(statuses, func(s v1beta1.PostgresDatabaseStatus) bool {
+				return s.Name == previous.Name
+			}) {
+			status.ReclaimPolicy = previous.ReclaimPolicy
+			statuses = append(statuses, status)
+			databases = append(databases, fmt.Sprintf("%q was not dropped", previous.Name))
+		}
+	}
+
+	if len(databases) > 0 {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DatabasesNotReady",
+			"Databases are not as specified: %s", strings.Join(databases, ", "))
+	}
+	if len(extensions) > 0 {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ExtensionsNotReady",
+			"Unable to install or update extensions: %s", strings.Join(extensions, ", "))
+	}
+
+	cluster.Status.Databases = statuses
+	return len(databases)+len(extensions) == 0
+}
+
+// passwordFileInterval is how often password files are read again. Changes to
+// files do not otherwise cause a reconcile.
+const passwordFileInterval = time.Minute
+
 // reconcilePostgresUsers writes the objects necessary to manage users and their
-// passwords in PostgreSQL.
+// passwords in PostgreSQL. It returns how long until a password needs to be
+// rotated, a previous password expires, or password files should be read
+// again, if ever.
 func (r *Reconciler) reconcilePostgresUsers(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
-) error {
+	root *pki.RootCertificateAuthority,
+) (time.Duration, error) {
 	r.validatePostgresUsers(cluster)
 
-	users, secrets, err := r.reconcilePostgresUserSecrets(ctx, cluster)
+	var next time.Duration
+	users, secrets, removed, err := r.reconcilePostgresUserSecrets(ctx, cluster, root)
 	if err == nil {
-		err = r.reconcilePostgresUsersInPostgreSQL(ctx, cluster, instances, users, secrets)
+		err = r.reconcilePostgresUsersInPostgreSQL(ctx, cluster, instances, users, secrets, removed)
+	}
+	if err == nil {
+		next = nextPasswordRotation(time.Now(), users, secrets)
+		if read := nextPasswordFileRead(users); read > 0 && (next == 0 || read < next) {
+			next = read
+		}
 	}
 	if err == nil {
 		// Copy PostgreSQL users and passwords into pgAdmin. This is here because
@@ -302,7 +524,58 @@ func (r *Reconciler) reconcilePostgresUsers(
 		// are available here, too.
 		err = r.reconcilePGAdminUsers(ctx, cluster, users, secrets)
 	}
-	return err
+	return next, err
+}
+
+// nextPasswordFileRead returns how long until the passwords of specUsers should
+// be read from their files again. It returns zero when none have a file.
+func nextPasswordFileRead(specUsers []v1beta1.PostgresUserSpec) time.Duration {
+	for i := range specUsers {
+		if source := specUsers[i].Password.GetSource(); source != nil && source.File != "" {
+			return passwordFileInterval
+		}
+	}
+	return 0
+}
+
+// nextPasswordRotation returns how long after now the first of secrets needs
+// a new password or loses its previous password. A new password is needed at
+// the end of an interval or at a requested time in the future. It returns zero
+// when none do.
+func nextPasswordRotation(
+	now time.Time, specUsers []v1beta1.PostgresUserSpec, userSecrets map[string]*corev1.Secret,
+) time.Duration {
+	var next time.Duration
+	consider := func(at time.Time) {
+		// Wait at least one second so that timestamps in annotations, which
+		// have second precision, have passed.
+		if d := max(at.Sub(now), time.Second); next == 0 || d < next {
+			next = d
+		}
+	}
+
+	for i := range specUsers {
+		rotation := specUsers[i].Password.GetRotation()
+		secret := userSecrets[string(specUsers[i].Name)]
+		if rotation == nil || secret == nil {
+			continue
+		}
+
+		if t, err := time.Parse(time.RFC3339,
+			secret.Annotations[naming.PasswordRotatedAt]); err == nil {
+			if rotation.Interval != nil {
+				consider(t.Add(rotation.Interval.Duration))
+			}
+			if rotation.RequestedAt != nil && rotation.RequestedAt.After(t) && rotation.RequestedAt.After(now) {
+				consider(rotation.RequestedAt.Time)
+			}
+		}
+		if t, err := time.Parse(time.RFC3339,
+			secret.Annotations[naming.PasswordPreviousExpiresAt]); err == nil {
+			consider(t)
+		}
+	}
+	return next
 }
 
 // validatePostgresUsers emits warnings when cluster.Spec.Users contains values
@@ -339,17 +612,192 @@ func (r *Reconciler) validatePostgresUsers(cluster *v1beta1.PostgresCluster) {
 	}
 }
 
+// setPostgresHBAs stores the HBA rules of cluster.Spec.Authentication in
+// pgHBAs. Any that cannot be applied are recorded in the status of cluster
+// and reported in an Event when they change.
+func (r *Reconciler) setPostgresHBAs(cluster *v1beta1.PostgresCluster, pgHBAs *postgres.HBAs) {
+	var rejected []string
+	if cluster.Spec.Authentication != nil {
+		pgHBAs.Specified, rejected = postgres.SpecifiedHBAs(cluster.Spec.Authentication.Rules)
+	}
+
+	var previous []string
+	if cluster.Status.Authentication != nil {
+		previous = cluster.Status.Authentication.Rejected
+	}
+	if len(rejected) > 0 && !slices.Equal(rejected, previous) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidAuthenticationRules",
+			"HBA rules were not applied: "+strings.Join(rejected, "; "))
+	}
+
+	cluster.Status.Authentication = nil
+	if len(rejected) > 0 {
+		cluster.Status.Authentication = &v1beta1.PostgresAuthenticationStatus{Rejected: rejected}
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// setPostgresUserHBAs stores the HBA rules and user name maps of users in
+// cluster.Spec.Users that authenticate other than with a password. Any that
+// cannot be applied are reported in an Event. Users whose LDAP bind password
+// cannot be read, or cannot be kept out of Patroni configuration, have no rules.
+func (r *Reconciler) setPostgresUserHBAs(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pgHBAs *postgres.HBAs,
+) error {
+	var problems []string
+	var users []v1beta1.PostgresUserSpec
+	bindPasswords := map[string]string{}
+
+	keytab := postgres.KerberosKeytab(cluster)
+
+	for _, user := range cluster.Spec.Users {
+		auth := user.Authentication
+		if user.Name == "postgres" || auth == nil {
+			continue
+		}
+
+		if auth.GSS != nil && keytab != nil &&
+			(auth.GSS.Keytab.Name != keytab.Name || auth.GSS.Keytab.Key != keytab.Key) {
+			problems = append(problems, fmt.Sprintf(
+				"user %q: keytab differs from Secret %q key %q and is ignored",
+				user.Name, keytab.Name, keytab.Key))
+		}
+
+		// Bind passwords are included from a file so they are not stored in
+		// Patroni configuration. A user without one would search anonymously.
+		if auth.LDAP != nil && auth.LDAP.BindPassword != nil {
+			if !postgres.IncludesConfidentialHBAs(cluster) {
+				problems = append(problems, fmt.Sprintf(
+					"user %q: bind passwords require PostgreSQL 16 or later", user.Name))
+				continue
+			}
+
+			selector := auth.LDAP.BindPassword
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: selector.Name, Namespace: cluster.Namespace,
+			}}
+			err := errors.WithStack(client.IgnoreNotFound(
+				r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret)))
+			if err != nil {
+				return err
+			}
+
+			if password := secret.Data[selector.Key]; len(password) > 0 {
+				bindPasswords[string(user.Name)] = string(password)
+			} else {
+				problems = append(problems, fmt.Sprintf(
+					"user %q: bind password Secret %q key %q does not exist or is empty",
+					user.Name, selector.Name, selector.Key))
+				continue
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	rejected := postgres.UserHBAs(users, bindPasswords, pgHBAs)
+
+	if problems = append(problems, rejected...); len(problems) > 0 {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidUserAuthentication",
+			"User authentication was not fully applied: "+strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// instanceSetParameters checks the parameters in the config of set and
+// returns those that can be applied along with the mandatory ones. It also
+// returns a description of each parameter that cannot be applied.
+func instanceSetParameters(
+	cluster *v1beta1.PostgresCluster, set *v1beta1.PostgresInstanceSetSpec,
+	mandatory *postgres.ParameterSet,
+) (postgres.Parameters, []string) {
+	var parameters map[string]intstr.IntOrString
+	if set.Config != nil {
+		parameters = set.Config.Parameters
+	}
+
+	specified, rejected := postgres.SpecifiedParameters(
+		cluster.Spec.PostgresVersion, parameters, mandatory)
+	return postgres.Parameters{Mandatory: mandatory, Specified: specified}, rejected
+}
+
+// setPostgresParameters checks the parameters in cluster.Spec.Config and
+// stores those that can be applied in pgParameters. The rest, and those of
+// instance sets that cannot be applied, are recorded in the status of cluster
+// and reported in an Event when they change.
+func (r *Reconciler) setPostgresParameters(
+	cluster *v1beta1.PostgresCluster, pgParameters *postgres.Parameters,
+) {
+	specified, rejected := postgres.SpecifiedParameters(
+		cluster.Spec.PostgresVersion, cluster.Spec.Config.Parameters, pgParameters.Mandatory)
+	pgParameters.Specified = specified
+
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		_, problems := instanceSetParameters(cluster, set, pgParameters.Mandatory)
+		for _, problem := range problems {
+			rejected = append(rejected, fmt.Sprintf("instance set %q: %s", set.Name, problem))
+		}
+	}
+
+	var previous []string
+	if cluster.Status.Parameters != nil {
+		previous = cluster.Status.Parameters.Rejected
+	}
+	if len(rejected) > 0 && !slices.Equal(rejected, previous) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidParameters",
+			"PostgreSQL parameters were not applied: "+strings.Join(rejected, "; "))
+	}
+
+	if len(rejected) == 0 {
+		rejected = nil
+	}
+	if cluster.Status.Parameters == nil {
+		cluster.Status.Parameters = new(v1beta1.PostgresParametersStatus)
+	}
+	cluster.Status.Parameters.Rejected = rejected
+
+	if len(rejected)+len(cluster.Status.Parameters.PendingRestart) == 0 {
+		cluster.Status.Parameters = nil
+	}
+}
+
+// observePendingRestart records in the status of cluster the parameters that
+// Patroni reports as pending a restart of any instance.
+func observePendingRestart(cluster *v1beta1.PostgresCluster, instances *observedInstances) {
+	var pending []string
+	for _, instance := range instances.forCluster {
+		for _, pod := range instance.Pods {
+			pending = append(pending, patroni.PodPendingRestartParameters(pod)...)
+		}
+	}
+	slices.Sort(pending)
+	pending = slices.Compact(pending)
+
+	if cluster.Status.Parameters == nil {
+		cluster.Status.Parameters = new(v1beta1.PostgresParametersStatus)
+	}
+	cluster.Status.Parameters.PendingRestart = pending
+
+	if len(cluster.Status.Parameters.Rejected)+len(pending) == 0 {
+		cluster.Status.Parameters = nil
+	}
+}
+
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={list}
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={create,delete,patch}
 
 // reconcilePostgresUserSecrets writes Secrets for the PostgreSQL users
 // specified in cluster and deletes existing Secrets that are not specified.
 // It returns the user specifications it acted on (because defaults) and the
-// Secrets it wrote.
+// Secrets it wrote. It also returns the Secrets of users that are not
+// specified but still need to be reclaimed in PostgreSQL; those are not deleted.
+// Users with certificate authentication get a client certificate signed by root.
 func (r *Reconciler) reconcilePostgresUserSecrets(
-	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	ctx context.Context, cluster *v1beta1.PostgresCluster, root *pki.RootCertificateAuthority,
 ) (
-	[]v1beta1.PostgresUserSpec, map[string]*corev1.Secret, error,
+	[]v1beta1.PostgresUserSpec, map[string]*corev1.Secret, []*corev1.Secret, error,
 ) {
 	// When users are unspecified, create one user matching the cluster name if
 	// it is also a valid user name.
@@ -436,10 +884,13 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 	// Index secrets by PostgreSQL user name and delete any that are not in the
 	// cluster spec. Keep track of the deprecated default secret to migrate its
 	// contents when the current secret doesn't exist.
+	// Secrets of users that need to be revoked or dropped are kept until that
+	// is done in PostgreSQL.
 	var (
 		defaultSecret     *corev1.Secret
 		defaultSecretName = naming.DeprecatedPostgresUserSecret(cluster).Name
 		defaultUserName   string
+		removedSecrets    []*corev1.Secret
 		userSecrets       = make(map[string]*corev1.Secret, len(secrets.Items))
 	)
 	if err == nil {
@@ -454,6 +905,8 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 				} else {
 					userSecrets[secretUserName] = secret
 				}
+			} else if reclaimPostgresUserPolicy(secret) != v1beta1.PostgresUserReclaimRetain {
+				removedSecrets = append(removedSecrets, secret)
 			} else if err == nil {
 				err = errors.WithStack(r.deleteControlled(ctx, cluster, secret))
 			}
@@ -473,19 +926,160 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 		if err == nil {
 			userSecrets[userName], err = r.generatePostgresUserSecret(cluster, user, secret)
 		}
+		if err == nil {
+			err = r.setPostgresUserPasswordFromSource(ctx, cluster, user, userSecrets[userName])
+		}
+		if err == nil {
+			err = setPostgresUserCertificate(root, user, secret, userSecrets[userName])
+		}
 		if err == nil {
 			err = errors.WithStack(r.apply(ctx, userSecrets[userName]))
 		}
 	}
 
-	return specUsers, userSecrets, err
+	return specUsers, userSecrets, removedSecrets, err
+}
+
+// setPostgresUserCertificate stores a client certificate for spec in intent
+// when spec authenticates with a certificate. The certificate in existing is
+// kept while it is valid and signed by root.
+func setPostgresUserCertificate(
+	root *pki.RootCertificateAuthority, spec *v1beta1.PostgresUserSpec,
+	existing, intent *corev1.Secret,
+) error {
+	if root == nil || spec.Name == "postgres" ||
+		spec.Authentication == nil || spec.Authentication.Certificate == nil {
+		return nil
+	}
+
+	leaf := &pki.LeafCertificate{}
+	commonName := string(spec.Name)
+	dnsNames := []string{commonName}
+
+	if existing != nil {
+		// Unmarshal and validate the stored leaf. These first errors can
+		// be ignored because they result in an invalid leaf which is then
+		// correctly regenerated.
+		_ = leaf.Certificate.UnmarshalText(existing.Data["tls.crt"])
+		_ = leaf.PrivateKey.UnmarshalText(existing.Data["tls.key"])
+	}
+
+	leaf, err := root.RegenerateLeafWhenNecessary(leaf, commonName, dnsNames)
+	err = errors.WithStack(err)
+
+	if err == nil {
+		intent.Data["tls.crt"], err = leaf.Certificate.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data["tls.key"], err = leaf.PrivateKey.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data["ca.crt"], err = root.Certificate.MarshalText()
+		err = errors.WithStack(err)
+	}
+	return err
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// setPostgresUserPasswordFromSource reads the password of spec from its
+// external source, if any, and stores its verifier in intent. The verifier
+// already in intent is kept when it matches the password or when the password
+// cannot be read. The external password is never written.
+func (r *Reconciler) setPostgresUserPasswordFromSource(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	spec *v1beta1.PostgresUserSpec, intent *corev1.Secret,
+) error {
+	source := spec.Password.GetSource()
+	if source == nil {
+		return nil
+	}
+
+	var password []byte
+	var problem string
+
+	switch {
+	case source.Secret != nil:
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: source.Secret.Name, Namespace: cluster.Namespace,
+		}}
+		err := errors.WithStack(client.IgnoreNotFound(
+			r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret)))
+		if err != nil {
+			return err
+		}
+
+		password = secret.Data[source.Secret.Key]
+		if len(password) == 0 {
+			problem = fmt.Sprintf("Secret %q key %q does not exist or is empty",
+				secret.Name, source.Secret.Key)
+		}
+
+	case source.File != "":
+		directory := config.PasswordDirectory()
+
+		switch {
+		case directory == "":
+			problem = "password files are not enabled; set PGO_PASSWORD_DIRECTORY"
+		case !filepath.IsLocal(source.File):
+			problem = fmt.Sprintf("file %q is not a local path", source.File)
+		default:
+			// Each namespace has its own directory of password files so that
+			// clusters cannot read the passwords of other namespaces.
+			var err error
+			if password, err = os.ReadFile(
+				filepath.Join(directory, cluster.Namespace, source.File),
+			); err != nil {
+				problem = fmt.Sprintf("unable to read file %q", source.File)
+			} else if password = bytes.TrimSuffix(
+				bytes.TrimSuffix(password, []byte("\n")), []byte("\r"),
+			); len(password) == 0 {
+				problem = fmt.Sprintf("file %q is empty", source.File)
+			}
+		}
+	}
+
+	if problem != "" {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "PasswordNotFound",
+			"Unable to read the password of user %q: %s", spec.Name, problem)
+		return nil
+	}
+
+	// NOTE: Verifiers have a random salt, so building a new one every time
+	// would change the users revision every time.
+	scram := pgpassword.NewSCRAMPassword(string(password))
+	if !scram.Verify(string(intent.Data["verifier"])) {
+		verifier, err := scram.Build()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		intent.Data["verifier"] = []byte(verifier)
+	}
+
+	return nil
+}
+
+// reclaimPostgresUserPolicy returns the reclaim policy recorded on the Secret
+// of a PostgreSQL user. The "postgres" user is always retained.
+func reclaimPostgresUserPolicy(secret *corev1.Secret) string {
+	if secret.Labels[naming.LabelPostgresUser] != "postgres" {
+		switch policy := secret.Annotations[naming.PostgresUserReclaimPolicy]; policy {
+		case v1beta1.PostgresUserReclaimDrop, v1beta1.PostgresUserReclaimRevoke:
+			return policy
+		}
+	}
+	return v1beta1.PostgresUserReclaimRetain
 }
 
 // reconcilePostgresUsersInPostgreSQL creates users inside of PostgreSQL and
-// sets their options and database access as specified.
+// sets their options and database access as specified. It then revokes or
+// drops the users of removedSecrets and deletes those Secrets.
 func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 	specUsers []v1beta1.PostgresUserSpec, userSecrets map[string]*corev1.Secret,
+	removedSecrets []*corev1.Secret,
 ) error {
 	const container = naming.ContainerDatabase
 	var podExecutor postgres.Executor
@@ -519,13 +1113,53 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
 
+	// Index verifiers by user. During a grace period, the role that does not
+	// have the current password has the previous one, if any. Only users with
+	// a grace period have an alternate role.
 	verifiers := make(map[string]string, len(userSecrets))
-	for userName := range userSecrets {
-		verifiers[userName] = string(userSecrets[userName].Data["verifier"])
+	alternates := make(map[string]string)
+	for i := range specUsers {
+		userName := string(specUsers[i].Name)
+		secret := userSecrets[userName]
+		if secret == nil {
+			continue
+		}
+
+		current := string(secret.Data["verifier"])
+		verifiers[userName] = current
+
+		if rotation := specUsers[i].Password.GetRotation(); rotation != nil && rotation.GracePeriod != nil {
+			previous := string(secret.Data["previous-verifier"])
+			if string(secret.Data["user"]) == postgres.AlternateUserName(userName) {
+				verifiers[userName], alternates[userName] = previous, current
+			} else {
+				alternates[userName] = previous
+			}
+		}
 	}
 
+	// Sort the names of removed users so their SQL is deterministic.
+	var drop, revoke []string
+	for _, secret := range removedSecrets {
+		userName := secret.Labels[naming.LabelPostgresUser]
+
+		switch reclaimPostgresUserPolicy(secret) {
+		case v1beta1.PostgresUserReclaimDrop:
+			drop = append(drop, userName)
+		case v1beta1.PostgresUserReclaimRevoke:
+			revoke = append(revoke, userName)
+		}
+	}
+	slices.Sort(drop)
+	slices.Sort(revoke)
+	drop, revoke = slices.Compact(drop), slices.Compact(revoke)
+
 	write := func(ctx context.Context, exec postgres.Executor) error {
-		return postgres.WriteUsersInPostgreSQL(ctx, cluster, exec, specUsers, verifiers)
+		err := postgres.WriteUsersInPostgreSQL(ctx, cluster, exec, specUsers, verifiers, alternates)
+		if err == nil && len(drop)+len(revoke) > 0 {
+			err = postgres.ReclaimUsersInPostgreSQL(ctx, exec, revoke, drop)
+		}
+		return err
 	}
 
 	revision, err := safeHash32(func(hasher io.Writer) error {
@@ -541,23 +1175,27 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 		})
 	})
 
-	if err == nil && revision == cluster.Status.UsersRevision {
-		// The necessary SQL has already been applied; there's nothing more to do.
+	// When the necessary SQL has already been applied, there's nothing more to
+	// do in PostgreSQL. Otherwise, apply it and record its hash in cluster.Status.
+	// Include the hash in any log messages.
 
-		// TODO(cbandy): Give the user a way to trigger execution regardless.
-		// The value of an annotation could influence the hash, for example.
-		return nil
-	}
+	// TODO(cbandy): Give the user a way to trigger execution regardless.
+	// The value of an annotation could influence the hash, for example.
 
-	// Apply the necessary SQL and record its hash in cluster.Status. Include
-	// the hash in any log messages.
-
-	if err == nil {
+	if err == nil && revision != cluster.Status.UsersRevision {
 		log := logging.FromContext(ctx).WithValues("revision", revision)
 		err = errors.WithStack(write(logging.NewContext(ctx, log), podExecutor))
+
+		if err == nil {
+			cluster.Status.UsersRevision = revision
+		}
 	}
-	if err == nil {
-		cluster.Status.UsersRevision = revision
+
+	// The Secrets of removed users are no longer necessary.
+	for _, secret := range removedSecrets {
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, secret))
+		}
 	}
 
 	return err
@@ -898,12 +1536,19 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 		// If database init sql is not requested, we will always expect the
 		// status to be nil
 		cluster.Status.DatabaseInitSQL = nil
+		cluster.Status.DatabaseInitSQLScripts = nil
 		return nil
 	}
 
-	// Spec is defined but status is already set, return
+	// Only scripts are defined, unset status and run them
+	if cluster.Spec.DatabaseInitSQL.Name == "" {
+		cluster.Status.DatabaseInitSQL = nil
+		return r.reconcileDatabaseInitSQLScripts(ctx, cluster, instances)
+	}
+
+	// Spec is defined but status is already set, run any scripts and return
 	if cluster.Status.DatabaseInitSQL != nil {
-		return nil
+		return r.reconcileDatabaseInitSQLScripts(ctx, cluster, instances)
 	}
 
 	// Based on the previous checks, the user wants to run sql in the database.
@@ -983,5 +1628,187 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 		cluster.Status.DatabaseInitSQL = &status
 	}
 
+	if err == nil {
+		err = r.reconcileDatabaseInitSQLScripts(ctx, cluster, instances)
+	}
+
+	return err
+}
+
+// reconcileDatabaseInitSQLScripts runs the scripts of DatabaseInitSQL in order
+// and records the outcome of each in status. A script that cannot run or does
+// not succeed is recorded with a message, and the scripts after it wait until
+// it succeeds in a later reconcile.
+func (r *Reconciler) reconcileDatabaseInitSQLScripts(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, instances *observedInstances) error {
+	scripts := cluster.Spec.DatabaseInitSQL.Scripts
+
+	if len(scripts) == 0 {
+		cluster.Status.DatabaseInitSQLScripts = nil
+		return nil
+	}
+
+	// Scripts run in the database container of a writable pod. When there is
+	// no such pod, keep the status of every script until there is.
+	var exec postgres.Executor
+	if pod, _ := instances.writablePod(naming.ContainerDatabase); pod != nil {
+		exec = func(
+			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+		}
+	}
+
+	previous := make(map[string]v1beta1.DatabaseInitSQLScriptStatus)
+	for _, status := range cluster.Status.DatabaseInitSQLScripts {
+		previous[status.Name] = status
+	}
+
+	var err error
+	var blocked bool
+	statuses := make([]v1beta1.DatabaseInitSQLScriptStatus, 0, len(scripts))
+	for i := range scripts {
+		status := previous[scripts[i].Name]
+		status.Name = scripts[i].Name
+
+		if err == nil && exec != nil && !blocked {
+			status, err = r.runDatabaseInitSQLScript(ctx, cluster, exec, scripts[i], status)
+			blocked = status.Message != ""
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	cluster.Status.DatabaseInitSQLScripts = statuses
 	return err
 }
+
+// runDatabaseInitSQLScript reads the SQL of script and calls exec to run it
+// when it has not run before or, for "OnChange" scripts, when its SQL or
+// database has changed since it last succeeded. It returns the next status of
+// script; an error is returned only when the script could not be read.
+func (r *Reconciler) runDatabaseInitSQLScript(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, exec postgres.Executor,
+	script v1beta1.DatabaseInitSQLScript, status v1beta1.DatabaseInitSQLScriptStatus,
+) (v1beta1.DatabaseInitSQLScriptStatus, error) {
+	log := logging.FromContext(ctx).WithValues("script", script.Name)
+
+	// A script that runs once is done after it succeeds.
+	if script.Run != v1beta1.DatabaseInitSQLRunOnChange && status.Hash != "" {
+		return status, nil
+	}
+
+	var (
+		err      error
+		found    bool
+		optional *bool
+		source   string
+		sql      string
+	)
+	switch {
+	case script.ConfigMap != nil:
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: script.ConfigMap.Name, Namespace: cluster.Namespace,
+		}}
+		err = client.IgnoreNotFound(r.Client.Get(ctx, client.ObjectKeyFromObject(cm), cm))
+		sql, found = cm.Data[script.ConfigMap.Key]
+		optional = script.ConfigMap.Optional
+		source = fmt.Sprintf("ConfigMap %q key %q", cm.Name, script.ConfigMap.Key)
+
+	case script.Secret != nil:
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: script.Secret.Name, Namespace: cluster.Namespace,
+		}}
+		err = client.IgnoreNotFound(r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+		data, ok := secret.Data[script.Secret.Key]
+		sql, found = string(data), ok
+		optional = script.Secret.Optional
+		source = fmt.Sprintf("Secret %q key %q", secret.Name, script.Secret.Key)
+
+		// Read only Secrets that were meant for this cluster. Anyone who can
+		// edit the cluster should not be able to run other Secrets as SQL.
+		if found && secret.Labels[naming.LabelDatabaseInitSQL] != cluster.Name {
+			status.Message = fmt.Sprintf("Secret %q is not labeled %s=%s",
+				secret.Name, naming.LabelDatabaseInitSQL, cluster.Name)
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DatabaseInitSQLScriptFailed",
+				"Unable to run script %q: %s", script.Name, status.Message)
+			return status, nil
+		}
+	}
+	if err != nil {
+		return status, errors.WithStack(err)
+	}
+
+	// Skip an optional script that does not exist. Scripts after it can run.
+	if !found && initialize.FromPointer(optional) {
+		return status, nil
+	}
+	if !found {
+		status.Message = source + " does not exist"
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DatabaseInitSQLScriptFailed",
+			"Unable to run script %q: %s", script.Name, status.Message)
+		return status, nil
+	}
+
+	database := string(script.Database)
+	if database == "" {
+		database = "postgres"
+	}
+
+	hash, _ := safeHash32(func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%s\n%s", database, sql)
+		return err
+	})
+
+	// The same SQL already succeeded in this database.
+	if status.Hash == hash {
+		status.Message = ""
+		return status, nil
+	}
+
+	stdout, stderr, err := exec.ExecInDatabase(ctx, database, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on",       // Abort when any one statement fails.
+			"VERBOSITY":     "sqlstate", // Report errors without quoting the SQL.
+		})
+	log.V(1).Info("applied init SQL script", "stdout", stdout, "stderr", stderr)
+
+	if err == nil {
+		status.Hash, status.Message = hash, ""
+	} else {
+		status.Message = databaseInitSQLScriptMessage(stderr)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DatabaseInitSQLScriptFailed",
+			"Script %q failed in database %q: %s", script.Name, database, status.Message)
+	}
+
+	return status, nil
+}
+
+// databaseInitSQLScriptMessage returns the line and SQLSTATE of the first
+// error that psql printed to stderr. It never includes the text of that error
+// because errors can quote the SQL of the script.
+// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-VARIABLES-VERBOSITY
+func databaseInitSQLScriptMessage(stderr string) string {
+	for _, line := range strings.Split(stderr, "\n") {
+		// psql prefixes each error with the name and line of its input.
+		rest, ok := strings.CutPrefix(line, "psql:<stdin>:")
+		if !ok {
+			continue
+		}
+		number, rest, _ := strings.Cut(rest, ":")
+		if _, err := strconv.Atoi(number); err != nil {
+			continue
+		}
+
+		message := "script failed at line " + number
+		if _, code, ok := strings.Cut(rest, "ERROR:"); ok && regexSQLState.MatchString(strings.TrimSpace(code)) {
+			message += " with SQLSTATE " + strings.TrimSpace(code)
+		}
+		return message
+	}
+	return "script failed"
+}
+
+// regexSQLState matches a five-character PostgreSQL error code.
+// - https://www.postgresql.org/docs/current/errcodes-appendix.html
+var regexSQLState = regexp.MustCompile(`^[0-9A-Z]{5}$`)
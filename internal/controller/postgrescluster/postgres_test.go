@@ -9,7 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr/funcr"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -20,7 +25,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
@@ -28,7 +36,9 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
+	pgpassword "github.com/crunchydata/postgres-operator/internal/postgres/password"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
 	"github.com/crunchydata/postgres-operator/internal/testing/events"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
@@ -231,6 +241,428 @@ func TestGeneratePostgresUserSecret(t *testing.T) {
 	})
 }
 
+func TestGeneratePostgresUserSecretRotation(t *testing.T) {
+	reconciler := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).Build(),
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace = "ns1"
+	cluster.Name = "hippo2"
+	cluster.Spec.Port = initialize.Int32(9999)
+
+	spec := &v1beta1.PostgresUserSpec{
+		Name:      "some-user-name",
+		Databases: []v1beta1.PostgresIdentifier{"db1"},
+		Password: &v1beta1.PostgresPasswordSpec{
+			Rotation: &v1beta1.PostgresPasswordRotationSpec{
+				Interval: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}
+
+	recent := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	expired := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+
+	existing := func(rotatedAt time.Time) *corev1.Secret {
+		secret := &corev1.Secret{Data: map[string][]byte{
+			"password": []byte(`asdf`),
+			"user":     []byte(`some-user-name`),
+			"verifier": []byte(`some$thing`),
+		}}
+		secret.CreationTimestamp = metav1.NewTime(expired.Add(-time.Hour))
+		secret.Annotations = map[string]string{
+			naming.PasswordRotatedAt: rotatedAt.Format(time.RFC3339),
+		}
+		return secret
+	}
+
+	t.Run("NotDue", func(t *testing.T) {
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing(recent))
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(secret.Data["password"]), "asdf")
+		assert.Equal(t, string(secret.Data["verifier"]), "some$thing")
+		assert.Equal(t, secret.Annotations[naming.PasswordRotatedAt], recent.Format(time.RFC3339))
+	})
+
+	t.Run("CreationTimestamp", func(t *testing.T) {
+		secret := existing(recent)
+		secret.Annotations = nil
+
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, secret)
+		assert.NilError(t, err)
+		assert.Assert(t, string(secret.Data["password"]) != "asdf")
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing(expired))
+		assert.NilError(t, err)
+
+		assert.Assert(t, string(secret.Data["password"]) != "asdf")
+		assert.Assert(t, string(secret.Data["verifier"]) != "some$thing")
+		assert.Equal(t, string(secret.Data["user"]), "some-user-name")
+		assert.Assert(t, cmp.Contains(string(secret.Data["uri"]),
+			url.UserPassword("some-user-name", string(secret.Data["password"])).String()))
+		assert.Assert(t, secret.Data["previous-verifier"] == nil)
+
+		rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[naming.PasswordRotatedAt])
+		assert.NilError(t, err)
+		assert.Assert(t, rotatedAt.After(recent))
+	})
+
+	t.Run("RequestedAt", func(t *testing.T) {
+		spec := spec.DeepCopy()
+		spec.Password.Rotation.Interval = nil
+
+		// Requested before the current password.
+		spec.Password.Rotation.RequestedAt = &metav1.Time{Time: recent.Add(-time.Minute)}
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing(recent))
+		assert.NilError(t, err)
+		assert.Equal(t, string(secret.Data["password"]), "asdf")
+
+		// Requested after the current password.
+		spec.Password.Rotation.RequestedAt = &metav1.Time{Time: recent.Add(time.Minute)}
+		secret, err = reconciler.generatePostgresUserSecret(cluster, spec, existing(recent))
+		assert.NilError(t, err)
+		assert.Assert(t, string(secret.Data["password"]) != "asdf")
+
+		// Requested in the future.
+		spec.Password.Rotation.RequestedAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		secret, err = reconciler.generatePostgresUserSecret(cluster, spec, existing(recent))
+		assert.NilError(t, err)
+		assert.Equal(t, string(secret.Data["password"]), "asdf")
+	})
+
+	t.Run("GracePeriod", func(t *testing.T) {
+		spec := spec.DeepCopy()
+		spec.Password.Rotation.GracePeriod = &metav1.Duration{Duration: time.Hour}
+
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing(expired))
+		assert.NilError(t, err)
+
+		// The new password goes to the alternate role.
+		assert.Assert(t, string(secret.Data["password"]) != "asdf")
+		assert.Equal(t, string(secret.Data["user"]), "some-user-name_alt")
+		assert.Assert(t, cmp.Contains(string(secret.Data["uri"]), "some-user-name_alt:"))
+		assert.Equal(t, string(secret.Data["previous-verifier"]), "some$thing")
+
+		expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[naming.PasswordPreviousExpiresAt])
+		assert.NilError(t, err)
+		assert.Assert(t, expiresAt.After(time.Now().Add(50*time.Minute)))
+
+		// The previous verifier is kept until it expires.
+		secret.CreationTimestamp = metav1.NewTime(expired)
+		again, err := reconciler.generatePostgresUserSecret(cluster, spec, secret.DeepCopy())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, again.Data, secret.Data)
+
+		secret.Annotations[naming.PasswordPreviousExpiresAt] = recent.Format(time.RFC3339)
+		again, err = reconciler.generatePostgresUserSecret(cluster, spec, secret.DeepCopy())
+		assert.NilError(t, err)
+		assert.Equal(t, string(again.Data["user"]), "some-user-name_alt")
+		assert.Assert(t, again.Data["previous-verifier"] == nil)
+		assert.Assert(t, again.Annotations[naming.PasswordPreviousExpiresAt] == "")
+
+		// The next password goes back to the user.
+		again.Annotations[naming.PasswordRotatedAt] = expired.Format(time.RFC3339)
+		next, err := reconciler.generatePostgresUserSecret(cluster, spec, again.DeepCopy())
+		assert.NilError(t, err)
+		assert.Equal(t, string(next.Data["user"]), "some-user-name")
+		assert.DeepEqual(t, next.Data["previous-verifier"], again.Data["verifier"])
+	})
+}
+
+func TestGeneratePostgresUserSecretSource(t *testing.T) {
+	ctx := context.Background()
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Client: cc, Recorder: recorder}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace = "ns1"
+	cluster.Name = "hippo2"
+	cluster.Spec.Port = initialize.Int32(9999)
+	cluster.Spec.Proxy = &v1beta1.PostgresProxySpec{
+		PGBouncer: &v1beta1.PGBouncerPodSpec{Port: initialize.Int32(10220)},
+	}
+
+	spec := &v1beta1.PostgresUserSpec{
+		Name:      "some-user-name",
+		Databases: []v1beta1.PostgresIdentifier{"first"},
+	}
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		source: { secret: { name: external, key: pw } },
+	}`), &spec.Password))
+
+	// generate returns the next Secret of spec when existing is the previous.
+	generate := func(t testing.TB, existing *corev1.Secret) *corev1.Secret {
+		t.Helper()
+		secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing)
+		assert.NilError(t, err)
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, secret))
+		return secret
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		recorder.Events = nil
+		secret := generate(t, nil)
+
+		assert.Equal(t, string(secret.Data["dbname"]), "first")
+		assert.Equal(t, string(secret.Data["user"]), "some-user-name")
+		assert.Equal(t, string(secret.Data["pgbouncer-host"]), "hippo2-pgbouncer.ns1.svc")
+		for _, key := range []string{
+			"password", "verifier", "uri", "jdbc-uri", "pgbouncer-uri", "pgbouncer-jdbc-uri",
+		} {
+			assert.Assert(t, secret.Data[key] == nil, "expected no %q", key)
+		}
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "PasswordNotFound")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, `Secret "external" key "pw"`))
+	})
+
+	external := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "external"},
+		Data:       map[string][]byte{"pw": []byte("from elsewhere")},
+	}
+	assert.NilError(t, cc.Create(ctx, external))
+
+	recorder.Events = nil
+	secret := generate(t, nil)
+	assert.Equal(t, len(recorder.Events), 0)
+	assert.Assert(t, secret.Data["password"] == nil)
+	assert.Assert(t, pgpassword.NewSCRAMPassword("from elsewhere").Verify(string(secret.Data["verifier"])))
+
+	t.Run("Unchanged", func(t *testing.T) {
+		again := generate(t, secret.DeepCopy())
+		assert.DeepEqual(t, again.Data, secret.Data)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		external := external.DeepCopy()
+		external.Data["pw"] = []byte("something new")
+		assert.NilError(t, cc.Update(ctx, external))
+
+		again := generate(t, secret.DeepCopy())
+		assert.Assert(t, pgpassword.NewSCRAMPassword("something new").Verify(string(again.Data["verifier"])))
+
+		// The external Secret is only read.
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(external), external))
+		assert.Equal(t, string(external.Data["pw"]), "something new")
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		// A generated password is discarded when the source is set.
+		generated := secret.DeepCopy()
+		generated.Data["password"] = []byte("generated")
+
+		again := generate(t, generated)
+		assert.Assert(t, again.Data["password"] == nil)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		recorder.Events = nil
+		dir := t.TempDir()
+		assert.NilError(t, os.Mkdir(filepath.Join(dir, "ns1"), 0o700))
+		assert.NilError(t, os.Mkdir(filepath.Join(dir, "ns2"), 0o700))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "ns1", "pw"), []byte("in a file\n"), 0o600))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "ns1", "crlf"), []byte("windows\r\n"), 0o600))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "ns1", "blank"), []byte("\n"), 0o600))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "ns2", "other"), []byte("elsewhere"), 0o600))
+
+		spec := spec.DeepCopy()
+		spec.Password.Source = &v1beta1.PostgresPasswordSource{File: "pw"}
+
+		// Files are not read unless enabled.
+		t.Setenv("PGO_PASSWORD_DIRECTORY", "")
+		unchanged := secret.DeepCopy()
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, unchanged))
+		assert.DeepEqual(t, unchanged.Data, secret.Data)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "PGO_PASSWORD_DIRECTORY"))
+
+		t.Setenv("PGO_PASSWORD_DIRECTORY", dir)
+		changed := secret.DeepCopy()
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, changed))
+		assert.Assert(t, pgpassword.NewSCRAMPassword("in a file").Verify(string(changed.Data["verifier"])))
+
+		spec.Password.Source.File = "../ns2/other"
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, changed))
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "not a local path"))
+
+		// Files of other namespaces cannot be read.
+		spec.Password.Source.File = "other"
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, changed))
+		assert.Equal(t, len(recorder.Events), 3)
+		assert.Assert(t, cmp.Contains(recorder.Events[2].Note, `unable to read file "other"`))
+		assert.Assert(t, pgpassword.NewSCRAMPassword("in a file").Verify(string(changed.Data["verifier"])))
+
+		// One trailing newline is not part of the password.
+		spec.Password.Source.File = "crlf"
+		windows := secret.DeepCopy()
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, windows))
+		assert.Assert(t, pgpassword.NewSCRAMPassword("windows").Verify(string(windows.Data["verifier"])))
+
+		spec.Password.Source.File = "blank"
+		assert.NilError(t, reconciler.setPostgresUserPasswordFromSource(ctx, cluster, spec, windows))
+		assert.Equal(t, len(recorder.Events), 4)
+		assert.Assert(t, cmp.Contains(recorder.Events[3].Note, `file "blank" is empty`))
+	})
+}
+
+func TestNextPasswordFileRead(t *testing.T) {
+	users := []v1beta1.PostgresUserSpec{
+		{Name: "none"},
+		{Name: "secret", Password: &v1beta1.PostgresPasswordSpec{
+			Source: &v1beta1.PostgresPasswordSource{Secret: &corev1.SecretKeySelector{}},
+		}},
+	}
+	assert.Equal(t, nextPasswordFileRead(users), time.Duration(0))
+
+	users = append(users, v1beta1.PostgresUserSpec{Name: "file",
+		Password: &v1beta1.PostgresPasswordSpec{
+			Source: &v1beta1.PostgresPasswordSource{File: "pw"},
+		},
+	})
+	assert.Equal(t, nextPasswordFileRead(users), time.Minute)
+}
+
+func TestNextPasswordRotation(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	users := []v1beta1.PostgresUserSpec{
+		{Name: "none"},
+		{Name: "interval", Password: &v1beta1.PostgresPasswordSpec{
+			Rotation: &v1beta1.PostgresPasswordRotationSpec{
+				Interval: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		}},
+		{Name: "grace", Password: &v1beta1.PostgresPasswordSpec{
+			Rotation: &v1beta1.PostgresPasswordRotationSpec{
+				GracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+		}},
+	}
+
+	secret := func(annotations map[string]string) *corev1.Secret {
+		s := &corev1.Secret{}
+		s.Annotations = annotations
+		return s
+	}
+
+	assert.Equal(t, nextPasswordRotation(now, users, nil), time.Duration(0))
+
+	secrets := map[string]*corev1.Secret{
+		"none": secret(nil),
+		"interval": secret(map[string]string{
+			naming.PasswordRotatedAt: "2024-05-01T00:00:00Z",
+		}),
+		"grace": secret(map[string]string{
+			naming.PasswordRotatedAt: "2024-05-01T11:50:00Z",
+		}),
+	}
+	assert.Equal(t, nextPasswordRotation(now, users, secrets), 12*time.Hour)
+
+	secrets["grace"].Annotations[naming.PasswordPreviousExpiresAt] = "2024-05-01T12:50:00Z"
+	assert.Equal(t, nextPasswordRotation(now, users, secrets), 50*time.Minute)
+
+	// Requests in the future are scheduled.
+	users[2].Password.Rotation.RequestedAt = &metav1.Time{Time: now.Add(10 * time.Minute)}
+	assert.Equal(t, nextPasswordRotation(now, users, secrets), 10*time.Minute)
+
+	// Requests that have been fulfilled are not.
+	users[2].Password.Rotation.RequestedAt = &metav1.Time{Time: now.Add(-15 * time.Minute)}
+	assert.Equal(t, nextPasswordRotation(now, users, secrets), 50*time.Minute)
+
+	// Passed times are soon.
+	secrets["interval"].Annotations[naming.PasswordRotatedAt] = "2024-04-01T00:00:00Z"
+	assert.Equal(t, nextPasswordRotation(now, users, secrets), time.Second)
+}
+
+func TestReconcilePostgresUsersInPostgreSQLReclaim(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := testCluster()
+	cluster.Namespace = "ns1"
+	cluster.UID = "some-uid"
+
+	secret := func(user, policy string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: naming.PostgresUserSecret(cluster, user)}
+		s.Labels = map[string]string{naming.LabelPostgresUser: user}
+		s.Annotations = map[string]string{naming.PostgresUserReclaimPolicy: policy}
+		assert.NilError(t, controllerutil.SetControllerReference(cluster, s, runtime.Scheme))
+		return s
+	}
+	removed := []*corev1.Secret{
+		secret("dropped", v1beta1.PostgresUserReclaimDrop),
+		secret("revoked", v1beta1.PostgresUserReclaimRevoke),
+	}
+
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+	for _, s := range removed {
+		assert.NilError(t, cc.Create(ctx, s))
+	}
+
+	var scripts []string
+	r := &Reconciler{
+		Client: cc,
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			scripts = append(scripts, strings.Join(command, " ")+"\n"+string(b))
+			return nil
+		},
+	}
+
+	// Nothing happens without a writable instance.
+	assert.NilError(t, r.reconcilePostgresUsersInPostgreSQL(ctx, cluster,
+		&observedInstances{}, nil, nil, removed))
+	assert.Equal(t, len(scripts), 0)
+
+	list := &corev1.SecretList{}
+	assert.NilError(t, cc.List(ctx, list))
+	assert.Equal(t, len(list.Items), 2, "expected Secrets to remain")
+
+	instances := &observedInstances{forCluster: []*Instance{{
+		Name: "instance",
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   cluster.Namespace,
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"primary"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: naming.ContainerDatabase,
+					State: corev1.ContainerState{
+						Running: new(corev1.ContainerStateRunning),
+					},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	assert.NilError(t, r.reconcilePostgresUsersInPostgreSQL(ctx, cluster,
+		instances, nil, nil, removed))
+
+	// Users are written, then revoked, then dropped.
+	assert.Equal(t, len(scripts), 4)
+	assert.Assert(t, cmp.Contains(scripts[1], `roles={"revoked":"revoked_alt"}`))
+	assert.Assert(t, cmp.Contains(scripts[1], `NOLOGIN`))
+	assert.Assert(t, cmp.Contains(scripts[2], `roles={"dropped":"dropped_alt"}`))
+	assert.Assert(t, cmp.Contains(scripts[2], `REASSIGN OWNED`))
+	assert.Assert(t, cmp.Contains(scripts[3], `DROP ROLE`))
+
+	assert.NilError(t, cc.List(ctx, list))
+	assert.Equal(t, len(list.Items), 0, "expected Secrets to be deleted")
+}
+
 func TestReconcilePostgresVolumes(t *testing.T) {
 	ctx := context.Background()
 	_, tClient := setupKubernetes(t)
@@ -1151,6 +1583,587 @@ func TestReconcileDatabaseInitSQLConfigMap(t *testing.T) {
 	})
 }
 
+func TestReconcileDatabaseInitSQLScripts(t *testing.T) {
+	ctx := context.Background()
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+
+	assert.NilError(t, cc.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sql"},
+		Data: map[string]string{
+			"one.sql": "CREATE TABLE one ();",
+			"two.sql": "INSERT INTO two VALUES (2);",
+		},
+	}))
+	assert.NilError(t, cc.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "sql", Labels: map[string]string{
+			naming.LabelDatabaseInitSQL: "hippo",
+		}},
+		Data: map[string][]byte{"three.sql": []byte("SELECT 3;")},
+	}))
+	assert.NilError(t, cc.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "unrelated"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}))
+
+	type call struct {
+		Database, SQL string
+	}
+	var calls []call
+
+	// Each call to PodExec returns the next of these results.
+	var results []error
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Client:   cc,
+		Recorder: recorder,
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Equal(t, pod, "pod")
+			assert.Equal(t, container, naming.ContainerDatabase)
+			assert.Assert(t, cmp.Contains(command, "--set=ON_ERROR_STOP=on"))
+			assert.Assert(t, cmp.Contains(command, "--set=VERBOSITY=sqlstate"))
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			calls = append(calls, call{Database: command[5], SQL: string(b)})
+
+			if len(results) > 0 {
+				err, results = results[0], results[1:]
+				if err != nil {
+					_, _ = io.WriteString(stderr, "psql:<stdin>:1: ERROR:  42P01\n")
+				}
+			}
+			return err
+		},
+	}
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns1",
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"primary"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: naming.ContainerDatabase,
+					State: corev1.ContainerState{
+						Running: new(corev1.ContainerStateRunning),
+					},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		scripts: [
+			{ name: one, configMap: { name: sql, key: one.sql }, database: app },
+			{ name: two, configMap: { name: sql, key: two.sql }, run: OnChange },
+			{ name: three, secret: { name: sql, key: three.sql } },
+		],
+	}`), &cluster.Spec.DatabaseInitSQL))
+
+	t.Run("NoWritablePod", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.DatabaseInitSQLScripts = []v1beta1.DatabaseInitSQLScriptStatus{
+			{Name: "one", Hash: "abc"},
+		}
+
+		assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, nil))
+		assert.Equal(t, len(calls), 0)
+		assert.DeepEqual(t, cluster.Status.DatabaseInitSQLScripts, []v1beta1.DatabaseInitSQLScriptStatus{
+			{Name: "one", Hash: "abc"}, {Name: "two"}, {Name: "three"},
+		})
+	})
+
+	// The second script fails, so the third waits.
+	calls, results = nil, []error{nil, errors.New("exit code 3")}
+	assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+	assert.DeepEqual(t, calls, []call{
+		{Database: "app", SQL: "CREATE TABLE one ();"},
+		{Database: "postgres", SQL: "INSERT INTO two VALUES (2);"},
+	})
+
+	statuses := cluster.Status.DatabaseInitSQLScripts
+	assert.Equal(t, len(statuses), 3)
+	assert.Equal(t, statuses[0].Name, "one")
+	assert.Assert(t, statuses[0].Hash != "")
+	assert.Equal(t, statuses[0].Message, "")
+	assert.Equal(t, statuses[1].Name, "two")
+	assert.Equal(t, statuses[1].Hash, "")
+	assert.Equal(t, statuses[1].Message, "script failed at line 1 with SQLSTATE 42P01")
+	assert.DeepEqual(t, statuses[2], v1beta1.DatabaseInitSQLScriptStatus{Name: "three"})
+
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Reason, "DatabaseInitSQLScriptFailed")
+
+	// The first script runs once; the others run now.
+	calls, results = nil, nil
+	assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+	assert.DeepEqual(t, calls, []call{
+		{Database: "postgres", SQL: "INSERT INTO two VALUES (2);"},
+		{Database: "postgres", SQL: "SELECT 3;"},
+	})
+	for _, status := range cluster.Status.DatabaseInitSQLScripts {
+		assert.Assert(t, status.Hash != "", "%q", status.Name)
+		assert.Equal(t, status.Message, "", "%q", status.Name)
+	}
+
+	// Nothing runs until something changes.
+	calls = nil
+	assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+	assert.Equal(t, len(calls), 0)
+
+	t.Run("OnChange", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.DatabaseInitSQL.Scripts[0].Database = "other"
+		cluster.Spec.DatabaseInitSQL.Scripts[1].Database = "other"
+
+		calls = nil
+		assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+		assert.DeepEqual(t, calls, []call{
+			{Database: "other", SQL: "INSERT INTO two VALUES (2);"},
+		})
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		recorder.Events = nil
+		cluster := cluster.DeepCopy()
+		cluster.Status.DatabaseInitSQLScripts = nil
+		cluster.Spec.DatabaseInitSQL.Scripts = []v1beta1.DatabaseInitSQLScript{
+			{Name: "optional", Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "sql"},
+				Key:                  "nope", Optional: initialize.Bool(true),
+			}},
+			{Name: "required", ConfigMap: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "nope"},
+				Key:                  "one.sql",
+			}},
+			{Name: "after", Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "sql"},
+				Key:                  "three.sql",
+			}},
+		}
+
+		calls = nil
+		assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+		assert.Equal(t, len(calls), 0)
+		assert.DeepEqual(t, cluster.Status.DatabaseInitSQLScripts, []v1beta1.DatabaseInitSQLScriptStatus{
+			{Name: "optional"},
+			{Name: "required", Message: `ConfigMap "nope" key "one.sql" does not exist`},
+			{Name: "after"},
+		})
+		assert.Equal(t, len(recorder.Events), 1)
+	})
+
+	t.Run("Unlabeled", func(t *testing.T) {
+		recorder.Events = nil
+		cluster := cluster.DeepCopy()
+		cluster.Status.DatabaseInitSQLScripts = nil
+		cluster.Spec.DatabaseInitSQL.Scripts = []v1beta1.DatabaseInitSQLScript{
+			{Name: "unrelated", Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "unrelated"},
+				Key:                  "password",
+			}},
+		}
+
+		calls = nil
+		assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+		assert.Equal(t, len(calls), 0)
+		assert.DeepEqual(t, cluster.Status.DatabaseInitSQLScripts, []v1beta1.DatabaseInitSQLScriptStatus{
+			{Name: "unrelated", Message: `Secret "unrelated" is not labeled ` +
+				`postgres-operator.crunchydata.com/database-init-sql=hippo`},
+		})
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, !strings.Contains(recorder.Events[0].Note, "secret"))
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.DatabaseInitSQL = nil
+
+		assert.NilError(t, reconciler.reconcileDatabaseInitSQL(ctx, cluster, observed))
+		assert.Assert(t, cluster.Status.DatabaseInitSQLScripts == nil)
+	})
+}
+
+func TestDatabaseInitSQLScriptMessage(t *testing.T) {
+	for _, tt := range []struct{ stderr, expected string }{
+		{stderr: "", expected: "script failed"},
+		{stderr: "command terminated with exit code 3\n", expected: "script failed"},
+		{
+			stderr:   "psql:<stdin>:12: ERROR:  42601\n",
+			expected: "script failed at line 12 with SQLSTATE 42601",
+		},
+		{
+			stderr:   "NOTICE:  something\npsql:<stdin>:3: ERROR:  syntax error at or near \"secret\"\n",
+			expected: "script failed at line 3",
+		},
+		{
+			stderr:   "psql:<stdin>:7: error: unterminated quoted string\n",
+			expected: "script failed at line 7",
+		},
+	} {
+		assert.Equal(t, databaseInitSQLScriptMessage(tt.stderr), tt.expected, "%q", tt.stderr)
+	}
+}
+
+func TestSetPostgresDatabasesStatus(t *testing.T) {
+	t.Parallel()
+
+	cluster := v1beta1.NewPostgresCluster()
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Recorder: recorder}
+
+	assert.Assert(t, reconciler.setPostgresDatabasesStatus(cluster, nil, nil))
+	assert.Assert(t, cluster.Status.Databases == nil)
+	assert.Equal(t, len(recorder.Events), 0)
+
+	t.Run("Extensions", func(t *testing.T) {
+		recorder.Events = nil
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Databases = []v1beta1.PostgresDatabaseSpec{
+			{Name: "empty"},
+			{Name: "app", Extensions: []v1beta1.PostgresExtensionSpec{
+				{Name: "pg_stat_statements"},
+				{Name: "pgcrypto", Version: "1.3"},
+				{Name: "postgis"},
+			}},
+		}
+		observed := map[string]v1beta1.PostgresDatabaseStatus{
+			"empty": {Name: "empty", Owner: "postgres"},
+			"app":   {Name: "app", Owner: "postgres"},
+		}
+
+		assert.Assert(t, !reconciler.setPostgresDatabasesStatus(cluster, observed, map[string]map[string]string{
+			"app": {"pg_stat_statements": "1.10", "pgcrypto": "1.2"},
+		}))
+		assert.DeepEqual(t, cluster.Status.Databases, []v1beta1.PostgresDatabaseStatus{
+			{Name: "empty", Owner: "postgres"},
+			{Name: "app", Owner: "postgres", Extensions: []v1beta1.PostgresExtensionStatus{
+				{Name: "pg_stat_statements", InstalledVersion: "1.10"},
+				{Name: "pgcrypto", RequestedVersion: "1.3", InstalledVersion: "1.2"},
+				{Name: "postgis"},
+			}},
+		})
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "ExtensionsNotReady")
+		assert.Equal(t, recorder.Events[0].Note,
+			`Unable to install or update extensions: pgcrypto in "app", postgis in "app"`)
+
+		assert.Assert(t, reconciler.setPostgresDatabasesStatus(cluster, observed, map[string]map[string]string{
+			"app": {"pg_stat_statements": "1.10", "pgcrypto": "1.3", "postgis": "3.4.2"},
+		}))
+		assert.Equal(t, len(recorder.Events), 1)
+	})
+
+	t.Run("Databases", func(t *testing.T) {
+		recorder.Events = nil
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Databases = []v1beta1.PostgresDatabaseSpec{
+			{Name: "missing"},
+			{Name: "owned", Owner: "app", ReclaimPolicy: "Drop"},
+		}
+		cluster.Status.Databases = []v1beta1.PostgresDatabaseStatus{
+			{Name: "owned", ReclaimPolicy: "Drop"},
+			{Name: "removed", ReclaimPolicy: "Drop"},
+			{Name: "retained", ReclaimPolicy: "Retain"},
+			{Name: "gone", ReclaimPolicy: "Drop"},
+		}
+		observed := map[string]v1beta1.PostgresDatabaseStatus{
+			"owned":   {Name: "owned", Owner: "postgres", Encoding: "UTF8"},
+			"removed": {Name: "removed", Owner: "postgres"},
+		}
+
+		assert.Assert(t, !reconciler.setPostgresDatabasesStatus(cluster, observed, nil))
+		assert.DeepEqual(t, cluster.Status.Databases, []v1beta1.PostgresDatabaseStatus{
+			{Name: "missing"},
+			{Name: "owned", Owner: "postgres", Encoding: "UTF8", ReclaimPolicy: "Drop"},
+			{Name: "removed", Owner: "postgres", ReclaimPolicy: "Drop"},
+		})
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "DatabasesNotReady")
+		assert.Equal(t, recorder.Events[0].Note, `Databases are not as specified: `+
+			`"missing" does not exist, "owned" is not owned by "app", "removed" was not dropped`)
+
+		observed["missing"] = v1beta1.PostgresDatabaseStatus{Name: "missing", Owner: "postgres"}
+		observed["owned"] = v1beta1.PostgresDatabaseStatus{Name: "owned", Owner: "app"}
+		delete(observed, "removed")
+
+		assert.Assert(t, reconciler.setPostgresDatabasesStatus(cluster, observed, nil))
+		assert.Equal(t, len(cluster.Status.Databases), 2)
+	})
+}
+
+func TestSetPostgresHBAs(t *testing.T) {
+	t.Parallel()
+
+	cluster := v1beta1.NewPostgresCluster()
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Recorder: recorder}
+
+	hbas := postgres.NewHBAs()
+	reconciler.setPostgresHBAs(cluster, &hbas)
+	assert.Assert(t, hbas.Specified == nil)
+	assert.Equal(t, len(recorder.Events), 0)
+
+	cluster.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+		Rules: []v1beta1.PostgresHBARule{
+			{Connection: "hostssl", Addresses: []string{"10.0.0.0/8"}, Method: "md5"},
+			{Connection: "host", Addresses: []string{"nowhere"}, Method: "reject"},
+		},
+	}
+	reconciler.setPostgresHBAs(cluster, &hbas)
+	assert.Equal(t, len(hbas.Specified), 1)
+	assert.Equal(t, hbas.Specified[0].String(), `hostssl all all "10.0.0.0/8" md5`)
+
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Reason, "InvalidAuthenticationRules")
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, `rule 1: address "nowhere"`))
+	assert.DeepEqual(t, cluster.Status.Authentication.Rejected, []string{
+		`rule 1: address "nowhere" is not in CIDR notation`,
+	})
+
+	// The same rejections are reported once.
+	reconciler.setPostgresHBAs(cluster, &hbas)
+	assert.Equal(t, len(recorder.Events), 1)
+
+	// The status goes away with the rejections.
+	cluster.Spec.Authentication = nil
+	reconciler.setPostgresHBAs(cluster, &hbas)
+	assert.Assert(t, cluster.Status.Authentication == nil)
+	assert.Equal(t, len(recorder.Events), 1)
+}
+
+func TestSetPostgresUserHBAs(t *testing.T) {
+	ctx := context.Background()
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Client: cc, Recorder: recorder}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace = "ns1"
+
+	hbas := postgres.NewHBAs()
+	assert.NilError(t, reconciler.setPostgresUserHBAs(ctx, cluster, &hbas))
+	assert.Equal(t, len(hbas.Users), 0)
+	assert.Equal(t, len(hbas.Identities), 0)
+	assert.Equal(t, len(recorder.Events), 0)
+
+	assert.NilError(t, cc.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ldap"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}))
+	assert.NilError(t, yaml.Unmarshal([]byte(`[
+		{ name: alice, authentication: { certificate: { commonNames: [alice.example.com] } } },
+		{ name: bob, authentication: { gss: {
+			principals: [bob@EXAMPLE.COM], keytab: { name: kerberos, key: keytab },
+		} } },
+		{ name: carol, authentication: { gss: {
+			principals: [carol@EXAMPLE.COM], keytab: { name: other, key: keytab },
+		} } },
+		{ name: dave, authentication: { ldap: {
+			server: ldap.example.com, baseDN: "dc=example",
+			bindDN: "cn=pg", bindPassword: { name: ldap, key: password },
+		} } },
+		{ name: eve, authentication: { ldap: {
+			server: ldap.example.com, baseDN: "dc=example",
+			bindDN: "cn=pg", bindPassword: { name: missing, key: password },
+		} } },
+	]`), &cluster.Spec.Users))
+
+	cluster.Spec.PostgresVersion = 16
+	hbas = postgres.NewHBAs()
+	assert.NilError(t, reconciler.setPostgresUserHBAs(ctx, cluster, &hbas))
+
+	printed := make([]string, len(hbas.Users))
+	for i := range hbas.Users {
+		printed[i] = hbas.Users[i].String()
+	}
+	assert.Assert(t, !strings.Contains(strings.Join(printed, "\n"), "hunter2"),
+		"expected bind passwords to be kept out of Patroni configuration")
+	assert.Assert(t, !strings.Contains(strings.Join(printed, "\n"), "eve"),
+		"expected no records for a user without its bind password")
+	assert.Equal(t, len(hbas.Identities), 4)
+
+	assert.Equal(t, len(hbas.Confidential), 1)
+	assert.Equal(t, hbas.Confidential[0].String(),
+		`hostssl all "dave" all ldap  ldapbasedn="dc=example" ldapbinddn="cn=pg" ldapbindpasswd="hunter2" ldapserver="ldap.example.com"`)
+
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Reason, "InvalidUserAuthentication")
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, `user "carol": keytab differs`))
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, `user "eve": bind password Secret "missing"`))
+
+	t.Run("BeforePostgreSQL16", func(t *testing.T) {
+		recorder.Events = nil
+		cluster := cluster.DeepCopy()
+		cluster.Spec.PostgresVersion = 15
+
+		hbas := postgres.NewHBAs()
+		assert.NilError(t, reconciler.setPostgresUserHBAs(ctx, cluster, &hbas))
+		assert.Equal(t, len(hbas.Confidential), 0)
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note,
+			`user "dave": bind passwords require PostgreSQL 16 or later`))
+	})
+}
+
+func TestSetPostgresUserCertificate(t *testing.T) {
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+
+	spec := &v1beta1.PostgresUserSpec{Name: "alice"}
+
+	t.Run("NoCertificate", func(t *testing.T) {
+		intent := &corev1.Secret{Data: map[string][]byte{}}
+		assert.NilError(t, setPostgresUserCertificate(root, spec, nil, intent))
+		assert.Equal(t, len(intent.Data), 0)
+	})
+
+	spec.Authentication = &v1beta1.PostgresUserAuthenticationSpec{
+		Certificate: &v1beta1.PostgresCertificateAuthentication{},
+	}
+
+	first := &corev1.Secret{Data: map[string][]byte{}}
+	assert.NilError(t, setPostgresUserCertificate(root, spec, nil, first))
+	assert.Assert(t, len(first.Data["tls.crt"]) > 0)
+	assert.Assert(t, len(first.Data["tls.key"]) > 0)
+
+	authority, err := root.Certificate.MarshalText()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, first.Data["ca.crt"], authority)
+
+	var leaf pki.Certificate
+	assert.NilError(t, leaf.UnmarshalText(first.Data["tls.crt"]))
+	assert.Equal(t, leaf.CommonName(), "alice")
+
+	t.Run("Kept", func(t *testing.T) {
+		second := &corev1.Secret{Data: map[string][]byte{}}
+		assert.NilError(t, setPostgresUserCertificate(root, spec, first, second))
+		assert.DeepEqual(t, second.Data, first.Data)
+	})
+
+	t.Run("OtherAuthority", func(t *testing.T) {
+		other, err := pki.NewRootCertificateAuthority()
+		assert.NilError(t, err)
+
+		second := &corev1.Secret{Data: map[string][]byte{}}
+		assert.NilError(t, setPostgresUserCertificate(other, spec, first, second))
+		assert.Assert(t, string(second.Data["tls.crt"]) != string(first.Data["tls.crt"]))
+	})
+}
+
+func TestSetPostgresParameters(t *testing.T) {
+	t.Parallel()
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.PostgresVersion = 17
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Recorder: recorder}
+
+	// Nothing to apply or report.
+	parameters := postgres.NewParameters()
+	reconciler.setPostgresParameters(cluster, &parameters)
+	assert.DeepEqual(t, parameters.Specified.AsMap(), map[string]string{})
+	assert.Assert(t, cluster.Status.Parameters == nil)
+	assert.Equal(t, len(recorder.Events), 0)
+
+	cluster.Spec.Config.Parameters = map[string]intstr.IntOrString{
+		"work_mem":  intstr.FromString("16MB"),
+		"wal_level": intstr.FromString("minimal"),
+		"wrok_mem":  intstr.FromString("16MB"),
+	}
+
+	parameters = postgres.NewParameters()
+	reconciler.setPostgresParameters(cluster, &parameters)
+	assert.DeepEqual(t, parameters.Specified.AsMap(), map[string]string{"work_mem": "16MB"})
+	assert.DeepEqual(t, cluster.Status.Parameters.Rejected, []string{
+		"wal_level: managed by the operator",
+		"wrok_mem: unknown in PostgreSQL 17",
+	})
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Type, "Warning")
+	assert.Equal(t, recorder.Events[0].Reason, "InvalidParameters")
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "wrok_mem: unknown"))
+
+	// The same rejections are reported once.
+	reconciler.setPostgresParameters(cluster, &parameters)
+	assert.Equal(t, len(recorder.Events), 1)
+
+	// Parameters of instance sets are checked the same way.
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+		Name: "analytics",
+		Config: &v1beta1.PostgresInstanceConfig{
+			Parameters: map[string]intstr.IntOrString{
+				"ssl":      intstr.FromString("off"),
+				"work_mem": intstr.FromString("1GB"),
+			},
+		},
+	}}
+	reconciler.setPostgresParameters(cluster, &parameters)
+	assert.DeepEqual(t, cluster.Status.Parameters.Rejected, []string{
+		"wal_level: managed by the operator",
+		"wrok_mem: unknown in PostgreSQL 17",
+		`instance set "analytics": ssl: managed by the operator`,
+	})
+	assert.Equal(t, len(recorder.Events), 2)
+
+	set, rejected := instanceSetParameters(cluster, &cluster.Spec.InstanceSets[0], parameters.Mandatory)
+	assert.DeepEqual(t, set.Specified.AsMap(), map[string]string{"work_mem": "1GB"})
+	assert.Equal(t, set.Mandatory, parameters.Mandatory)
+	assert.DeepEqual(t, rejected, []string{"ssl: managed by the operator"})
+	cluster.Spec.InstanceSets = nil
+
+	// Pending restarts remain when rejections go away.
+	cluster.Spec.Config.Parameters = nil
+	cluster.Status.Parameters.PendingRestart = []string{"shared_buffers"}
+	reconciler.setPostgresParameters(cluster, &parameters)
+	assert.Assert(t, cluster.Status.Parameters.Rejected == nil)
+	assert.DeepEqual(t, cluster.Status.Parameters.PendingRestart, []string{"shared_buffers"})
+}
+
+func TestObservePendingRestart(t *testing.T) {
+	t.Parallel()
+
+	pod := func(status string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Annotations = map[string]string{"status": status}
+		return pod
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	observePendingRestart(cluster, &observedInstances{})
+	assert.Assert(t, cluster.Status.Parameters == nil)
+
+	observePendingRestart(cluster, &observedInstances{forCluster: []*Instance{
+		{Pods: []*corev1.Pod{pod(`{"pending_restart":true,"pending_restart_reason":{` +
+			`"shared_buffers":{"old_value":"128MB","new_value":"256MB"}}}`)}},
+		{Pods: []*corev1.Pod{pod(`{"pending_restart":true,"pending_restart_reason":{` +
+			`"max_connections":{"old_value":"100","new_value":"200"},` +
+			`"shared_buffers":{"old_value":"128MB","new_value":"256MB"}}}`)}},
+		{Pods: []*corev1.Pod{pod(`{}`)}},
+		{Name: "stopped"},
+	}})
+	assert.DeepEqual(t, cluster.Status.Parameters.PendingRestart,
+		[]string{"max_connections", "shared_buffers"})
+
+	// The status goes away once every instance restarts.
+	observePendingRestart(cluster, &observedInstances{forCluster: []*Instance{
+		{Pods: []*corev1.Pod{pod(`{}`)}},
+	}})
+	assert.Assert(t, cluster.Status.Parameters == nil)
+}
+
 func TestValidatePostgresUsers(t *testing.T) {
 	t.Parallel()
 
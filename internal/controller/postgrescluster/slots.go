@@ -0,0 +1,205 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// ConditionReplicationSlotsRetainingWAL is the type used in a condition to
+// indicate whether or not any permanent physical replication slot retains
+// more WAL than its threshold.
+const ConditionReplicationSlotsRetainingWAL = "ReplicationSlotsRetainingWAL"
+
+// replicationSlotsInterval is how often replication slots are observed while
+// any are declared.
+const replicationSlotsInterval = time.Minute
+
+// replicationSlotsWALUnit is the amount of WAL to which status rounds. Status
+// changes only when a slot falls behind or catches up by at least this much.
+const replicationSlotsWALUnit = 16 << 20
+
+// roundWAL rounds bytes down to a multiple of [replicationSlotsWALUnit].
+func roundWAL(bytes *int64) *int64 {
+	if bytes == nil {
+		return nil
+	}
+	rounded := *bytes - *bytes%replicationSlotsWALUnit
+	return &rounded
+}
+
+// replicationSlot is one row of pg_replication_slots.
+type replicationSlot struct {
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	Active            bool   `json:"active"`
+	ConfirmedFlushLSN string `json:"confirmed_flush_lsn"`
+	LagBytes          *int64 `json:"lag_bytes"`
+	RetainedBytes     *int64 `json:"retained_bytes"`
+}
+
+// queryReplicationSlots returns the replication slots of the PostgreSQL
+// server reached through exec. Amounts of WAL are known only on the leader.
+func queryReplicationSlots(ctx context.Context, exec postgres.Executor) ([]replicationSlot, error) {
+	row, err := queryRow(ctx, exec, ``+
+		`SELECT COALESCE(pg_catalog.json_agg(pg_catalog.json_build_object(`+
+		` 'name', slot_name, 'type', slot_type, 'active', active,`+
+		` 'confirmed_flush_lsn', confirmed_flush_lsn,`+
+		` 'lag_bytes', CASE WHEN NOT pg_catalog.pg_is_in_recovery() THEN`+
+		`  pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint END,`+
+		` 'retained_bytes', CASE WHEN NOT pg_catalog.pg_is_in_recovery() THEN`+
+		`  pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), restart_lsn)::bigint END`+
+		` ) ORDER BY slot_name), '[]')`+
+		` FROM pg_catalog.pg_replication_slots;`)
+
+	var slots []replicationSlot
+	if err == nil {
+		err = json.Unmarshal([]byte(row[0]), &slots)
+	}
+	return slots, err
+}
+
+// reconcileReplicationSlots observes the permanent replication slots that
+// Patroni keeps for cluster and records them in its status. It returns how
+// long to wait before observing them again.
+func (r *Reconciler) reconcileReplicationSlots(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) time.Duration {
+	if cluster.Spec.Patroni == nil ||
+		len(cluster.Spec.Patroni.LogicalSlots)+len(cluster.Spec.Patroni.PhysicalSlots) == 0 {
+		cluster.Status.Patroni.LogicalSlots = nil
+		cluster.Status.Patroni.PhysicalSlots = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionReplicationSlotsRetainingWAL)
+		return 0
+	}
+
+	log := logging.FromContext(ctx)
+	logical := make([]v1beta1.PatroniLogicalSlotStatus, len(cluster.Spec.Patroni.LogicalSlots))
+	for i, slot := range cluster.Spec.Patroni.LogicalSlots {
+		logical[i].Name = slot.Name
+	}
+	physical := make([]v1beta1.PatroniPhysicalSlotStatus, len(cluster.Spec.Patroni.PhysicalSlots))
+	for i, slot := range cluster.Spec.Patroni.PhysicalSlots {
+		physical[i].Name = slot.Name
+	}
+
+	// Look for the slots in every running instance. An instance that cannot
+	// be queried is neither missing nor holding any slot.
+	var leaderObserved bool
+	for _, instance := range instances.forCluster {
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running ||
+			!known || len(instance.Pods) != 1 {
+			continue
+		}
+
+		pod := instance.Pods[0]
+		exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+			return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+		}
+
+		slots, err := queryReplicationSlots(ctx, postgres.Executor(exec))
+		if err != nil {
+			log.Error(err, "unable to observe replication slots", "instance", instance.Name)
+			continue
+		}
+
+		find := func(name, kind string) *replicationSlot {
+			index := slices.IndexFunc(slots, func(slot replicationSlot) bool {
+				return slot.Name == name && slot.Type == kind
+			})
+			if index < 0 {
+				return nil
+			}
+			return &slots[index]
+		}
+
+		primary, _ := instance.IsPrimary()
+		leaderObserved = leaderObserved || primary
+
+		for i := range logical {
+			if slot := find(logical[i].Name, "logical"); slot == nil {
+				logical[i].MissingInstances = append(logical[i].MissingInstances, instance.Name)
+			} else if primary {
+				logical[i].ConfirmedFlushLSN = slot.ConfirmedFlushLSN
+				logical[i].LagBytes = roundWAL(slot.LagBytes)
+			}
+		}
+		for i := range physical {
+			if slot := find(physical[i].Name, "physical"); slot != nil && primary {
+				physical[i].Active = slot.Active
+				physical[i].RetainedWALBytes = slot.RetainedBytes
+			}
+		}
+	}
+
+	cluster.Status.Patroni.LogicalSlots = nil
+	if len(logical) > 0 {
+		cluster.Status.Patroni.LogicalSlots = logical
+	}
+	cluster.Status.Patroni.PhysicalSlots = nil
+	if len(physical) > 0 {
+		cluster.Status.Patroni.PhysicalSlots = physical
+	}
+
+	// Compare the WAL retained by each physical slot to its threshold. Report
+	// only the names of slots so the condition changes only when they do.
+	var thresholds bool
+	var exceeded []string
+	for i, slot := range cluster.Spec.Patroni.PhysicalSlots {
+		if slot.RetainedWALThreshold != nil {
+			thresholds = true
+
+			if retained := physical[i].RetainedWALBytes; retained != nil &&
+				*retained > slot.RetainedWALThreshold.Value() {
+				exceeded = append(exceeded, fmt.Sprintf("%q", slot.Name))
+			}
+		}
+		physical[i].RetainedWALBytes = roundWAL(physical[i].RetainedWALBytes)
+	}
+
+	switch {
+	case !thresholds:
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionReplicationSlotsRetainingWAL)
+
+	case !leaderObserved:
+		// Keep the previous condition until the leader can be queried.
+
+	case len(exceeded) > 0:
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ConditionReplicationSlotsRetainingWAL,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ThresholdExceeded",
+			Message: "Replication slots retain more WAL than their threshold: " + strings.Join(exceeded, ", "),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+
+	default:
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ConditionReplicationSlotsRetainingWAL,
+			Status:  metav1.ConditionFalse,
+			Reason:  "WithinThreshold",
+			Message: "Replication slots retain less WAL than their threshold",
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	}
+
+	return replicationSlotsInterval
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestQueryReplicationSlots(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := queryReplicationSlots(ctx, func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			return errors.New("boom")
+		})
+		assert.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		_, err := queryReplicationSlots(ctx, func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			b, _ := io.ReadAll(stdin)
+			assert.Equal(t, string(b), strings.Join([]string{
+				`\set QUIET on`,
+				`\pset format unaligned`,
+				`\pset tuples_only on`,
+				`SELECT COALESCE(pg_catalog.json_agg(pg_catalog.json_build_object(` +
+					` 'name', slot_name, 'type', slot_type, 'active', active,` +
+					` 'confirmed_flush_lsn', confirmed_flush_lsn,` +
+					` 'lag_bytes', CASE WHEN NOT pg_catalog.pg_is_in_recovery() THEN` +
+					`  pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint END,` +
+					` 'retained_bytes', CASE WHEN NOT pg_catalog.pg_is_in_recovery() THEN` +
+					`  pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), restart_lsn)::bigint END` +
+					` ) ORDER BY slot_name), '[]')` +
+					` FROM pg_catalog.pg_replication_slots;`,
+			}, "\n"))
+
+			_, err := stdout.Write([]byte(`[]` + "\n"))
+			return err
+		})
+		assert.NilError(t, err)
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		slots, err := queryReplicationSlots(ctx, func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			b, _ := io.ReadAll(stdin)
+			assert.Assert(t, strings.Contains(string(b), `pg_replication_slots`))
+
+			_, err := stdout.Write([]byte(`[` +
+				`{"name":"one","type":"logical","confirmed_flush_lsn":"0/3000060","lag_bytes":1024},` +
+				`{"name":"two","type":"physical","active":true,"retained_bytes":2048}` +
+				`]` + "\n"))
+			return err
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(slots), 2)
+		assert.Equal(t, slots[0].Name, "one")
+		assert.Equal(t, slots[0].ConfirmedFlushLSN, "0/3000060")
+		assert.Equal(t, *slots[0].LagBytes, int64(1024))
+		assert.Equal(t, slots[1].Type, "physical")
+		assert.Assert(t, slots[1].Active)
+		assert.Equal(t, *slots[1].RetainedBytes, int64(2048))
+		assert.Assert(t, slots[1].LagBytes == nil)
+	})
+}
+
+func TestReconcileReplicationSlots(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	instance := func(name, role string) *Instance {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Labels = map[string]string{naming.LabelRole: role}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}, Runner: &appsv1.StatefulSet{}}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Status.Patroni.LogicalSlots = []v1beta1.PatroniLogicalSlotStatus{{Name: "old"}}
+
+		r := &Reconciler{}
+		assert.Equal(t, r.reconcileReplicationSlots(ctx, cluster, &observedInstances{}), time.Duration(0))
+		assert.Assert(t, cluster.Status.Patroni.LogicalSlots == nil)
+	})
+
+	t.Run("Observed", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			LogicalSlots: []v1beta1.PatroniLogicalSlot{
+				{Name: "cdc", Database: "app", Plugin: "pgoutput"},
+				{Name: "other", Database: "app", Plugin: "pgoutput"},
+			},
+		}
+
+		r := &Reconciler{}
+		r.PodExec = func(
+			_ context.Context, namespace, pod, container string,
+			_ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			assert.Equal(t, namespace, "ns1")
+			assert.Equal(t, container, "database")
+
+			var err error
+			switch pod {
+			case "leader-0":
+				_, err = stdout.Write([]byte(`[` +
+					`{"name":"cdc","type":"logical","confirmed_flush_lsn":"0/3000060","lag_bytes":96},` +
+					`{"name":"other","type":"logical","confirmed_flush_lsn":"0/1000000","lag_bytes":34603008}` +
+					`]` + "\n"))
+			case "synced-0":
+				_, err = stdout.Write([]byte(`[` +
+					`{"name":"cdc","type":"logical","confirmed_flush_lsn":"0/3000000","lag_bytes":null},` +
+					`{"name":"other","type":"logical","confirmed_flush_lsn":"0/1000000","lag_bytes":null}` +
+					`]` + "\n"))
+			case "behind-0":
+				_, err = stdout.Write([]byte(`[` +
+					`{"name":"other","type":"logical","lag_bytes":null}` +
+					`]` + "\n"))
+			default:
+				err = errors.New("unreachable")
+			}
+			return err
+		}
+
+		requeue := r.reconcileReplicationSlots(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{
+				instance("leader", naming.RolePatroniLeader),
+				instance("synced", naming.RolePatroniReplica),
+				instance("behind", naming.RolePatroniReplica),
+				instance("broken", naming.RolePatroniReplica),
+				{Name: "stopped"},
+			},
+		})
+		assert.Equal(t, requeue, replicationSlotsInterval)
+
+		status := cluster.Status.Patroni.LogicalSlots
+		assert.Equal(t, len(status), 2)
+
+		// Only the leader reports lag. Instances that could not be queried
+		// are not missing anything.
+		assert.Equal(t, status[0].Name, "cdc")
+		assert.Equal(t, status[0].ConfirmedFlushLSN, "0/3000060")
+		assert.Equal(t, *status[0].LagBytes, int64(0))
+		assert.DeepEqual(t, status[0].MissingInstances, []string{"behind"})
+
+		assert.Equal(t, status[1].Name, "other")
+		assert.Equal(t, *status[1].LagBytes, int64(32<<20))
+		assert.Assert(t, status[1].MissingInstances == nil)
+
+		assert.Assert(t, cluster.Status.Patroni.PhysicalSlots == nil)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionReplicationSlotsRetainingWAL) == nil)
+	})
+
+	t.Run("Retained", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			PhysicalSlots: []v1beta1.PatroniPhysicalSlot{
+				{Name: "big", RetainedWALThreshold: initialize.Pointer(resource.MustParse("1Ki"))},
+				{Name: "small", RetainedWALThreshold: initialize.Pointer(resource.MustParse("1Ki"))},
+				{Name: "unlimited"},
+			},
+		}
+
+		var stdout string
+		r := &Reconciler{}
+		r.PodExec = func(
+			_ context.Context, _, _, _ string, _ io.Reader, w, _ io.Writer, _ ...string,
+		) error {
+			_, err := w.Write([]byte(stdout))
+			return err
+		}
+
+		stdout = `[` +
+			`{"name":"big","type":"physical","active":false,"retained_bytes":4096},` +
+			`{"name":"small","type":"physical","active":true,"retained_bytes":512},` +
+			`{"name":"unlimited","type":"physical","active":false,"retained_bytes":20000000}` +
+			`]` + "\n"
+
+		leader := &observedInstances{forCluster: []*Instance{instance("leader", naming.RolePatroniLeader)}}
+		assert.Equal(t, r.reconcileReplicationSlots(ctx, cluster, leader), replicationSlotsInterval)
+
+		status := cluster.Status.Patroni.PhysicalSlots
+		assert.Equal(t, len(status), 3)
+		assert.Equal(t, *status[0].RetainedWALBytes, int64(0))
+		assert.Equal(t, *status[2].RetainedWALBytes, int64(16<<20))
+		assert.Assert(t, status[1].Active)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicationSlotsRetainingWAL)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Reason, "ThresholdExceeded")
+		assert.Equal(t, condition.Message,
+			`Replication slots retain more WAL than their threshold: "big"`)
+		assert.Assert(t, !strings.Contains(condition.Message, "small"))
+		assert.Assert(t, !strings.Contains(condition.Message, "unlimited"))
+
+		// The condition stays the same while the leader is unavailable.
+		replica := &observedInstances{forCluster: []*Instance{instance("replica", naming.RolePatroniReplica)}}
+		r.reconcileReplicationSlots(ctx, cluster, replica)
+		assert.Assert(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionReplicationSlotsRetainingWAL))
+		assert.Assert(t, cluster.Status.Patroni.PhysicalSlots[0].RetainedWALBytes == nil)
+
+		stdout = `[{"name":"big","type":"physical","active":true,"retained_bytes":0}]` + "\n"
+		r.reconcileReplicationSlots(ctx, cluster, leader)
+		assert.Assert(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionReplicationSlotsRetainingWAL))
+
+		// The condition goes away with the thresholds.
+		cluster.Spec.Patroni.PhysicalSlots = []v1beta1.PatroniPhysicalSlot{{Name: "big"}}
+		r.reconcileReplicationSlots(ctx, cluster, leader)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionReplicationSlotsRetainingWAL) == nil)
+	})
+}
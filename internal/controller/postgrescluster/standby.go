@@ -0,0 +1,310 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// ConditionStandbySourceFenced is the type used in a condition to indicate
+	// whether or not the source of a standby cluster can no longer accept writes
+	ConditionStandbySourceFenced = "StandbySourceFenced"
+
+	// ConditionStandbyReplayCaughtUp is the type used in a condition to indicate
+	// whether or not a standby cluster has replayed every WAL file in its repository
+	ConditionStandbyReplayCaughtUp = "StandbyReplayCaughtUp"
+
+	// ConditionStandbyPromoted is the type used in a condition to indicate whether
+	// or not a standby cluster has been promoted
+	ConditionStandbyPromoted = "StandbyPromoted"
+
+	// ConditionStandbyDemoted is the type used in a condition to indicate whether
+	// or not a former primary cluster is following its new source as a standby
+	ConditionStandbyDemoted = "StandbyDemoted"
+)
+
+// holdStandbyPromotion returns true when cluster should remain a standby until
+// the checks in its promotion spec have passed.
+func holdStandbyPromotion(cluster *v1beta1.PostgresCluster) bool {
+	standby := cluster.Spec.Standby
+	if standby == nil || standby.Enabled || standby.Promotion == nil {
+		return false
+	}
+
+	// Hold only clusters that were observed acting as a standby. A cluster
+	// that has not been a standby has nothing to promote.
+	promoted := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyPromoted)
+	if promoted == nil || promoted.Status != metav1.ConditionFalse {
+		return false
+	}
+
+	return !meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionStandbySourceFenced) ||
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionStandbyReplayCaughtUp)
+}
+
+// standbyInEffect returns true when cluster should follow the source in its
+// standby spec. This differs from spec.standby.enabled while a promotion is
+// held and while a disaster recovery site promotion or demotion is in effect.
+func standbyInEffect(ctx context.Context, cluster *v1beta1.PostgresCluster) bool {
+	if cluster.Spec.Standby == nil || sitePromotionInEffect(ctx, cluster) {
+		return false
+	}
+	return cluster.Spec.Standby.Enabled ||
+		holdStandbyPromotion(cluster) || siteDemotionInEffect(ctx, cluster)
+}
+
+// reconcileStandbyPromotion reports the progress of a standby cluster through
+// promotion and demotion in the conditions of cluster. When held is true, the
+// promotion is being held by holdStandbyPromotion.
+func (r *Reconciler) reconcileStandbyPromotion(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instances *observedInstances, held bool,
+) error {
+	standby := cluster.Spec.Standby
+	if standby == nil || standby.Promotion == nil {
+		for _, condition := range []string{
+			ConditionStandbySourceFenced, ConditionStandbyReplayCaughtUp,
+			ConditionStandbyPromoted, ConditionStandbyDemoted,
+		} {
+			meta.RemoveStatusCondition(&cluster.Status.Conditions, condition)
+		}
+		cluster.Status.Standby = nil
+		return nil
+	}
+
+	// Look for the running Pod that Patroni has chosen as its leader.
+	var leader *corev1.Pod
+	for _, instance := range instances.forCluster {
+		if running, known := instance.IsRunning(naming.ContainerDatabase); running &&
+			known && len(instance.Pods) == 1 &&
+			(patroni.PodIsPrimary(instance.Pods[0]) || patroni.PodIsStandbyLeader(instance.Pods[0])) {
+			leader = instance.Pods[0]
+		}
+	}
+	if leader == nil {
+		// There is nothing to observe; try again later.
+		return nil
+	}
+
+	exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+		return r.PodExec(ctx, leader.Namespace, leader.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+	}
+
+	condition := func(kind string, status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    kind,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	}
+
+	// The cluster should be a standby.
+	if standby.Enabled && !sitePromotionInEffect(ctx, cluster) {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionStandbySourceFenced)
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionStandbyReplayCaughtUp)
+
+		if patroni.PodIsPrimary(leader) {
+			// Patroni follows the source once it reads the "standby_cluster"
+			// section of its dynamic configuration. It uses pg_rewind to undo
+			// any changes that did not reach the source.
+			condition(ConditionStandbyDemoted, metav1.ConditionFalse, "Demoting",
+				"Waiting for the primary to follow "+standbySource(standby))
+			return nil
+		}
+
+		if demoted := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionStandbyDemoted); demoted != nil && demoted.Status != metav1.ConditionTrue {
+			condition(ConditionStandbyDemoted, metav1.ConditionTrue, "Demoted",
+				"The cluster is following "+standbySource(standby))
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "StandbyDemoted",
+				"The cluster is following "+standbySource(standby))
+		}
+		condition(ConditionStandbyPromoted, metav1.ConditionFalse, "Standby",
+			"The cluster is following "+standbySource(standby))
+		return nil
+	}
+
+	// The cluster should be promoted, and Patroni has done so.
+	if patroni.PodIsPrimary(leader) {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionStandbyDemoted)
+
+		if promoted := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionStandbyPromoted); promoted != nil && promoted.Status == metav1.ConditionFalse {
+			api, err := r.PatroniClient(ctx, cluster, leader)
+			var timeline int64
+			if err == nil {
+				timeline, err = api.GetTimeline(ctx)
+			}
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if timeline == 0 {
+				return errors.New("error getting and parsing current timeline")
+			}
+
+			message := fmt.Sprintf("The cluster was promoted to timeline %d", timeline)
+			condition(ConditionStandbyPromoted, metav1.ConditionTrue, "Promoted", message)
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "StandbyPromoted", message)
+
+			cluster.Status.Standby = &v1beta1.PostgresStandbyStatus{PromotionTimeline: &timeline}
+		}
+		return nil
+	}
+
+	// The cluster should be promoted, but it is still following its source.
+	// When the promotion is not held, Patroni is already promoting it.
+	if !held {
+		return nil
+	}
+
+	condition(ConditionStandbyPromoted, metav1.ConditionFalse, "Promoting",
+		"Waiting for promotion checks to pass")
+
+	if standby.Promotion.SourceFenced {
+		condition(ConditionStandbySourceFenced, metav1.ConditionTrue, "Fenced",
+			"The source is fenced according to spec.standby.promotion.sourceFenced")
+	} else if standby.Host == "" {
+		condition(ConditionStandbySourceFenced, metav1.ConditionFalse, "Unverified",
+			"Set spec.standby.promotion.sourceFenced after fencing the source")
+	} else if address := standbySourceAddress(standby); dialStandbySource(ctx, address) == nil {
+		condition(ConditionStandbySourceFenced, metav1.ConditionFalse, "Reachable",
+			"The source at "+address+" is accepting connections")
+	} else {
+		condition(ConditionStandbySourceFenced, metav1.ConditionTrue, "Unreachable",
+			"The source at "+address+" is not accepting connections")
+	}
+
+	if standby.RepoName == "" {
+		condition(ConditionStandbyReplayCaughtUp, metav1.ConditionTrue, "NoRepository",
+			"The cluster does not follow a pgBackRest repository")
+		return nil
+	}
+
+	latest, err := pgbackrest.Executor(exec).LatestArchivedWAL(ctx, standby.RepoName)
+	var timeline, replayed, size uint64
+	if err == nil {
+		timeline, replayed, size, err = standbyReplayPosition(ctx, postgres.Executor(exec))
+	}
+	if err != nil {
+		condition(ConditionStandbyReplayCaughtUp, metav1.ConditionUnknown, "Error",
+			"Unable to compare WAL replay with the repository")
+		return err
+	}
+
+	latestTimeline, latestSegment := walFileSegment(latest, size)
+	if latest == "" || latestTimeline < timeline ||
+		(latestTimeline == timeline && latestSegment <= replayed/size) {
+		condition(ConditionStandbyReplayCaughtUp, metav1.ConditionTrue, "CaughtUp",
+			"Every WAL file in "+standby.RepoName+" has been replayed")
+	} else {
+		condition(ConditionStandbyReplayCaughtUp, metav1.ConditionFalse, "Replaying",
+			"Waiting to replay WAL file "+latest+" from "+standby.RepoName)
+	}
+
+	return nil
+}
+
+// dialStandbySource returns nil when something at address accepts TCP connections.
+var dialStandbySource = func(ctx context.Context, address string) error {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err == nil {
+		err = conn.Close()
+	}
+	return err
+}
+
+// standbySource returns a description of what standby follows.
+func standbySource(standby *v1beta1.PostgresStandbySpec) string {
+	if standby.Host != "" {
+		return standbySourceAddress(standby)
+	}
+	return standby.RepoName
+}
+
+// standbySourceAddress returns the network address of the PostgreSQL server
+// that standby follows.
+func standbySourceAddress(standby *v1beta1.PostgresStandbySpec) string {
+	port := "5432"
+	if standby.Port != nil {
+		port = strconv.Itoa(int(*standby.Port))
+	}
+	return net.JoinHostPort(standby.Host, port)
+}
+
+// queryRow executes sql using exec and returns the fields of the first row
+// it returns.
+func queryRow(ctx context.Context, exec postgres.Executor, sql string) ([]string, error) {
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(strings.Join([]string{
+		`\set QUIET on`,
+		`\pset format unaligned`,
+		`\pset tuples_only on`,
+		sql,
+	}, "\n")), nil)
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %v", err, stderr))
+	}
+
+	row, _, _ := strings.Cut(stdout, "\n")
+	return strings.Split(row, "|"), nil
+}
+
+// standbyReplayPosition returns the timeline and location of the last WAL
+// record replayed during recovery and the size of WAL segments. The timeline
+// is that of the latest restartpoint.
+// - https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-PG-CONTROL-CHECKPOINT
+func standbyReplayPosition(ctx context.Context, exec postgres.Executor) (uint64, uint64, uint64, error) {
+	row, err := queryRow(ctx, exec, ``+
+		`SELECT (pg_catalog.pg_control_checkpoint()).timeline_id,`+
+		` pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_last_wal_replay_lsn(), '0/0'),`+
+		` setting FROM pg_catalog.pg_settings WHERE name = 'wal_segment_size';`)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if len(row) == 3 {
+		timeline, err1 := strconv.ParseUint(row[0], 10, 32)
+		position, err2 := strconv.ParseUint(row[1], 10, 64)
+		size, err3 := strconv.ParseUint(row[2], 10, 64)
+		if err1 == nil && err2 == nil && err3 == nil && size > 0 {
+			return timeline, position, size, nil
+		}
+	}
+	return 0, 0, 0, errors.Errorf("unexpected replay position: %q", row)
+}
+
+// walFileSegment returns the timeline and segment number of the WAL file
+// named name.
+// - https://git.postgresql.org/gitweb/?p=postgresql.git;f=src/include/access/xlog_internal.h;hb=REL_16_0#l163
+func walFileSegment(name string, size uint64) (uint64, uint64) {
+	if len(name) < 24 {
+		return 0, 0
+	}
+	timeline, _ := strconv.ParseUint(name[0:8], 16, 32)
+	log, _ := strconv.ParseUint(name[8:16], 16, 32)
+	seg, _ := strconv.ParseUint(name[16:24], 16, 32)
+	return timeline, log*(0x100000000/size) + seg
+}
@@ -0,0 +1,288 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestHoldStandbyPromotion(t *testing.T) {
+	t.Parallel()
+
+	setCondition := func(cluster *v1beta1.PostgresCluster, kind string, status metav1.ConditionStatus) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: kind, Status: status, Reason: "Testing",
+		})
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	assert.Assert(t, !holdStandbyPromotion(cluster), "expected no hold without standby")
+
+	cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: false, RepoName: "repo1"}
+	setCondition(cluster, ConditionStandbyPromoted, metav1.ConditionFalse)
+	assert.Assert(t, !holdStandbyPromotion(cluster), "expected no hold without promotion checks")
+	assert.Assert(t, !cluster.Spec.Standby.Enabled)
+
+	cluster.Spec.Standby.Promotion = &v1beta1.PostgresStandbyPromotionSpec{}
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionStandbyPromoted)
+	assert.Assert(t, !holdStandbyPromotion(cluster), "expected no hold before being a standby")
+	assert.Assert(t, !cluster.Spec.Standby.Enabled)
+
+	setCondition(cluster, ConditionStandbyPromoted, metav1.ConditionFalse)
+	setCondition(cluster, ConditionStandbySourceFenced, metav1.ConditionTrue)
+	assert.Assert(t, holdStandbyPromotion(cluster), "expected hold before replay catches up")
+	assert.Assert(t, !cluster.Spec.Standby.Enabled, "expected no change to spec")
+
+	setCondition(cluster, ConditionStandbyReplayCaughtUp, metav1.ConditionTrue)
+	assert.Assert(t, !holdStandbyPromotion(cluster), "expected no hold after checks pass")
+	assert.Assert(t, !cluster.Spec.Standby.Enabled)
+
+	setCondition(cluster, ConditionStandbyPromoted, metav1.ConditionTrue)
+	setCondition(cluster, ConditionStandbySourceFenced, metav1.ConditionFalse)
+	assert.Assert(t, !holdStandbyPromotion(cluster), "expected no hold after promotion")
+}
+
+func TestReconcileStandbyPromotion(t *testing.T) {
+	ctx := context.Background()
+
+	leader := func(role string) *observedInstances {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", "hippo-instance1-abcd-0"
+		pod.Annotations = map[string]string{"status": `{"role":"` + role + `"}`}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return &observedInstances{forCluster: []*Instance{{
+			Name: "hippo-instance1-abcd", Pods: []*corev1.Pod{pod},
+		}}}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Status.Standby = &v1beta1.PostgresStandbyStatus{}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionStandbyPromoted, Status: metav1.ConditionTrue, Reason: "Promoted",
+		})
+
+		r := &Reconciler{}
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("master"), false))
+		assert.Equal(t, len(cluster.Status.Conditions), 0)
+		assert.Assert(t, cluster.Status.Standby == nil)
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{
+			Enabled: true, RepoName: "repo1",
+			Promotion: &v1beta1.PostgresStandbyPromotionSpec{},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), false))
+
+		promoted := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyPromoted)
+		assert.Assert(t, promoted != nil)
+		assert.Equal(t, promoted.Status, metav1.ConditionFalse)
+		assert.Equal(t, promoted.Reason, "Standby")
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Demote", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{
+			Enabled: true, Host: "example.com",
+			Promotion: &v1beta1.PostgresStandbyPromotionSpec{},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{Recorder: recorder}
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("master"), false))
+
+		demoted := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyDemoted)
+		assert.Assert(t, demoted != nil)
+		assert.Equal(t, demoted.Status, metav1.ConditionFalse)
+		assert.Equal(t, demoted.Reason, "Demoting")
+		assert.Equal(t, demoted.Message, "Waiting for the primary to follow example.com:5432")
+
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), false))
+
+		demoted = meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyDemoted)
+		assert.Equal(t, demoted.Status, metav1.ConditionTrue)
+		assert.Equal(t, demoted.Reason, "Demoted")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "StandbyDemoted")
+	})
+
+	t.Run("Held", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{
+			Enabled: false, Host: "source.example.com", Port: initialize.Int32(6543),
+			RepoName:  "repo2",
+			Promotion: &v1beta1.PostgresStandbyPromotionSpec{},
+		}
+
+		var archived, replay string
+		r := &Reconciler{
+			PodExec: func(
+				ctx context.Context, namespace, pod, container string,
+				stdin io.Reader, stdout, stderr io.Writer, command ...string,
+			) error {
+				assert.Equal(t, namespace, "ns1")
+				assert.Equal(t, pod, "hippo-instance1-abcd-0")
+				assert.Equal(t, container, "database")
+
+				switch command[0] {
+				case "pgbackrest":
+					_, _ = io.WriteString(stdout, `[{"archive":[{"max":"`+archived+`"}]}]`)
+				case "psql":
+					b, _ := io.ReadAll(stdin)
+					assert.Assert(t, strings.Contains(string(b), "pg_last_wal_replay_lsn"))
+					_, _ = io.WriteString(stdout, replay+"|50331752|16777216\n")
+				default:
+					t.Fatalf("unexpected command: %q", command)
+				}
+				return nil
+			},
+		}
+
+		dialed := []string{}
+		reachable := true
+		dial := dialStandbySource
+		t.Cleanup(func() { dialStandbySource = dial })
+		dialStandbySource = func(_ context.Context, address string) error {
+			dialed = append(dialed, address)
+			if reachable {
+				return nil
+			}
+			return errors.New("refused")
+		}
+
+		// The source is reachable and replay is behind the repository.
+		archived, replay = "000000020000000000000004", "2"
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), true))
+		assert.DeepEqual(t, dialed, []string{"source.example.com:6543"})
+
+		fenced := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbySourceFenced)
+		assert.Equal(t, fenced.Status, metav1.ConditionFalse)
+		assert.Equal(t, fenced.Reason, "Reachable")
+
+		caughtUp := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyReplayCaughtUp)
+		assert.Equal(t, caughtUp.Status, metav1.ConditionFalse)
+		assert.Equal(t, caughtUp.Reason, "Replaying")
+
+		promoted := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyPromoted)
+		assert.Equal(t, promoted.Status, metav1.ConditionFalse)
+		assert.Equal(t, promoted.Reason, "Promoting")
+
+		// The repository has a later timeline than replay.
+		archived, replay = "000000030000000000000001", "2"
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), true))
+
+		caughtUp = meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyReplayCaughtUp)
+		assert.Equal(t, caughtUp.Status, metav1.ConditionFalse)
+
+		// The source is unreachable and replay is in the latest WAL file.
+		reachable = false
+		archived, replay = "000000020000000000000003", "2"
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), true))
+
+		fenced = meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbySourceFenced)
+		assert.Equal(t, fenced.Status, metav1.ConditionTrue)
+		assert.Equal(t, fenced.Reason, "Unreachable")
+
+		caughtUp = meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyReplayCaughtUp)
+		assert.Equal(t, caughtUp.Status, metav1.ConditionTrue)
+
+		// The source is fenced according to the spec.
+		cluster.Spec.Standby.Promotion.SourceFenced = true
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), true))
+		assert.Equal(t, len(dialed), 3, "expected no dial")
+
+		fenced = meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbySourceFenced)
+		assert.Equal(t, fenced.Status, metav1.ConditionTrue)
+		assert.Equal(t, fenced.Reason, "Fenced")
+	})
+
+	t.Run("Promoted", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{
+			Enabled: false, RepoName: "repo1",
+			Promotion: &v1beta1.PostgresStandbyPromotionSpec{SourceFenced: true},
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionStandbyPromoted, Status: metav1.ConditionFalse, Reason: "Promoting",
+		})
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		r := &Reconciler{
+			Recorder: recorder,
+			PatroniClient: setupPatroniClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Path, "/cluster")
+				_, _ = io.WriteString(w, `{"members":[{"role":"leader","state":"running","timeline":7}]}`)
+			}),
+		}
+
+		// Patroni has not yet promoted.
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("standby_leader"), false))
+		assert.Equal(t, len(recorder.Events), 0)
+
+		assert.NilError(t, r.reconcileStandbyPromotion(ctx, cluster, leader("master"), false))
+
+		promoted := meta.FindStatusCondition(cluster.Status.Conditions, ConditionStandbyPromoted)
+		assert.Equal(t, promoted.Status, metav1.ConditionTrue)
+		assert.Equal(t, promoted.Message, "The cluster was promoted to timeline 7")
+		assert.DeepEqual(t, cluster.Status.Standby, &v1beta1.PostgresStandbyStatus{
+			PromotionTimeline: initialize.Pointer(int64(7)),
+		})
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "StandbyPromoted")
+	})
+}
+
+func TestDialStandbySource(t *testing.T) {
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	address := listener.Addr().String()
+
+	assert.NilError(t, dialStandbySource(ctx, address))
+	assert.NilError(t, listener.Close())
+	assert.Assert(t, dialStandbySource(ctx, address) != nil)
+}
+
+func TestWALFileSegment(t *testing.T) {
+	t.Parallel()
+
+	const size = 16 * 1024 * 1024
+	segment := func(name string, size uint64) [2]uint64 {
+		timeline, segment := walFileSegment(name, size)
+		return [2]uint64{timeline, segment}
+	}
+
+	assert.Equal(t, segment("", size), [2]uint64{0, 0})
+	assert.Equal(t, segment("000000010000000000000003", size), [2]uint64{1, 3})
+	assert.Equal(t, segment("0000000A00000001000000FF", size), [2]uint64{10, 256 + 255})
+	assert.Equal(t, segment("000000010000000200000001", 1024*1024*1024), [2]uint64{1, 9})
+}
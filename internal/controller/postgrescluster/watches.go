@@ -7,14 +7,17 @@ package postgrescluster
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // watchPods returns a handler.EventHandler for Pods.
@@ -61,6 +64,17 @@ func (*Reconciler) watchPods() handler.Funcs {
 				return
 			}
 
+			// Queue an event when Patroni changes the role of a pod. The replica
+			// Service may need different Endpoints.
+			if len(cluster) != 0 &&
+				e.ObjectOld.GetLabels()[naming.LabelRole] != labels[naming.LabelRole] {
+				q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
+					Namespace: e.ObjectNew.GetNamespace(),
+					Name:      cluster,
+				}})
+				return
+			}
+
 			oldAnnotations := e.ObjectOld.GetAnnotations()
 			newAnnotations := e.ObjectNew.GetAnnotations()
 			// If the suggested-pgdata-pvc-size annotation is added or changes, reconcile.
@@ -74,3 +88,89 @@ func (*Reconciler) watchPods() handler.Funcs {
 		},
 	}
 }
+
+// watchPasswordSecrets returns a handler.EventHandler for Secrets that contain
+// passwords managed outside of PGO. It queues every cluster with a user whose
+// password or LDAP bind password comes from the Secret.
+func (r *Reconciler) watchPasswordSecrets() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, secret client.Object) []reconcile.Request {
+		return runtime.Requests(r.findPostgresClustersForPasswordSecret(ctx, client.ObjectKeyFromObject(secret))...)
+	})
+}
+
+// findPostgresClustersForPasswordSecret returns clusters in the namespace of
+// secret that have a user whose password or LDAP bind password comes from secret.
+func (r *Reconciler) findPostgresClustersForPasswordSecret(
+	ctx context.Context, secret client.ObjectKey,
+) []*v1beta1.PostgresCluster {
+	var matching []*v1beta1.PostgresCluster
+	var clusters v1beta1.PostgresClusterList
+
+	// NOTE: If this becomes slow due to a large number of clusters in a single
+	// namespace, we can configure the [manager.Manager] field indexer and pass a
+	// [fields.Selector] here.
+	// - https://book.kubebuilder.io/reference/watching-resources/externally-managed.html
+	if err := r.Client.List(ctx, &clusters, &client.ListOptions{
+		Namespace: secret.Namespace,
+	}); err == nil {
+		for i := range clusters.Items {
+			for j := range clusters.Items[i].Spec.Users {
+				user := clusters.Items[i].Spec.Users[j]
+				source := user.Password.GetSource()
+				if source != nil && source.Secret != nil && source.Secret.Name == secret.Name {
+					matching = append(matching, &clusters.Items[i])
+					break
+				}
+				if auth := user.Authentication; auth != nil && auth.LDAP != nil &&
+					auth.LDAP.BindPassword != nil && auth.LDAP.BindPassword.Name == secret.Name {
+					matching = append(matching, &clusters.Items[i])
+					break
+				}
+			}
+		}
+	}
+	return matching
+}
+
+// watchDatabaseInitSQLScripts returns a handler.EventHandler for ConfigMaps and
+// Secrets that contain init SQL scripts. It queues every cluster with a script
+// that comes from the object so that changed scripts run again.
+func (r *Reconciler) watchDatabaseInitSQLScripts() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, object client.Object) []reconcile.Request {
+		_, secret := object.(*corev1.Secret)
+		return runtime.Requests(r.findPostgresClustersForDatabaseInitSQLScript(
+			ctx, client.ObjectKeyFromObject(object), secret)...)
+	})
+}
+
+// findPostgresClustersForDatabaseInitSQLScript returns clusters in the namespace
+// of source that have an init SQL script in source. When secret is true, source
+// is a Secret; otherwise it is a ConfigMap.
+func (r *Reconciler) findPostgresClustersForDatabaseInitSQLScript(
+	ctx context.Context, source client.ObjectKey, secret bool,
+) []*v1beta1.PostgresCluster {
+	var matching []*v1beta1.PostgresCluster
+	var clusters v1beta1.PostgresClusterList
+
+	// NOTE: If this becomes slow due to a large number of clusters in a single
+	// namespace, we can configure the [manager.Manager] field indexer and pass a
+	// [fields.Selector] here.
+	// - https://book.kubebuilder.io/reference/watching-resources/externally-managed.html
+	if err := r.Client.List(ctx, &clusters, &client.ListOptions{
+		Namespace: source.Namespace,
+	}); err == nil {
+		for i := range clusters.Items {
+			if clusters.Items[i].Spec.DatabaseInitSQL == nil {
+				continue
+			}
+			for _, script := range clusters.Items[i].Spec.DatabaseInitSQL.Scripts {
+				if (secret && script.Secret != nil && script.Secret.Name == source.Name) ||
+					(!secret && script.ConfigMap != nil && script.ConfigMap.Name == source.Name) {
+					matching = append(matching, &clusters.Items[i])
+					break
+				}
+			}
+		}
+	}
+	return matching
+}
@@ -12,9 +12,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestWatchPodsUpdate(t *testing.T) {
@@ -132,6 +138,40 @@ func TestWatchPodsUpdate(t *testing.T) {
 		queue.Done(item)
 	})
 
+	t.Run("RoleChanged", func(t *testing.T) {
+		expected := reconcile.Request{}
+		expected.Namespace = "some-ns"
+		expected.Name = "starfish"
+
+		replica := &corev1.Pod{}
+		replica.Namespace = "some-ns"
+		replica.Labels = map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "starfish",
+			"postgres-operator.crunchydata.com/role":    "replica",
+		}
+
+		primary := replica.DeepCopy()
+		primary.Labels["postgres-operator.crunchydata.com/role"] = "master"
+
+		// Role changed; one reconcile by label.
+		update(ctx, event.UpdateEvent{
+			ObjectOld: replica.DeepCopy(),
+			ObjectNew: primary.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 1, "expected one reconcile")
+
+		item, _ := queue.Get()
+		assert.Equal(t, item, expected)
+		queue.Done(item)
+
+		// Same role; no reconcile.
+		update(ctx, event.UpdateEvent{
+			ObjectOld: replica.DeepCopy(),
+			ObjectNew: replica.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 0, "expected no reconcile")
+	})
+
 	// Pod annotation with arbitrary key; no reconcile.
 	update(ctx, event.UpdateEvent{
 		ObjectOld: &corev1.Pod{
@@ -182,3 +222,81 @@ func TestWatchPodsUpdate(t *testing.T) {
 	}, queue)
 	assert.Equal(t, queue.Len(), 1)
 }
+
+func TestFindPostgresClustersForPasswordSecret(t *testing.T) {
+	ctx := context.Background()
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+	reconciler := &Reconciler{Client: cc}
+
+	for _, tt := range []struct{ namespace, name, users string }{
+		{"ns1", "uses", `[
+			{ name: generated },
+			{ name: external, password: { source: { secret: { name: passwords, key: one } } } },
+		]`},
+		{"ns1", "other", `[
+			{ name: external, password: { source: { secret: { name: other, key: one } } } },
+			{ name: file, password: { source: { file: passwords } } },
+		]`},
+		{"ns2", "elsewhere", `[
+			{ name: external, password: { source: { secret: { name: passwords, key: one } } } },
+		]`},
+		{"ns1", "ldap", `[
+			{ name: searcher, authentication: { ldap: {
+				server: ldap.example.com, baseDN: "dc=example",
+				bindPassword: { name: passwords, key: bind },
+			} } },
+		]`},
+	} {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Namespace, cluster.Name = tt.namespace, tt.name
+		assert.NilError(t, yaml.Unmarshal([]byte(tt.users), &cluster.Spec.Users))
+		assert.NilError(t, cc.Create(ctx, cluster))
+	}
+
+	found := reconciler.findPostgresClustersForPasswordSecret(ctx,
+		client.ObjectKey{Namespace: "ns1", Name: "passwords"})
+	assert.Equal(t, len(found), 2)
+	assert.Equal(t, found[0].Name, "ldap")
+	assert.Equal(t, found[1].Name, "uses")
+
+	assert.Equal(t, len(reconciler.findPostgresClustersForPasswordSecret(ctx,
+		client.ObjectKey{Namespace: "ns3", Name: "passwords"})), 0)
+}
+
+func TestFindPostgresClustersForDatabaseInitSQLScript(t *testing.T) {
+	ctx := context.Background()
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+	reconciler := &Reconciler{Client: cc}
+
+	for _, tt := range []struct{ namespace, name, init string }{
+		{"ns1", "none", `null`},
+		{"ns1", "configmap", `{ scripts: [
+			{ name: one, configMap: { name: other, key: one } },
+			{ name: two, configMap: { name: scripts, key: two } },
+		] }`},
+		{"ns1", "secret", `{ scripts: [
+			{ name: one, secret: { name: scripts, key: one } },
+		] }`},
+		{"ns2", "elsewhere", `{ scripts: [
+			{ name: one, configMap: { name: scripts, key: one } },
+		] }`},
+	} {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Namespace, cluster.Name = tt.namespace, tt.name
+		assert.NilError(t, yaml.Unmarshal([]byte(tt.init), &cluster.Spec.DatabaseInitSQL))
+		assert.NilError(t, cc.Create(ctx, cluster))
+	}
+
+	found := reconciler.findPostgresClustersForDatabaseInitSQLScript(ctx,
+		client.ObjectKey{Namespace: "ns1", Name: "scripts"}, false)
+	assert.Equal(t, len(found), 1)
+	assert.Equal(t, found[0].Name, "configmap")
+
+	found = reconciler.findPostgresClustersForDatabaseInitSQLScript(ctx,
+		client.ObjectKey{Namespace: "ns1", Name: "scripts"}, true)
+	assert.Equal(t, len(found), 1)
+	assert.Equal(t, found[0].Name, "secret")
+
+	assert.Equal(t, len(reconciler.findPostgresClustersForDatabaseInitSQLScript(ctx,
+		client.ObjectKey{Namespace: "ns3", Name: "scripts"}, false)), 0)
+}
@@ -78,6 +78,9 @@ const (
 	// Deprecated
 	BridgeIdentifiers = "BridgeIdentifiers"
 
+	// Support failover between PostgresClusters in different Kubernetes clusters
+	DisasterRecovery = "DisasterRecovery"
+
 	// Support custom sidecars for PostgreSQL instance Pods
 	InstanceSidecars = "InstanceSidecars"
 
@@ -103,6 +106,7 @@ func NewGate() MutableGate {
 		AutoCreateUserSchema:    {Default: true, PreRelease: featuregate.Beta},
 		AutoGrowVolumes:         {Default: false, PreRelease: featuregate.Alpha},
 		BridgeIdentifiers:       {Default: false, PreRelease: featuregate.Deprecated},
+		DisasterRecovery:        {Default: false, PreRelease: featuregate.Alpha},
 		InstanceSidecars:        {Default: false, PreRelease: featuregate.Alpha},
 		PGBouncerSidecars:       {Default: false, PreRelease: featuregate.Alpha},
 		PGUpgradeCPUConcurrency: {Default: false, PreRelease: featuregate.Alpha},
@@ -20,6 +20,7 @@ func TestDefaults(t *testing.T) {
 	assert.Assert(t, true == gate.Enabled(AutoCreateUserSchema))
 	assert.Assert(t, false == gate.Enabled(AutoGrowVolumes))
 	assert.Assert(t, false == gate.Enabled(BridgeIdentifiers))
+	assert.Assert(t, false == gate.Enabled(DisasterRecovery))
 	assert.Assert(t, false == gate.Enabled(InstanceSidecars))
 	assert.Assert(t, false == gate.Enabled(PGBouncerSidecars))
 	assert.Assert(t, false == gate.Enabled(PGUpgradeCPUConcurrency))
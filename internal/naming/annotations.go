@@ -14,6 +14,33 @@ const (
 	// Patroni Switchover (or Failover).
 	PatroniSwitchover = annotationPrefix + "trigger-switchover"
 
+	// PatroniReinitialize is the annotation added to a PostgresCluster to initiate
+	// a manual Patroni reinitialize of a replica.
+	PatroniReinitialize = annotationPrefix + "trigger-reinitialize"
+
+	// PostgresParameters is the annotation added to instance Pods that holds the
+	// PostgreSQL parameters of the instance set. Patroni reads these when it
+	// starts, so a change to this value recreates the Pod.
+	PostgresParameters = annotationPrefix + "postgres-parameters"
+
+	// RecoveryMinApplyDelay is the annotation added to instance Pods that holds the
+	// number of seconds a delayed replica waits before replaying changes. Patroni
+	// reads this setting when it starts, so a change to this value recreates the Pod.
+	RecoveryMinApplyDelay = annotationPrefix + "recovery-min-apply-delay"
+
+	// PasswordRotatedAt is the annotation added to PostgreSQL user Secrets that
+	// holds the RFC3339 time at which the password was generated.
+	PasswordRotatedAt = annotationPrefix + "password-rotated-at"
+
+	// PasswordPreviousExpiresAt is the annotation added to PostgreSQL user Secrets
+	// that holds the RFC3339 time at which the previous password stops working.
+	PasswordPreviousExpiresAt = annotationPrefix + "password-previous-expires-at"
+
+	// PostgresUserReclaimPolicy is the annotation added to PostgreSQL user Secrets
+	// that holds the reclaim policy of the user. It is read after the user is
+	// removed from the PostgresCluster spec.
+	PostgresUserReclaimPolicy = annotationPrefix + "reclaim-policy"
+
 	// PGBackRestBackup is the annotation that is added to a PostgresCluster to initiate a manual
 	// backup.  The value of the annotation will be a unique identifier for a backup Job (e.g. a
 	// timestamp), which will be stored in the PostgresCluster status to properly track completion
@@ -16,6 +16,7 @@ func TestAnnotationsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(AutoCreateUserSchemaAnnotation))
 	assert.Assert(t, nil == validation.IsQualifiedName(CrunchyBridgeClusterAdoptionAnnotation))
 	assert.Assert(t, nil == validation.IsQualifiedName(Finalizer))
+	assert.Assert(t, nil == validation.IsQualifiedName(PatroniReinitialize))
 	assert.Assert(t, nil == validation.IsQualifiedName(PatroniSwitchover))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestBackup))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestBackupJobCompletion))
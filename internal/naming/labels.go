@@ -74,6 +74,10 @@ const (
 	// support discovery by Prometheus according to pgMonitor configuration
 	LabelPGMonitorDiscovery = labelPrefix + "crunchy-postgres-exporter"
 
+	// LabelDatabaseInitSQL allows the PostgresCluster named in its value to
+	// run the SQL in a Secret.
+	LabelDatabaseInitSQL = labelPrefix + "database-init-sql"
+
 	// LabelPostgresUser identifies the PostgreSQL user an object is for or about.
 	LabelPostgresUser = labelPrefix + "pguser"
 
@@ -403,6 +403,15 @@ func ReplicationClientCertSecret(cluster *v1beta1.PostgresCluster) metav1.Object
 	}
 }
 
+// PostgresHBASecret returns the ObjectMeta necessary to lookup the Secret
+// containing HBA records that cannot be stored in Patroni configuration.
+func PostgresHBASecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-pg-hba",
+	}
+}
+
 // PatroniDistributedConfiguration returns the ObjectMeta necessary to lookup
 // the DCS created by Patroni for cluster. This same name is used for both
 // ConfigMap and Endpoints. See Patroni DCS "config_path".
@@ -141,6 +141,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"DeprecatedPostgresUserSecret", DeprecatedPostgresUserSecret(cluster)},
 			{"PostgresTLSSecret", PostgresTLSSecret(cluster)},
 			{"ReplicationClientCertSecret", ReplicationClientCertSecret(cluster)},
+			{"PostgresHBASecret", PostgresHBASecret(cluster)},
 			{"PGBackRestSSHSecret", PGBackRestSSHSecret(cluster)},
 			{"MonitoringUserSecret", MonitoringUserSecret(cluster)},
 		})
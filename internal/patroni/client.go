@@ -0,0 +1,398 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package patroni
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// Client implements API by calling the Patroni REST API over HTTPS.
+// - https://patroni.readthedocs.io/en/latest/rest_api.html
+type Client struct {
+	// HTTP sends requests to Patroni. Its transport should verify servers
+	// and present a client certificate; see [NewClient].
+	HTTP *http.Client
+
+	// Member is the name of the Patroni member that answers requests about
+	// the entire cluster, e.g. "GET /cluster" and "POST /switchover".
+	Member string
+
+	// URL returns the base URL of the REST API of the named member.
+	URL func(member string) string
+}
+
+// Client implements API.
+var _ API = (*Client)(nil)
+
+// Error is returned when the Patroni REST API responds with an unexpected
+// HTTP status code.
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("patroni: %s %s: %d %s",
+		e.Method, e.URL, e.StatusCode, strings.TrimSpace(e.Message))
+}
+
+// ClusterState is the response of the "GET /cluster" REST endpoint.
+type ClusterState struct {
+	Members []MemberState `json:"members"`
+	Pause   bool          `json:"pause,omitempty"`
+}
+
+// MemberState describes one member in the response of the "GET /cluster"
+// REST endpoint.
+type MemberState struct {
+	Name           string          `json:"name"`
+	Role           string          `json:"role"`
+	State          string          `json:"state"`
+	Timeline       int64           `json:"timeline,omitempty"`
+	Lag            json.RawMessage `json:"lag,omitempty"`
+	PendingRestart bool            `json:"pending_restart,omitempty"`
+	Tags           map[string]any  `json:"tags,omitempty"`
+}
+
+// IsLeader returns true when m is the leader of its cluster, either a primary
+// or the leader of a standby cluster.
+func (m MemberState) IsLeader() bool {
+	switch m.Role {
+	case "leader", "master", "primary", "standby_leader":
+		return true
+	}
+	return false
+}
+
+// MemberStatus is the response of the "GET /patroni" REST endpoint.
+type MemberStatus struct {
+	State               string `json:"state"`
+	Role                string `json:"role"`
+	PostmasterStartTime string `json:"postmaster_start_time,omitempty"`
+	Timeline            int64  `json:"timeline,omitempty"`
+	PendingRestart      bool   `json:"pending_restart,omitempty"`
+
+	// ScheduledRestart is present while a restart of the member is requested.
+	ScheduledRestart *struct {
+		Schedule string `json:"schedule"`
+	} `json:"scheduled_restart,omitempty"`
+}
+
+// PostmasterStarted returns the time PostgreSQL started. Patroni formats it
+// the way Python formats a datetime with a time zone.
+func (m MemberStatus) PostmasterStarted() (time.Time, error) {
+	return time.Parse("2006-01-02 15:04:05.999999Z07:00", m.PostmasterStartTime)
+}
+
+// NewTransport returns an HTTP transport that verifies servers and presents a
+// client certificate using the contents of instanceCertificates, a Secret
+// populated by [InstanceCertificates].
+func NewTransport(instanceCertificates *corev1.Secret) (*http.Transport, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(instanceCertificates.Data[certAuthorityFileKey]) {
+		return nil, fmt.Errorf("patroni: no certificate authority in %q", instanceCertificates.Name)
+	}
+
+	// The private key and certificate are concatenated in a single value;
+	// the function below looks for the blocks it needs in each argument.
+	combined := instanceCertificates.Data[certServerFileKey]
+	certificate, err := tls.X509KeyPair(combined, combined)
+	if err != nil {
+		return nil, fmt.Errorf("patroni: %w", err)
+	}
+
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      roots,
+		},
+	}, nil
+}
+
+// Transports keeps one HTTP transport for each Secret of instance certificates
+// so that connections to Patroni are reused between calls. A transport is
+// replaced when its Secret changes. The zero value is ready to use.
+type Transports struct {
+	mutex sync.Mutex
+	cache map[types.UID]cachedTransport
+}
+
+type cachedTransport struct {
+	resourceVersion string
+	transport       *http.Transport
+}
+
+// Get returns the transport for instanceCertificates, calling [NewTransport]
+// when there is none or when the Secret has changed.
+func (t *Transports) Get(instanceCertificates *corev1.Secret) (*http.Transport, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	uid, version := instanceCertificates.UID, instanceCertificates.ResourceVersion
+	if cached, ok := t.cache[uid]; ok && uid != "" {
+		if cached.resourceVersion == version {
+			return cached.transport, nil
+		}
+		cached.transport.CloseIdleConnections()
+		delete(t.cache, uid)
+	}
+
+	transport, err := NewTransport(instanceCertificates)
+	if err == nil && uid != "" {
+		if t.cache == nil {
+			t.cache = make(map[types.UID]cachedTransport)
+		}
+		t.cache[uid] = cachedTransport{resourceVersion: version, transport: transport}
+	}
+	return transport, err
+}
+
+// NewClient returns a Client that calls the REST API of member in cluster
+// using transport; see [NewTransport].
+func NewClient(
+	cluster *v1beta1.PostgresCluster, member string, transport http.RoundTripper,
+) *Client {
+	// Patroni listens on the same port in every Pod. Pods have stable DNS
+	// names that are covered by their certificates.
+	// - https://docs.k8s.io/concepts/services-networking/dns-pod-service/#pods
+	port := strconv.Itoa(int(*cluster.Spec.Patroni.Port))
+	subdomain := naming.ClusterPodService(cluster).Name + "." + cluster.Namespace + ".svc"
+
+	return &Client{
+		HTTP: &http.Client{
+			Timeout:   time.Minute,
+			Transport: transport,
+		},
+		Member: member,
+		URL: func(member string) string {
+			return "https://" + net.JoinHostPort(member+"."+subdomain, port)
+		},
+	}
+}
+
+// do sends a request with a JSON body to the REST API of member and returns
+// the body of the response. It returns an [*Error] when the response has an
+// HTTP status code other than 200.
+func (c *Client) do(
+	ctx context.Context, method, member, path string, body any,
+) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	url := c.URL(member) + path
+	request, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	result, err := io.ReadAll(response.Body)
+	if err == nil && response.StatusCode != http.StatusOK {
+		err = &Error{
+			Method: method, URL: url,
+			StatusCode: response.StatusCode, Message: string(result),
+		}
+	}
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("called patroni",
+		"method", method, "url", url, "status", response.StatusCode,
+		"response", string(result),
+	)
+
+	return result, err
+}
+
+// GetCluster returns the state of every member in the Patroni cluster.
+// Similar to "patronictl list".
+func (c *Client) GetCluster(ctx context.Context) (*ClusterState, error) {
+	body, err := c.do(ctx, http.MethodGet, c.Member, "/cluster", nil)
+
+	var state ClusterState
+	if err == nil {
+		err = json.Unmarshal(body, &state)
+	}
+	return &state, err
+}
+
+// GetMember returns the state of the named member using its "GET /patroni"
+// REST endpoint.
+func (c *Client) GetMember(ctx context.Context, member string) (*MemberStatus, error) {
+	body, err := c.do(ctx, http.MethodGet, member, "/patroni", nil)
+
+	var status MemberStatus
+	if err == nil {
+		err = json.Unmarshal(body, &status)
+	}
+	return &status, err
+}
+
+// ChangePrimaryAndWait tries to demote the current Patroni leader using the
+// "POST /switchover" REST endpoint. It returns true when an election completes
+// successfully. When Patroni is paused, next cannot be blank.
+func (c *Client) ChangePrimaryAndWait(
+	ctx context.Context, current, next string,
+) (bool, error) {
+	request := map[string]string{"leader": current}
+	if next != "" {
+		request["candidate"] = next
+	}
+
+	// Patroni responds 200 only when the switchover completes. It responds
+	// 503 when the switchover fails and 412 when it cannot begin. When another
+	// member wins the election, it still responds 200 but without "Successfully".
+	// - https://github.com/zalando/patroni/blob/v3.3.0/patroni/api.py#L1213
+	body, err := c.do(ctx, http.MethodPost, c.Member, "/switchover", request)
+	return err == nil && bytes.HasPrefix(body, []byte("Successfully")), err
+}
+
+// SwitchoverAndWait tries to change the current Patroni leader to target using
+// the "POST /switchover" REST endpoint. It returns true when an election
+// completes successfully. When Patroni is paused, target cannot be blank.
+func (c *Client) SwitchoverAndWait(ctx context.Context, target string) (bool, error) {
+	state, err := c.GetCluster(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var leader string
+	for _, member := range state.Members {
+		if member.IsLeader() {
+			leader = member.Name
+		}
+	}
+	if leader == "" {
+		return false, errors.New("patroni: cluster has no leader")
+	}
+
+	return c.ChangePrimaryAndWait(ctx, leader, target)
+}
+
+// FailoverAndWait tries to change the current Patroni leader to target using
+// the "POST /failover" REST endpoint. It returns true when an election
+// completes successfully. Unlike switchover, this works when there is no
+// healthy leader.
+func (c *Client) FailoverAndWait(ctx context.Context, target string) (bool, error) {
+	// Patroni responds 200 only when the failover completes. The response
+	// begins with "Successfully" only when target became the leader.
+	body, err := c.do(ctx, http.MethodPost, c.Member, "/failover",
+		map[string]string{"candidate": target})
+	return err == nil && bytes.HasPrefix(body, []byte("Successfully")), err
+}
+
+// ReplaceConfiguration replaces Patroni's entire dynamic configuration using
+// the "PUT /config" REST endpoint.
+func (c *Client) ReplaceConfiguration(
+	ctx context.Context, configuration map[string]any,
+) error {
+	_, err := c.do(ctx, http.MethodPut, c.Member, "/config", configuration)
+	return err
+}
+
+// Reload tells the named member to read its configuration files again using
+// its "POST /reload" REST endpoint.
+func (c *Client) Reload(ctx context.Context, member string) error {
+	_, err := c.do(ctx, http.MethodPost, member, "/reload", nil)
+	return err
+}
+
+// Reinitialize tells the named member to remove its data directory and create
+// it again using its "POST /reinitialize" REST endpoint. Patroni responds
+// before this completes.
+func (c *Client) Reinitialize(ctx context.Context, member string) error {
+	_, err := c.do(ctx, http.MethodPost, member, "/reinitialize", map[string]any{})
+	return err
+}
+
+// Restart restarts PostgreSQL of the named member using its "POST /restart"
+// REST endpoint. Patroni responds after PostgreSQL has started again.
+func (c *Client) Restart(ctx context.Context, member string) error {
+	_, err := c.do(ctx, http.MethodPost, member, "/restart", map[string]any{})
+	return err
+}
+
+// RestartPendingMembers looks up Patroni members with role, either "primary"
+// or "replica", and restarts those that have a pending restart using the
+// "POST /restart" REST endpoint of each.
+func (c *Client) RestartPendingMembers(ctx context.Context, role string) error {
+	state, err := c.GetCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range state.Members {
+		if !member.PendingRestart || member.IsLeader() != (role == "primary") {
+			continue
+		}
+
+		// Patroni responds 503 when "restart conditions are not satisfied",
+		// which is normal and means the member has already restarted.
+		// - https://github.com/zalando/patroni/blob/v3.3.0/patroni/api.py#L1005
+		_, err = c.do(ctx, http.MethodPost, member.Name, "/restart",
+			map[string]bool{"restart_pending": true})
+
+		var response *Error
+		if errors.As(err, &response) && response.StatusCode == http.StatusServiceUnavailable {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTimeline returns the timeline of the running Patroni leader. It returns
+// zero when there is no running leader.
+func (c *Client) GetTimeline(ctx context.Context) (int64, error) {
+	state, err := c.GetCluster(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, member := range state.Members {
+		if member.IsLeader() && member.State == "running" {
+			return member.Timeline, nil
+		}
+	}
+	return 0, nil
+}
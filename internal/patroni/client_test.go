@@ -0,0 +1,419 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package patroni
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// testClient returns a Client that sends every request to handler.
+func testClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &Client{
+		HTTP:   server.Client(),
+		Member: "some-pod",
+		URL:    func(string) string { return server.URL },
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{Port: initialize.Int32(8008)}
+
+	t.Run("NoCertificates", func(t *testing.T) {
+		secret := new(corev1.Secret)
+		secret.Name = "some-certs"
+
+		_, err := NewTransport(secret)
+		assert.ErrorContains(t, err, `no certificate authority in "some-certs"`)
+	})
+
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	leaf, err := root.GenerateLeafCertificate("localhost", []string{"localhost"})
+	assert.NilError(t, err)
+
+	secret := new(corev1.Secret)
+	assert.NilError(t, InstanceCertificates(ctx,
+		root.Certificate, leaf.Certificate, leaf.PrivateKey, secret))
+
+	transport, err := NewTransport(secret)
+	assert.NilError(t, err)
+
+	client := NewClient(cluster, "some-pod", transport)
+	assert.Equal(t, client.Member, "some-pod")
+	assert.Equal(t, client.URL("other-pod"), "https://other-pod.hippo-pods.ns1.svc:8008")
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		serverCertificate, err := tls.X509KeyPair(
+			secret.Data[certServerFileKey], secret.Data[certServerFileKey])
+		assert.NilError(t, err)
+
+		roots := x509.NewCertPool()
+		assert.Assert(t, roots.AppendCertsFromPEM(secret.Data[certAuthorityFileKey]))
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Assert(t, len(r.TLS.VerifiedChains) > 0, "expected a client certificate")
+				_, _ = w.Write([]byte(`{"members":[{"name":"some-pod","role":"leader","state":"running","timeline":3}]}`))
+			}))
+		server.TLS = &tls.Config{
+			Certificates: []tls.Certificate{serverCertificate},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    roots,
+		}
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		// Connect using the DNS name in the server certificate.
+		address, err := url.Parse(server.URL)
+		assert.NilError(t, err)
+		_, port, _ := net.SplitHostPort(address.Host)
+		client.URL = func(string) string { return "https://" + net.JoinHostPort("localhost", port) }
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, timeline, int64(3))
+	})
+}
+
+func TestTransports(t *testing.T) {
+	ctx := context.Background()
+
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	leaf, err := root.GenerateLeafCertificate("localhost", []string{"localhost"})
+	assert.NilError(t, err)
+
+	secret := new(corev1.Secret)
+	secret.UID, secret.ResourceVersion = "some-uid", "1"
+	assert.NilError(t, InstanceCertificates(ctx,
+		root.Certificate, leaf.Certificate, leaf.PrivateKey, secret))
+
+	var transports Transports
+	first, err := transports.Get(secret)
+	assert.NilError(t, err)
+
+	second, err := transports.Get(secret)
+	assert.NilError(t, err)
+	assert.Assert(t, first == second, "expected the same transport")
+
+	secret.ResourceVersion = "2"
+	third, err := transports.Get(secret)
+	assert.NilError(t, err)
+	assert.Assert(t, first != third, "expected a new transport after the Secret changed")
+
+	secret.Data = nil
+	secret.ResourceVersion = "3"
+	_, err = transports.Get(secret)
+	assert.ErrorContains(t, err, "no certificate authority")
+}
+
+func TestClientChangePrimaryAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		called := false
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, r.Method, http.MethodPost)
+			assert.Equal(t, r.URL.Path, "/switchover")
+			assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+			assert.Equal(t, string(body), `{"candidate":"new","leader":"old"}`)
+		})
+
+		_, _ = client.ChangePrimaryAndWait(ctx, "old", "new")
+		assert.Assert(t, called)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte("candidate name does not match with sync_standby\n"))
+		})
+
+		success, err := client.ChangePrimaryAndWait(ctx, "any", "thing")
+		assert.Assert(t, !success)
+
+		var response *Error
+		assert.Assert(t, errors.As(err, &response))
+		assert.Equal(t, response.StatusCode, http.StatusPreconditionFailed)
+		assert.ErrorContains(t, err, "POST")
+		assert.ErrorContains(t, err, "/switchover: 412 candidate name does not match with sync_standby")
+	})
+
+	t.Run("Result", func(t *testing.T) {
+		status, message := 0, ""
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(message))
+		})
+
+		status, message = http.StatusServiceUnavailable, "Switchover failed"
+		success, err := client.ChangePrimaryAndWait(ctx, "any", "thing")
+		assert.ErrorContains(t, err, "503 Switchover failed")
+		assert.Assert(t, !success, "expected failure status to become false")
+
+		status, message = http.StatusOK, `Switched over to "other" instead of "thing"`
+		success, err = client.ChangePrimaryAndWait(ctx, "any", "thing")
+		assert.NilError(t, err)
+		assert.Assert(t, !success, "expected another leader to become false")
+
+		status, message = http.StatusOK, `Successfully switched over to "thing"`
+		success, err = client.ChangePrimaryAndWait(ctx, "any", "thing")
+		assert.NilError(t, err)
+		assert.Assert(t, success, "expected success status to become true")
+	})
+}
+
+func TestClientSwitchoverAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NoLeader", func(t *testing.T) {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Path, "/cluster")
+			_, _ = w.Write([]byte(`{"members":[{"name":"a","role":"replica","state":"streaming"}]}`))
+		})
+
+		success, err := client.SwitchoverAndWait(ctx, "a")
+		assert.Assert(t, !success)
+		assert.ErrorContains(t, err, "no leader")
+	})
+
+	t.Run("Leader", func(t *testing.T) {
+		var body string
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/cluster":
+				_, _ = w.Write([]byte(`{"members":[` +
+					`{"name":"a","role":"replica","state":"streaming"},` +
+					`{"name":"b","role":"leader","state":"running"}]}`))
+			case "/switchover":
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				_, _ = w.Write([]byte(`Successfully switched over to "a"`))
+			}
+		})
+
+		success, err := client.SwitchoverAndWait(ctx, "a")
+		assert.NilError(t, err)
+		assert.Assert(t, success)
+		assert.Equal(t, body, `{"candidate":"a","leader":"b"}`)
+	})
+}
+
+func TestClientFailoverAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	status, message := 0, ""
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.URL.Path, "/failover")
+		assert.Equal(t, string(body), `{"candidate":"new"}`)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(message))
+	})
+
+	status, message = http.StatusServiceUnavailable, "Failover status unknown"
+	success, err := client.FailoverAndWait(ctx, "new")
+	assert.ErrorContains(t, err, "503 Failover status unknown")
+	assert.Assert(t, !success)
+
+	status, message = http.StatusOK, `Failed over to "other" instead of "new"`
+	success, err = client.FailoverAndWait(ctx, "new")
+	assert.NilError(t, err)
+	assert.Assert(t, !success)
+
+	status, message = http.StatusOK, `Successfully failed over to "new"`
+	success, err = client.FailoverAndWait(ctx, "new")
+	assert.NilError(t, err)
+	assert.Assert(t, success)
+}
+
+func TestClientReplaceConfiguration(t *testing.T) {
+	called := false
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, r.Method, http.MethodPut)
+		assert.Equal(t, r.URL.Path, "/config")
+		assert.Equal(t, string(body), `{"some":"values"}`)
+	})
+
+	assert.NilError(t, client.ReplaceConfiguration(context.Background(),
+		map[string]any{"some": "values"}))
+	assert.Assert(t, called)
+}
+
+func TestClientReload(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.URL.Path, "/reload")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var response *Error
+	assert.Assert(t, errors.As(client.Reload(context.Background(), "a"), &response))
+	assert.Equal(t, response.StatusCode, http.StatusServiceUnavailable)
+}
+
+func TestClientGetMember(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodGet)
+		assert.Equal(t, r.URL.Path, "/patroni")
+		_, _ = w.Write([]byte(`{"state":"running","role":"replica",` +
+			`"postmaster_start_time":"2025-03-01 12:34:56.789012+00:00","timeline":2}`))
+	})
+
+	member, err := client.GetMember(context.Background(), "a")
+	assert.NilError(t, err)
+	assert.Equal(t, member.State, "running")
+	assert.Equal(t, member.Timeline, int64(2))
+
+	started, err := member.PostmasterStarted()
+	assert.NilError(t, err)
+	assert.Equal(t, started.UTC().Format(time.RFC3339Nano), "2025-03-01T12:34:56.789012Z")
+
+	_, err = MemberStatus{}.PostmasterStarted()
+	assert.Assert(t, err != nil, "expected an error when not started")
+}
+
+func TestClientReinitialize(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.URL.Path, "/reinitialize")
+		assert.Equal(t, string(body), `{}`)
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("I am the leader, can not reinitialize"))
+	})
+
+	err := client.Reinitialize(context.Background(), "a")
+	assert.ErrorContains(t, err, "503 I am the leader")
+}
+
+func TestClientRestart(t *testing.T) {
+	called := false
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.URL.Path, "/restart")
+		assert.Equal(t, string(body), `{}`)
+	})
+
+	assert.NilError(t, client.Restart(context.Background(), "a"))
+	assert.Assert(t, called)
+}
+
+func TestClientRestartPendingMembers(t *testing.T) {
+	ctx := context.Background()
+
+	var restarted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		member, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+		switch path {
+		case "cluster":
+			assert.Equal(t, member, "some-pod")
+			_, _ = w.Write([]byte(`{"members":[` +
+				`{"name":"a","role":"leader","state":"running","pending_restart":true},` +
+				`{"name":"b","role":"replica","state":"streaming","pending_restart":true},` +
+				`{"name":"c","role":"sync_standby","state":"streaming","pending_restart":true},` +
+				`{"name":"d","role":"replica","state":"streaming"}]}`))
+		case "restart":
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, r.Method, http.MethodPost)
+			assert.Equal(t, string(body), `{"restart_pending":true}`)
+			restarted = append(restarted, member)
+
+			// Patroni responds this way when the member has already restarted.
+			if member == "c" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("restart conditions are not satisfied"))
+			}
+			if member == "a" {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	// Send requests for each member to a different path.
+	client := &Client{
+		HTTP:   server.Client(),
+		Member: "some-pod",
+		URL:    func(member string) string { return server.URL + "/" + member },
+	}
+
+	assert.NilError(t, client.RestartPendingMembers(ctx, "replica"))
+	assert.DeepEqual(t, restarted, []string{"b", "c"})
+
+	restarted = nil
+	assert.ErrorContains(t, client.RestartPendingMembers(ctx, "primary"), "500")
+	assert.DeepEqual(t, restarted, []string{"a"})
+}
+
+func TestClientGetTimeline(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error", func(t *testing.T) {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.Equal(t, timeline, int64(0))
+		assert.ErrorContains(t, err, "500")
+	})
+
+	t.Run("NoLeader", func(t *testing.T) {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"members":[{"name":"a","role":"leader","state":"stopped","timeline":4}]}`))
+		})
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, timeline, int64(0))
+	})
+
+	t.Run("StandbyLeader", func(t *testing.T) {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"members":[` +
+				`{"name":"a","role":"replica","state":"streaming","timeline":4},` +
+				`{"name":"b","role":"standby_leader","state":"running","timeline":5}]}`))
+		})
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, timeline, int64(5))
+	})
+}
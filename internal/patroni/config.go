@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator/internal/config"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
@@ -50,8 +51,8 @@ func clusterYAML(
 		// lifetime.
 		"scope": naming.PatroniScope(cluster),
 
-		// Use Kubernetes Endpoints for the distributed configuration store (DCS).
-		// These values cannot change during the cluster's lifetime.
+		// Use Kubernetes Endpoints or ConfigMaps for the distributed configuration
+		// store (DCS). These values cannot change during the cluster's lifetime.
 		//
 		// NOTE(cbandy): It *might* be possible to *carefully* change the role and
 		// scope labels, but there is no way to reconfigure all instances at once.
@@ -59,7 +60,7 @@ func clusterYAML(
 			"namespace":     cluster.Namespace,
 			"role_label":    naming.LabelRole,
 			"scope_label":   naming.LabelPatroni,
-			"use_endpoints": true,
+			"use_endpoints": UseEndpoints(cluster),
 			// To support transitioning to Patroni v4, set the value to 'master'.
 			// In a future release, this can be removed in favor of the default.
 			"leader_label_value": naming.RolePatroniLeader,
@@ -211,6 +212,12 @@ func DynamicConfiguration(
 	root["ttl"] = *spec.Patroni.LeaderLeaseDurationSeconds
 	root["loop_wait"] = *spec.Patroni.SyncPeriodSeconds
 
+	// Enable or disable maintenance mode when the spec says so.
+	// - https://patroni.readthedocs.io/en/latest/pause.html
+	if spec.Patroni.Pause != nil {
+		root["pause"] = *spec.Patroni.Pause
+	}
+
 	postgresql := map[string]any{
 		// TODO(cbandy): explain this. requires an archive, perhaps.
 		"use_slots": false,
@@ -232,6 +239,30 @@ func DynamicConfiguration(
 	}
 	root["postgresql"] = postgresql
 
+	// Declare permanent replication slots. Patroni creates these on the
+	// leader and keeps them through failover. It copies logical slots to
+	// replicas but manages no slots at all unless "use_slots" is enabled, so
+	// enable it even when the spec disables it.
+	// - https://patroni.readthedocs.io/en/latest/dynamic_configuration.html
+	if len(spec.Patroni.LogicalSlots)+len(spec.Patroni.PhysicalSlots) > 0 {
+		slots, _ := root["slots"].(map[string]any)
+		if slots == nil {
+			slots = make(map[string]any)
+		}
+		for _, slot := range spec.Patroni.LogicalSlots {
+			slots[slot.Name] = map[string]any{
+				"type":     "logical",
+				"database": slot.Database,
+				"plugin":   slot.Plugin,
+			}
+		}
+		for _, slot := range spec.Patroni.PhysicalSlots {
+			slots[slot.Name] = map[string]any{"type": "physical"}
+		}
+		root["slots"] = slots
+		postgresql["use_slots"] = true
+	}
+
 	// Copy the "postgresql.parameters" section over any defaults.
 	parameters := make(map[string]any)
 	if pgParameters.Default != nil {
@@ -244,24 +275,21 @@ func DynamicConfiguration(
 			parameters[k] = v
 		}
 	}
+	// Override the above with parameters in the spec.
+	if pgParameters.Specified != nil {
+		for k, v := range pgParameters.Specified.AsMap() {
+			parameters[k] = v
+		}
+	}
 	// Override the above with mandatory parameters.
 	if pgParameters.Mandatory != nil {
 		for k, v := range pgParameters.Mandatory.AsMap() {
 
 			// This parameter is a comma-separated list. Rather than overwrite the
 			// user-defined value, we want to combine it with the mandatory one.
-			// Some libraries belong at specific positions in the list, so figure
-			// that out as well.
 			if k == "shared_preload_libraries" {
-				// Load mandatory libraries ahead of user-defined libraries.
-				if s, ok := parameters[k].(string); ok && len(s) > 0 {
-					v = v + "," + s
-				}
-				// Load "citus" ahead of any other libraries.
-				// - https://github.com/citusdata/citus/blob/v12.0.0/src/backend/distributed/shared_library_init.c#L417-L419
-				if strings.Contains(v, "citus") {
-					v = "citus," + v
-				}
+				s, _ := parameters[k].(string)
+				v = preloadLibraries(v, s)
 			}
 
 			parameters[k] = v
@@ -269,11 +297,25 @@ func DynamicConfiguration(
 	}
 	postgresql["parameters"] = parameters
 
-	// Copy the "postgresql.pg_hba" section after any mandatory values.
-	hba := make([]string, 0, len(pgHBAs.Mandatory))
+	// Copy the "postgresql.pg_hba" section after any mandatory, user, and
+	// specified values.
+	hba := make([]string, 0, len(pgHBAs.Mandatory)+len(pgHBAs.Users)+len(pgHBAs.Specified))
 	for i := range pgHBAs.Mandatory {
 		hba = append(hba, pgHBAs.Mandatory[i].String())
 	}
+	for i := range pgHBAs.Users {
+		hba = append(hba, pgHBAs.Users[i].String())
+	}
+	// Records that contain credentials are read from a file that PostgreSQL
+	// can include since v16.
+	// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	if len(pgHBAs.Confidential) > 0 {
+		hba = append(hba, "include_if_exists "+postgres.ConfidentialHBAsPath)
+	}
+	generated := len(hba)
+	for i := range pgHBAs.Specified {
+		hba = append(hba, pgHBAs.Specified[i].String())
+	}
 	if section, ok := postgresql["pg_hba"].([]any); ok {
 		for i := range section {
 			// any pg_hba values that are not strings will be skipped
@@ -282,14 +324,31 @@ func DynamicConfiguration(
 			}
 		}
 	}
-	// When the section is missing or empty, include the recommended defaults.
-	if len(hba) == len(pgHBAs.Mandatory) {
+	// When there are no other values, include the recommended defaults.
+	if len(hba) == generated {
 		for i := range pgHBAs.Default {
 			hba = append(hba, pgHBAs.Default[i].String())
 		}
 	}
 	postgresql["pg_hba"] = hba
 
+	// Copy the "postgresql.pg_ident" section after any user name maps.
+	ident := make([]string, 0, len(pgHBAs.Identities))
+	for i := range pgHBAs.Identities {
+		ident = append(ident, pgHBAs.Identities[i].String())
+	}
+	if section, ok := postgresql["pg_ident"].([]any); ok {
+		for i := range section {
+			// any pg_ident values that are not strings will be skipped
+			if value, ok := section[i].(string); ok {
+				ident = append(ident, value)
+			}
+		}
+	}
+	if len(ident) > 0 {
+		postgresql["pg_ident"] = ident
+	}
+
 	// Enabling `pg_rewind` allows a former primary to automatically rejoin the
 	// cluster even if it has commits that were not sent to a replica. In other
 	// words, this favors availability over consistency. Without it, the former
@@ -491,10 +550,48 @@ func instanceConfigFiles(cluster, instance *corev1.ConfigMap) []corev1.VolumePro
 	}
 }
 
+// preloadLibraries combines the mandatory and user-defined values of the
+// "shared_preload_libraries" parameter. Some libraries belong at specific
+// positions in the list, so it figures that out as well.
+func preloadLibraries(mandatory, specified string) string {
+	v := mandatory
+
+	// Load mandatory libraries ahead of user-defined libraries.
+	if len(specified) > 0 {
+		v = v + "," + specified
+	}
+	// Load "citus" ahead of any other libraries.
+	// - https://github.com/citusdata/citus/blob/v12.0.0/src/backend/distributed/shared_library_init.c#L417-L419
+	if strings.Contains(v, "citus") {
+		v = "citus," + v
+	}
+	return v
+}
+
+// instanceParameters returns the specified PostgreSQL parameters of an
+// instance in a form that Patroni accepts. Mandatory libraries are combined
+// with any specified "shared_preload_libraries".
+func instanceParameters(pgParameters postgres.Parameters) map[string]any {
+	if pgParameters.Specified == nil {
+		return nil
+	}
+
+	parameters := make(map[string]any)
+	for k, v := range pgParameters.Specified.AsMap() {
+		if k == "shared_preload_libraries" && pgParameters.Mandatory != nil {
+			if mandatory, ok := pgParameters.Mandatory.Get(k); ok {
+				v = preloadLibraries(mandatory, v)
+			}
+		}
+		parameters[k] = v
+	}
+	return parameters
+}
+
 // instanceYAML returns Patroni settings that apply to instance.
 func instanceYAML(
 	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
-	pgbackrestReplicaCreateCommand []string,
+	pgParameters postgres.Parameters, replicateFrom string, fenced bool, pgbackrestReplicaCreateCommand []string,
 ) (string, error) {
 	root := map[string]any{
 		// Missing here is "name" which cannot be known until the instance Pod is
@@ -519,10 +616,7 @@ func instanceYAML(
 			// See the PATRONI_RESTAPI_LISTEN environment variable.
 		},
 
-		"tags": map[string]any{
-			// TODO(cbandy): "nofailover"
-			// TODO(cbandy): "nosync"
-		},
+		"tags": instanceTags(instance, replicateFrom, fenced),
 	}
 
 	postgresql := map[string]any{
@@ -546,6 +640,23 @@ func instanceYAML(
 	}
 	root["postgresql"] = postgresql
 
+	// Parameters of the instance set take precedence over those in the
+	// dynamic configuration.
+	// - https://patroni.readthedocs.io/en/latest/patroni_configuration.html
+	if parameters := instanceParameters(pgParameters); len(parameters) > 0 {
+		postgresql["parameters"] = parameters
+	}
+
+	// Patroni ignores recovery parameters in "postgresql.parameters". Those
+	// that apply to only this instance belong in "postgresql.recovery_conf".
+	// PostgreSQL ignores them while it is not in recovery.
+	// - https://patroni.readthedocs.io/en/latest/yaml_configuration.html#postgresql
+	if seconds := initialize.FromPointer(instance.ApplyDelaySeconds); seconds > 0 {
+		postgresql["recovery_conf"] = map[string]any{
+			"recovery_min_apply_delay": fmt.Sprintf("%ds", seconds),
+		}
+	}
+
 	// The "basebackup" replica method is configured differently from others.
 	// Patroni prepends "--" before it calls `pg_basebackup`.
 	// - https://github.com/zalando/patroni/blob/v2.0.2/patroni/postgresql/bootstrap.py#L45
@@ -657,6 +768,56 @@ func instanceYAML(
 	return string(append([]byte(yamlGeneratedWarning), b...)), err
 }
 
+// instanceTags returns the Patroni tags that apply to every instance of
+// instance. Patroni reads these at startup and publishes them to the DCS.
+// When replicateFrom is not empty, it is the name of the Patroni member from
+// which these instances should stream changes. When fenced is true, these
+// instances are out of service.
+// - https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+func instanceTags(
+	instance *v1beta1.PostgresInstanceSetSpec, replicateFrom string, fenced bool,
+) map[string]any {
+	tags := map[string]any{}
+
+	// Patroni streams from the primary when this member is not running.
+	if replicateFrom != "" {
+		tags["replicatefrom"] = replicateFrom
+	}
+
+	if spec := instance.Patroni; spec != nil {
+		if spec.FailoverPriority != nil {
+			tags["failover_priority"] = *spec.FailoverPriority
+		}
+		if spec.NoFailover != nil {
+			tags["nofailover"] = *spec.NoFailover
+		}
+		if spec.NoLoadBalance != nil {
+			tags["noloadbalance"] = *spec.NoLoadBalance
+		}
+		if spec.NoSync != nil {
+			tags["nosync"] = *spec.NoSync
+		}
+	}
+
+	// A delayed replica is far behind the primary by design. Never promote it,
+	// and keep it out of read-only traffic, regardless of other settings.
+	if initialize.FromPointer(instance.ApplyDelaySeconds) > 0 {
+		delete(tags, "failover_priority")
+		tags["nofailover"] = true
+		tags["noloadbalance"] = true
+	}
+
+	// A fenced instance is suspect. Never promote it, and keep it out of
+	// read-only traffic, regardless of other settings.
+	if fenced {
+		delete(tags, "failover_priority")
+		tags["nofailover"] = true
+		tags["noloadbalance"] = true
+	}
+
+	return tags
+}
+
 // probeTiming returns a Probe with thresholds and timeouts set according to spec.
 func probeTiming(spec *v1beta1.PatroniSpec) *corev1.Probe {
 	// "Probes should be configured in such a way that they start failing about
@@ -17,6 +17,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
@@ -216,6 +217,16 @@ watchdog:
   mode: "off"
 	`)+"\n")
 	})
+
+	t.Run("ConfigMaps", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Default()
+		cluster.Spec.Patroni.DistributedConfigurationStore = v1beta1.PatroniDCSConfigMaps
+
+		data, err := clusterYAML(cluster, postgres.HBAs{}, postgres.Parameters{}, 0)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(data, "\n  use_endpoints: false\n"), "got:\n%s", data)
+	})
 }
 
 func TestDynamicConfiguration(t *testing.T) {
@@ -293,6 +304,111 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pause: spec overrides input",
+			spec: `{
+				patroni: {
+					pause: false,
+					dynamicConfiguration: {
+						pause: true,
+					},
+				},
+			}`,
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"pause":     false,
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "logical slots: spec overrides input",
+			spec: `{
+				patroni: {
+					logicalSlots: [
+						{ name: cdc, database: app, plugin: pgoutput },
+					],
+					dynamicConfiguration: {
+						slots: {
+							cdc: { type: physical },
+							other: { type: physical },
+						},
+					},
+				},
+			}`,
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"slots": map[string]any{
+					"cdc": map[string]any{
+						"type":     "logical",
+						"database": "app",
+						"plugin":   "pgoutput",
+					},
+					"other": map[string]any{"type": "physical"},
+				},
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     true,
+				},
+			},
+		},
+		{
+			name: "slots: spec overrides use_slots",
+			spec: `{
+				patroni: {
+					dynamicConfiguration: {
+						postgresql: { use_slots: false },
+					},
+					physicalSlots: [
+						{ name: standby },
+					],
+				},
+			}`,
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"slots": map[string]any{
+					"standby": map[string]any{"type": "physical"},
+				},
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     true,
+				},
+			},
+		},
+		{
+			name: "physical slots",
+			spec: `{
+				patroni: {
+					physicalSlots: [
+						{ name: standby, retainedWALThreshold: 1Gi },
+					],
+				},
+			}`,
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"slots": map[string]any{
+					"standby": map[string]any{"type": "physical"},
+				},
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     true,
+				},
+			},
+		},
 		{
 			name: "postgresql: wrong-type is ignored",
 			spec: `{
@@ -549,6 +665,49 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "postgresql.parameters: specified overrides input",
+			spec: `{
+				patroni: {
+					dynamicConfiguration: {
+						postgresql: {
+							parameters: {
+								something: str,
+								another: 5,
+								shared_preload_libraries: given,
+							},
+						},
+					},
+				},
+			}`,
+			params: postgres.Parameters{
+				Default: parameters(map[string]string{
+					"unrelated": "default",
+				}),
+				Specified: parameters(map[string]string{
+					"another":                  "10",
+					"shared_preload_libraries": "specified",
+				}),
+				Mandatory: parameters(map[string]string{
+					"shared_preload_libraries": "mandatory",
+				}),
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{
+						"something":                "str",
+						"another":                  "10",
+						"unrelated":                "default",
+						"shared_preload_libraries": "mandatory,specified",
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql.pg_hba: wrong-type is ignored",
 			spec: `{
@@ -689,6 +848,135 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "postgresql.pg_hba: specified after mandatory without default",
+			spec: `{
+				patroni: {
+					dynamicConfiguration: {
+						postgresql: {
+							pg_hba: [custom],
+						},
+					},
+				},
+			}`,
+			hbas: postgres.HBAs{
+				Mandatory: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().Local().Method("peer"),
+				},
+				Specified: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TLS().Method("scram-sha-256"),
+				},
+				Default: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TCP().Method("md5"),
+				},
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{},
+					"pg_hba": []string{
+						"local all all peer",
+						"hostssl all all all scram-sha-256",
+						"custom",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "postgresql.pg_hba: users after mandatory with default",
+			hbas: postgres.HBAs{
+				Mandatory: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().Local().Method("peer"),
+				},
+				Users: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TLS().User("alice").Method("cert"),
+				},
+				Default: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TCP().Method("md5"),
+				},
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{},
+					"pg_hba": []string{
+						"local all all peer",
+						`hostssl all "alice" all cert`,
+						"host all all all md5",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "postgresql.pg_hba: confidential included after users with default",
+			hbas: postgres.HBAs{
+				Mandatory: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().Local().Method("peer"),
+				},
+				Users: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TLS().User("alice").Method("cert"),
+				},
+				Confidential: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TLS().User("bob").Method("ldap").
+						Options(map[string]string{"ldapbindpasswd": "secret"}),
+				},
+				Default: []*postgres.HostBasedAuthentication{
+					postgres.NewHBA().TCP().Method("md5"),
+				},
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{},
+					"pg_hba": []string{
+						"local all all peer",
+						`hostssl all "alice" all cert`,
+						"include_if_exists /pgconf/tls/pg_hba_confidential.conf",
+						"host all all all md5",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "postgresql.pg_ident: identities before others",
+			spec: `{
+				patroni: {
+					dynamicConfiguration: {
+						postgresql: {
+							pg_ident: [1, custom],
+						},
+					},
+				},
+			}`,
+			hbas: postgres.HBAs{
+				Identities: []*postgres.IdentityMapping{
+					postgres.NewIdentityMapping("pgo-cert", "alice", "alice"),
+				},
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{},
+					"pg_hba":     []string{},
+					"pg_ident": []string{
+						`pgo-cert "alice" "alice"`,
+						"custom",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "standby_cluster: input passes through",
 			spec: `{
@@ -996,7 +1284,7 @@ func TestInstanceYAML(t *testing.T) {
 	cluster := &v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{PostgresVersion: 12}}
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 
-	data, err := instanceYAML(cluster, instance, nil)
+	data, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, nil)
 	assert.NilError(t, err)
 	assert.Equal(t, data, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -1019,7 +1307,7 @@ restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
 
-	dataWithReplicaCreate, err := instanceYAML(cluster, instance, []string{"some", "backrest", "cmd"})
+	dataWithReplicaCreate, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, []string{"some", "backrest", "cmd"})
 	assert.NilError(t, err)
 	assert.Equal(t, dataWithReplicaCreate, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -1059,7 +1347,7 @@ tags: {}
 		},
 	}
 
-	datawithTDE, err := instanceYAML(cluster, instance, nil)
+	datawithTDE, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, nil)
 	assert.NilError(t, err)
 	assert.Equal(t, datawithTDE, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -1083,6 +1371,78 @@ restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
 
+	t.Run("Tags", func(t *testing.T) {
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		instance.Patroni = &v1beta1.PatroniInstanceSetSpec{
+			FailoverPriority: initialize.Int32(0),
+			NoFailover:       initialize.Bool(true),
+			NoLoadBalance:    initialize.Bool(true),
+			NoSync:           initialize.Bool(false),
+		}
+
+		data, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasSuffix(data, `
+tags:
+  failover_priority: 0
+  nofailover: true
+  noloadbalance: true
+  nosync: false
+`), "got:\n%s", data)
+	})
+
+	t.Run("ReplicateFrom", func(t *testing.T) {
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+
+		data, err := instanceYAML(cluster, instance, postgres.Parameters{}, "some-member-0", false, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasSuffix(data, `
+tags:
+  replicatefrom: some-member-0
+`), "got:\n%s", data)
+	})
+
+	t.Run("ApplyDelay", func(t *testing.T) {
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		instance.ApplyDelaySeconds = initialize.Int32(3600)
+		instance.Patroni = &v1beta1.PatroniInstanceSetSpec{
+			FailoverPriority: initialize.Int32(5),
+			NoFailover:       initialize.Bool(false),
+		}
+
+		data, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.Contains(data, `
+  recovery_conf:
+    recovery_min_apply_delay: 3600s
+`))
+		assert.Assert(t, strings.HasSuffix(data, `
+tags:
+  nofailover: true
+  noloadbalance: true
+`), "got:\n%s", data)
+	})
+
+	t.Run("Parameters", func(t *testing.T) {
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		parameters := postgres.NewParameters()
+		parameters.Mandatory.Add("shared_preload_libraries", "pgaudit")
+		parameters.Specified = postgres.NewParameterSet()
+		parameters.Specified.Add("max_parallel_workers", "16")
+		parameters.Specified.Add("shared_preload_libraries", "pg_stat_statements")
+		parameters.Specified.Add("work_mem", "256MB")
+
+		data, err := instanceYAML(cluster, instance, parameters, "", false, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.Contains(data, `
+  parameters:
+    max_parallel_workers: "16"
+    shared_preload_libraries: pgaudit,pg_stat_statements
+    work_mem: 256MB
+`), "got:\n%s", data)
+		assert.Assert(t, !strings.Contains(data, "ssl"),
+			"expected only specified parameters, got:\n%s", data)
+	})
 }
 
 func TestPGBackRestCreateReplicaCommand(t *testing.T) {
@@ -1092,7 +1452,7 @@ func TestPGBackRestCreateReplicaCommand(t *testing.T) {
 	cluster := new(v1beta1.PostgresCluster)
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 
-	data, err := instanceYAML(cluster, instance, []string{"some", "backrest", "cmd"})
+	data, err := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, []string{"some", "backrest", "cmd"})
 	assert.NilError(t, err)
 
 	var parsed struct {
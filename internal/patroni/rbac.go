@@ -16,8 +16,6 @@ import (
 // +kubebuilder:rbac:groups="",resources="pods",verbs={list,watch}
 // +kubebuilder:rbac:groups="",resources="pods",verbs={patch}
 
-// TODO(cbandy): Separate these so that one can choose ConfigMap over Endpoints.
-
 // When using Endpoints for DCS, "create", "list", "patch", and "watch" are
 // required. Include "get" for good measure. The `patronictl scaffold` and
 // `patronictl remove` commands require "deletecollection".
@@ -32,24 +30,36 @@ import (
 // - https://github.com/openshift/origin/pull/9383
 // +kubebuilder:rbac:groups="",resources="endpoints/restricted",verbs={create}
 
+// When using ConfigMaps for DCS, the same verbs are required on ConfigMaps.
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={get}
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={create,deletecollection}
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={list,watch}
+// +kubebuilder:rbac:groups="",resources="configmaps",verbs={patch}
+
 // Permissions returns the RBAC rules Patroni needs for cluster.
 func Permissions(cluster *v1beta1.PostgresCluster) []rbacv1.PolicyRule {
-	// TODO(cbandy): This must change when using ConfigMaps for DCS.
-
 	rules := make([]rbacv1.PolicyRule, 0, 4)
 
-	rules = append(rules, rbacv1.PolicyRule{
-		APIGroups: []string{corev1.SchemeGroupVersion.Group},
-		Resources: []string{"endpoints"},
-		Verbs:     []string{"create", "deletecollection", "get", "list", "patch", "watch"},
-	})
-
-	if cluster.Spec.OpenShift != nil && *cluster.Spec.OpenShift {
+	if !UseEndpoints(cluster) {
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups: []string{corev1.SchemeGroupVersion.Group},
-			Resources: []string{"endpoints/restricted"},
-			Verbs:     []string{"create"},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"create", "deletecollection", "get", "list", "patch", "watch"},
+		})
+	} else {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{corev1.SchemeGroupVersion.Group},
+			Resources: []string{"endpoints"},
+			Verbs:     []string{"create", "deletecollection", "get", "list", "patch", "watch"},
 		})
+
+		if cluster.Spec.OpenShift != nil && *cluster.Spec.OpenShift {
+			rules = append(rules, rbacv1.PolicyRule{
+				APIGroups: []string{corev1.SchemeGroupVersion.Group},
+				Resources: []string{"endpoints/restricted"},
+				Verbs:     []string{"create"},
+			})
+		}
 	}
 
 	rules = append(rules, rbacv1.PolicyRule{
@@ -62,11 +72,13 @@ func Permissions(cluster *v1beta1.PostgresCluster) []rbacv1.PolicyRule {
 	// NOTE(cbandy): The PostgresCluster controller already creates this Service;
 	// it might be possible to eliminate this permission if it also created the
 	// Endpoints.
-	rules = append(rules, rbacv1.PolicyRule{
-		APIGroups: []string{corev1.SchemeGroupVersion.Group},
-		Resources: []string{"services"},
-		Verbs:     []string{"create"},
-	})
+	if UseEndpoints(cluster) {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{corev1.SchemeGroupVersion.Group},
+			Resources: []string{"services"},
+			Verbs:     []string{"create"},
+		})
+	}
 
 	return rules
 }
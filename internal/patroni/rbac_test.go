@@ -114,4 +114,38 @@ func TestPermissions(t *testing.T) {
   - create
 		`))
 	})
+	t.Run("ConfigMaps", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DistributedConfigurationStore = v1beta1.PatroniDCSConfigMaps
+
+		permissions := Permissions(cluster)
+		for _, rule := range permissions {
+			assert.Assert(t, isUniqueAndSorted(rule.APIGroups), "got %q", rule.APIGroups)
+			assert.Assert(t, isUniqueAndSorted(rule.Resources), "got %q", rule.Resources)
+			assert.Assert(t, isUniqueAndSorted(rule.Verbs), "got %q", rule.Verbs)
+		}
+
+		assert.Assert(t, cmp.MarshalMatches(permissions, `
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - create
+  - deletecollection
+  - get
+  - list
+  - patch
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+  - patch
+  - watch
+		`))
+	})
 }
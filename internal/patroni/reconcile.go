@@ -6,6 +6,10 @@ package patroni
 
 import (
 	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +30,13 @@ func ClusterBootstrapped(postgresCluster *v1beta1.PostgresCluster) bool {
 	return postgresCluster.Status.Patroni.SystemIdentifier != ""
 }
 
+// UseEndpoints returns whether or not Patroni of cluster keeps its distributed
+// configuration in Endpoints. When false, it uses ConfigMaps.
+func UseEndpoints(cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.Patroni == nil ||
+		cluster.Spec.Patroni.DistributedConfigurationStore != v1beta1.PatroniDCSConfigMaps
+}
+
 // ClusterConfigMap populates the shared ConfigMap with fields needed to run Patroni.
 func ClusterConfigMap(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
@@ -45,19 +56,29 @@ func ClusterConfigMap(ctx context.Context,
 }
 
 // InstanceConfigMap populates the shared ConfigMap with fields needed to run Patroni.
+// The specified parameters of inParameters are those of the instance set.
+// When inReplicateFrom is not empty, it is the name of the Patroni member from
+// which the instance should stream changes; Patroni must be reloaded to apply
+// a change to it. When inFenced is true, the instance
+// is out of service. Replicas are created as described by spec.standby only
+// when inStandby is true, regardless of spec.standby.enabled.
 func InstanceConfigMap(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
 	inInstanceSpec *v1beta1.PostgresInstanceSetSpec,
+	inParameters postgres.Parameters,
+	inReplicateFrom string,
+	inFenced bool,
+	inStandby bool,
 	outInstanceConfigMap *corev1.ConfigMap,
 ) error {
 	var err error
 
 	initialize.Map(&outInstanceConfigMap.Data)
 
-	command := pgbackrest.ReplicaCreateCommand(inCluster, inInstanceSpec)
+	command := pgbackrest.ReplicaCreateCommand(inCluster, inInstanceSpec, inStandby)
 
 	outInstanceConfigMap.Data[configMapFileKey], err = instanceYAML(
-		inCluster, inInstanceSpec, command)
+		inCluster, inInstanceSpec, inParameters, inReplicateFrom, inFenced, command)
 
 	return err
 }
@@ -80,13 +101,15 @@ func InstanceCertificates(ctx context.Context,
 }
 
 // InstancePod populates a PodTemplateSpec with the fields needed to run Patroni.
-// The database container must already be in the template.
+// The database container must already be in the template. See InstanceConfigMap
+// for inParameters.
 func InstancePod(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
 	inClusterConfigMap *corev1.ConfigMap,
 	inClusterPodService *corev1.Service,
 	inPatroniLeaderService *corev1.Service,
 	inInstanceSpec *v1beta1.PostgresInstanceSetSpec,
+	inParameters postgres.Parameters,
 	inInstanceCertificates *corev1.Secret,
 	inInstanceConfigMap *corev1.ConfigMap,
 	outInstancePod *corev1.PodTemplateSpec,
@@ -98,6 +121,20 @@ func InstancePod(ctx context.Context,
 	// "kubernetes.labels" settings.
 	outInstancePod.Labels[naming.LabelPatroni] = naming.PatroniScope(inCluster)
 
+	// Patroni reads its recovery settings from the instance configuration file
+	// when it starts. Copy them to the Pod template so that changing them
+	// recreates the Pod. Tags are left out; Patroni applies them when it
+	// reloads, so they change without a restart. See [PodHasTags].
+	if parameters := instanceParameters(inParameters); len(parameters) > 0 {
+		b, _ := json.Marshal(parameters)
+		initialize.Annotations(outInstancePod)
+		outInstancePod.Annotations[naming.PostgresParameters] = string(b)
+	}
+	if seconds := initialize.FromPointer(inInstanceSpec.ApplyDelaySeconds); seconds > 0 {
+		initialize.Annotations(outInstancePod)
+		outInstancePod.Annotations[naming.RecoveryMinApplyDelay] = strconv.Itoa(int(seconds))
+	}
+
 	var container *corev1.Container
 	for i := range outInstancePod.Spec.Containers {
 		if outInstancePod.Spec.Containers[i].Name == naming.ContainerDatabase {
@@ -171,6 +208,23 @@ func instanceProbes(cluster *v1beta1.PostgresCluster, container *corev1.Containe
 	}
 }
 
+// InstanceSetCanFailover returns whether or not Patroni may promote instances
+// of set during failover or switchover.
+func InstanceSetCanFailover(set *v1beta1.PostgresInstanceSetSpec) bool {
+	if initialize.FromPointer(set.ApplyDelaySeconds) > 0 {
+		return false
+	}
+	if spec := set.Patroni; spec != nil {
+		if initialize.FromPointer(spec.NoFailover) {
+			return false
+		}
+		if spec.FailoverPriority != nil && *spec.FailoverPriority == 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // PodIsPrimary returns whether or not pod is currently acting as the leader with
 // the "primary" role.
 func PodIsPrimary(pod metav1.Object) bool {
@@ -219,3 +273,74 @@ func PodRequiresRestart(pod metav1.Object) bool {
 	status := pod.GetAnnotations()["status"]
 	return strings.Contains(status, `"pending_restart":true`)
 }
+
+// PodPendingRestartParameters returns the names of PostgreSQL parameters that
+// Patroni reports as pending a restart of pod, sorted by name. Patroni 3.1
+// and later report these.
+func PodPendingRestartParameters(pod metav1.Object) []string {
+	if pod == nil {
+		return nil
+	}
+
+	// - https://github.com/patroni/patroni/blob/v3.1.0/patroni/ha.py
+	var status struct {
+		Reason map[string]json.RawMessage `json:"pending_restart_reason"`
+	}
+	if json.Unmarshal([]byte(pod.GetAnnotations()["status"]), &status) != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(status.Reason))
+	for name := range status.Reason {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// PodTags returns the tags that Patroni inside pod reports, if any.
+func PodTags(pod metav1.Object) map[string]any {
+	if pod == nil {
+		return nil
+	}
+
+	// TODO(cbandy): This works only when using Kubernetes for DCS.
+
+	// - https://github.com/patroni/patroni/blob/v3.3.0/patroni/ha.py
+	var status struct {
+		Tags map[string]any `json:"tags"`
+	}
+	_ = json.Unmarshal([]byte(pod.GetAnnotations()["status"]), &status)
+	return status.Tags
+}
+
+// PodHasTags returns whether or not Patroni inside pod reports the tags of an
+// instance of inInstanceSpec. It returns false for known when Patroni has not
+// reported its status. See InstanceConfigMap for inReplicateFrom and inFenced.
+func PodHasTags(
+	pod metav1.Object, inInstanceSpec *v1beta1.PostgresInstanceSetSpec,
+	inReplicateFrom string, inFenced bool,
+) (matches bool, known bool) {
+	if pod == nil || pod.GetAnnotations()["status"] == "" {
+		return false, false
+	}
+
+	// Patroni leaves out tags that are false, and numbers are read from JSON
+	// as floats. Compare the JSON of values that are not false.
+	// - https://github.com/patroni/patroni/blob/v3.3.0/patroni/tags.py
+	values := func(tags map[string]any) map[string]string {
+		result := make(map[string]string, len(tags))
+		for name, value := range tags {
+			if value != nil && value != false {
+				b, _ := json.Marshal(value)
+				result[name] = string(b)
+			}
+		}
+		return result
+	}
+
+	return maps.Equal(
+		values(instanceTags(inInstanceSpec, inReplicateFrom, inFenced)),
+		values(PodTags(pod)),
+	), true
+}
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
@@ -98,15 +99,15 @@ func TestInstanceConfigMap(t *testing.T) {
 	cluster := new(v1beta1.PostgresCluster)
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 	config := new(corev1.ConfigMap)
-	data, _ := instanceYAML(cluster, instance, nil)
+	data, _ := instanceYAML(cluster, instance, postgres.Parameters{}, "", false, nil)
 
-	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, config))
+	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, postgres.Parameters{}, "", false, false, config))
 
 	assert.DeepEqual(t, config.Data["patroni.yaml"], data)
 
 	// No change when called again.
 	before := config.DeepCopy()
-	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, config))
+	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, postgres.Parameters{}, "", false, false, config))
 	assert.DeepEqual(t, config, before)
 }
 
@@ -131,7 +132,7 @@ func TestInstancePod(t *testing.T) {
 	call := func() error {
 		return InstancePod(context.Background(),
 			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
-			instanceSpec, instanceCertificates, instanceConfigMap, template)
+			instanceSpec, postgres.Parameters{}, instanceCertificates, instanceConfigMap, template)
 	}
 
 	assert.NilError(t, call())
@@ -218,6 +219,78 @@ volumes:
         - key: patroni.crt-combined
           path: ~postgres-operator/patroni.crt+key
 	`))
+
+	t.Run("Tags", func(t *testing.T) {
+		instanceSpec := new(v1beta1.PostgresInstanceSetSpec)
+		instanceSpec.Patroni = &v1beta1.PatroniInstanceSetSpec{
+			NoFailover: initialize.Bool(true),
+		}
+		template := new(corev1.PodTemplateSpec)
+		template.Spec.Containers = []corev1.Container{{Name: "database"}}
+
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, postgres.Parameters{}, instanceCertificates, instanceConfigMap, template))
+
+		// Patroni applies tags when it reloads, so they are not in the template.
+		assert.Assert(t, template.Annotations == nil)
+	})
+
+	t.Run("ApplyDelay", func(t *testing.T) {
+		instanceSpec := new(v1beta1.PostgresInstanceSetSpec)
+		instanceSpec.ApplyDelaySeconds = initialize.Int32(300)
+		template := new(corev1.PodTemplateSpec)
+		template.Spec.Containers = []corev1.Container{{Name: "database"}}
+
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, postgres.Parameters{}, instanceCertificates, instanceConfigMap, template))
+
+		assert.DeepEqual(t, template.Annotations, map[string]string{
+			naming.RecoveryMinApplyDelay: "300",
+		})
+	})
+
+	t.Run("Parameters", func(t *testing.T) {
+		instanceSpec := new(v1beta1.PostgresInstanceSetSpec)
+		parameters := postgres.NewParameters()
+		parameters.Specified = postgres.NewParameterSet()
+		parameters.Specified.Add("work_mem", "256MB")
+		parameters.Specified.Add("max_parallel_workers", "16")
+		template := new(corev1.PodTemplateSpec)
+		template.Spec.Containers = []corev1.Container{{Name: "database"}}
+
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, parameters, instanceCertificates, instanceConfigMap, template))
+
+		assert.DeepEqual(t, template.Annotations, map[string]string{
+			naming.PostgresParameters: `{"max_parallel_workers":"16","work_mem":"256MB"}`,
+		})
+	})
+
+}
+
+func TestInstanceSetCanFailover(t *testing.T) {
+	set := new(v1beta1.PostgresInstanceSetSpec)
+	assert.Assert(t, InstanceSetCanFailover(set))
+
+	set.Patroni = new(v1beta1.PatroniInstanceSetSpec)
+	assert.Assert(t, InstanceSetCanFailover(set))
+
+	set.Patroni.FailoverPriority = initialize.Int32(2)
+	assert.Assert(t, InstanceSetCanFailover(set))
+
+	set.Patroni.NoFailover = initialize.Bool(true)
+	assert.Assert(t, !InstanceSetCanFailover(set))
+
+	set.Patroni.NoFailover = initialize.Bool(false)
+	set.Patroni.FailoverPriority = initialize.Int32(0)
+	assert.Assert(t, !InstanceSetCanFailover(set))
+
+	set.Patroni = nil
+	set.ApplyDelaySeconds = initialize.Int32(60)
+	assert.Assert(t, !InstanceSetCanFailover(set))
 }
 
 func TestPodIsPrimary(t *testing.T) {
@@ -290,3 +363,65 @@ func TestPodRequiresRestart(t *testing.T) {
 	pod.Annotations["status"] = `{"pending_restart":true}`
 	assert.Assert(t, PodRequiresRestart(pod))
 }
+
+func TestPodTags(t *testing.T) {
+	assert.Assert(t, PodTags(nil) == nil)
+
+	pod := &corev1.Pod{}
+	assert.Assert(t, PodTags(pod) == nil)
+
+	pod.Annotations = map[string]string{"status": `{"role":"replica"}`}
+	assert.Assert(t, PodTags(pod) == nil)
+
+	pod.Annotations["status"] = `{"role":"replica","tags":{"nofailover":true,"failover_priority":2}}`
+	assert.DeepEqual(t, PodTags(pod), map[string]any{"nofailover": true, "failover_priority": float64(2)})
+}
+
+func TestPodHasTags(t *testing.T) {
+	spec := new(v1beta1.PostgresInstanceSetSpec)
+	spec.Patroni = &v1beta1.PatroniInstanceSetSpec{
+		FailoverPriority: initialize.Int32(2),
+		NoSync:           initialize.Bool(false),
+	}
+
+	_, known := PodHasTags(nil, spec, "", false)
+	assert.Assert(t, !known)
+
+	// Patroni has not reported its status.
+	pod := &corev1.Pod{}
+	_, known = PodHasTags(pod, spec, "", false)
+	assert.Assert(t, !known)
+
+	// Tags that are false are not reported.
+	pod.Annotations = map[string]string{"status": `{"tags":{"failover_priority":2}}`}
+	matches, known := PodHasTags(pod, spec, "", false)
+	assert.Assert(t, known)
+	assert.Assert(t, matches)
+
+	matches, _ = PodHasTags(pod, spec, "other-0", false)
+	assert.Assert(t, !matches, "expected replicatefrom to differ")
+
+	matches, _ = PodHasTags(pod, spec, "", true)
+	assert.Assert(t, !matches, "expected fencing to differ")
+
+	pod.Annotations["status"] = `{"tags":{"nofailover":true,"noloadbalance":true,"replicatefrom":"other-0"}}`
+	matches, _ = PodHasTags(pod, spec, "other-0", true)
+	assert.Assert(t, matches)
+}
+
+func TestPodPendingRestartParameters(t *testing.T) {
+	assert.Assert(t, PodPendingRestartParameters(nil) == nil)
+
+	pod := &corev1.Pod{}
+	assert.Assert(t, PodPendingRestartParameters(pod) == nil)
+
+	// Older versions of Patroni do not report a reason.
+	pod.Annotations = map[string]string{"status": `{"pending_restart":true}`}
+	assert.DeepEqual(t, PodPendingRestartParameters(pod), []string{})
+
+	pod.Annotations["status"] = `{"pending_restart":true,"pending_restart_reason":{` +
+		`"shared_buffers":{"old_value":"128MB","new_value":"256MB"},` +
+		`"max_connections":{"old_value":"100","new_value":"200"}}}`
+	assert.DeepEqual(t, PodPendingRestartParameters(pod),
+		[]string{"max_connections", "shared_buffers"})
+}
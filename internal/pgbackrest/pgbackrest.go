@@ -7,8 +7,11 @@ package pgbackrest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -107,3 +110,53 @@ fi
 
 	return false, nil
 }
+
+// LatestArchivedWAL calls "pgbackrest info" and returns the name of the most
+// recent WAL file archived to repoName. It returns an empty string when there
+// are no WAL files in the repository.
+// - https://pgbackrest.org/command.html#command-info
+func (exec Executor) LatestArchivedWAL(ctx context.Context, repoName string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	err := exec(ctx, nil, &stdout, &stderr, "pgbackrest", "info", "--output=json",
+		"--stanza="+DefaultStanzaName, "--repo="+strings.TrimPrefix(repoName, "repo"))
+	if err != nil {
+		return "", errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+
+	var stanzas []struct {
+		Archive []struct {
+			Max string `json:"max"`
+		} `json:"archive"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &stanzas); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	var latest string
+	var latestTimeline, latestPosition uint64
+	for _, stanza := range stanzas {
+		for _, archive := range stanza.Archive {
+			timeline, position, ok := walFileNumbers(archive.Max)
+			if ok && (latest == "" || timeline > latestTimeline ||
+				(timeline == latestTimeline && position > latestPosition)) {
+				latest, latestTimeline, latestPosition = archive.Max, timeline, position
+			}
+		}
+	}
+	return latest, nil
+}
+
+// walFileNumbers returns the timeline and position of the WAL file named name.
+// The first eight hexadecimal digits are the timeline; the remaining sixteen
+// increase in the order WAL files are written. It returns false when name is
+// not the name of a WAL file.
+// - https://www.postgresql.org/docs/current/wal-internals.html
+func walFileNumbers(name string) (uint64, uint64, bool) {
+	if len(name) != 24 {
+		return 0, 0, false
+	}
+	timeline, err1 := strconv.ParseUint(name[:8], 16, 32)
+	position, err2 := strconv.ParseUint(name[8:], 16, 64)
+	return timeline, position, err1 == nil && err2 == nil
+}
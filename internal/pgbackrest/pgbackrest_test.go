@@ -6,6 +6,7 @@ package pgbackrest
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
@@ -98,3 +99,64 @@ fi
 	output, err := cmd.CombinedOutput()
 	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
 }
+
+func TestLatestArchivedWAL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Command", func(t *testing.T) {
+		var called bool
+		exec := func(_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string) error {
+			called = true
+			assert.Assert(t, stdin == nil)
+			assert.DeepEqual(t, command, []string{
+				"pgbackrest", "info", "--output=json", "--stanza=db", "--repo=2",
+			})
+			_, err := io.WriteString(stdout, `[{"name":"db","archive":[
+				{"id":"15-1","max":"000000010000000000000009","min":"000000010000000000000001"},
+				{"id":"16-2","max":"00000002000000000000000C","min":"00000002000000000000000A"}
+			]}]`)
+			return err
+		}
+
+		latest, err := Executor(exec).LatestArchivedWAL(ctx, "repo2")
+		assert.NilError(t, err)
+		assert.Assert(t, called)
+		assert.Equal(t, latest, "00000002000000000000000C")
+	})
+
+	t.Run("Timelines", func(t *testing.T) {
+		exec := func(_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string) error {
+			_, err := io.WriteString(stdout, `[{"name":"db","archive":[
+				{"id":"16-1","max":"0000000A0000000000000002","min":"000000090000000000000001"},
+				{"id":"16-1","max":"00000009000000010000000F","min":"000000090000000000000001"},
+				{"id":"16-1","max":"unexpected","min":"000000090000000000000001"}
+			]}]`)
+			return err
+		}
+
+		latest, err := Executor(exec).LatestArchivedWAL(ctx, "repo1")
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "0000000A0000000000000002")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string) error {
+			_, err := io.WriteString(stdout, `[{"name":"db","archive":[]}]`)
+			return err
+		}
+
+		latest, err := Executor(exec).LatestArchivedWAL(ctx, "repo1")
+		assert.NilError(t, err)
+		assert.Equal(t, latest, "")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		exec := func(_ context.Context, _ io.Reader, _, stderr io.Writer, _ ...string) error {
+			_, _ = io.WriteString(stderr, "stanza missing")
+			return errors.New("exit 1")
+		}
+
+		_, err := Executor(exec).LatestArchivedWAL(ctx, "repo1")
+		assert.ErrorContains(t, err, "exit 1: stanza missing")
+	})
+}
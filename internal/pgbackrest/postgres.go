@@ -12,10 +12,13 @@ import (
 )
 
 // PostgreSQL populates outParameters with any settings needed to run pgBackRest.
+// WAL is fetched as described by spec.standby only when inStandby is true,
+// regardless of spec.standby.enabled.
 func PostgreSQL(
 	inCluster *v1beta1.PostgresCluster,
 	outParameters *postgres.Parameters,
 	backupsEnabled bool,
+	inStandby bool,
 ) {
 	if outParameters.Mandatory == nil {
 		outParameters.Mandatory = postgres.NewParameterSet()
@@ -59,7 +62,7 @@ func PostgreSQL(
 	restore := `pgbackrest --stanza=` + DefaultStanzaName + ` archive-get %f "%p"`
 	outParameters.Mandatory.Add("restore_command", restore)
 
-	if inCluster.Spec.Standby != nil && inCluster.Spec.Standby.Enabled && inCluster.Spec.Standby.RepoName != "" {
+	if inStandby && inCluster.Spec.Standby != nil && inCluster.Spec.Standby.RepoName != "" {
 
 		// Fetch WAL files from the designated repository. The repository name
 		// is validated by the Kubernetes API, so it does not need to be quoted
@@ -17,7 +17,7 @@ func TestPostgreSQLParameters(t *testing.T) {
 	cluster := new(v1beta1.PostgresCluster)
 	parameters := new(postgres.Parameters)
 
-	PostgreSQL(cluster, parameters, true)
+	PostgreSQL(cluster, parameters, true, false)
 	assert.DeepEqual(t, parameters.Mandatory.AsMap(), map[string]string{
 		"archive_mode":    "on",
 		"archive_command": `pgbackrest --stanza=db archive-push "%p"`,
@@ -28,7 +28,7 @@ func TestPostgreSQLParameters(t *testing.T) {
 		"archive_timeout": "60s",
 	})
 
-	PostgreSQL(cluster, parameters, false)
+	PostgreSQL(cluster, parameters, false, false)
 	assert.DeepEqual(t, parameters.Mandatory.AsMap(), map[string]string{
 		"archive_mode":    "on",
 		"archive_command": "true",
@@ -40,10 +40,32 @@ func TestPostgreSQLParameters(t *testing.T) {
 		RepoName: "repo99",
 	}
 
-	PostgreSQL(cluster, parameters, true)
+	PostgreSQL(cluster, parameters, true, true)
 	assert.DeepEqual(t, parameters.Mandatory.AsMap(), map[string]string{
 		"archive_mode":    "on",
 		"archive_command": `pgbackrest --stanza=db archive-push "%p"`,
 		"restore_command": `pgbackrest --stanza=db archive-get %f "%p" --repo=99`,
 	})
+
+	t.Run("Held", func(t *testing.T) {
+		parameters := new(postgres.Parameters)
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Standby.Enabled = false
+
+		PostgreSQL(cluster, parameters, true, false)
+		assert.Equal(t, parameters.Mandatory.Value("restore_command"),
+			`pgbackrest --stanza=db archive-get %f "%p"`)
+
+		PostgreSQL(cluster, parameters, true, true)
+		assert.Equal(t, parameters.Mandatory.Value("restore_command"),
+			`pgbackrest --stanza=db archive-get %f "%p" --repo=99`)
+	})
+
+	t.Run("Promoted", func(t *testing.T) {
+		parameters := new(postgres.Parameters)
+
+		PostgreSQL(cluster, parameters, true, false)
+		assert.Equal(t, parameters.Mandatory.Value("restore_command"),
+			`pgbackrest --stanza=db archive-get %f "%p"`)
+	})
 }
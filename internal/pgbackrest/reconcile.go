@@ -420,9 +420,11 @@ func InstanceCertificates(ctx context.Context,
 
 // ReplicaCreateCommand returns the command that can initialize the PostgreSQL
 // data directory on an instance from one of cluster's repositories. It returns
-// nil when no repository is available.
+// nil when no repository is available. The repository in spec.standby is used
+// only when standby is true, regardless of spec.standby.enabled.
 func ReplicaCreateCommand(
 	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
+	standby bool,
 ) []string {
 	command := func(repoName string) []string {
 		return []string{
@@ -440,7 +442,7 @@ func ReplicaCreateCommand(
 		}
 	}
 
-	if cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled && cluster.Spec.Standby.RepoName != "" {
+	if standby && cluster.Spec.Standby != nil && cluster.Spec.Standby.RepoName != "" {
 		// Patroni initializes standby clusters using the same command it uses
 		// for any replica. Assume the repository in the spec has a stanza
 		// and can be used to restore. The repository name is validated by the
@@ -971,7 +971,7 @@ func TestReplicaCreateCommand(t *testing.T) {
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 
 	t.Run("NoRepositories", func(t *testing.T) {
-		assert.Equal(t, 0, len(ReplicaCreateCommand(cluster, instance)))
+		assert.Equal(t, 0, len(ReplicaCreateCommand(cluster, instance, false)))
 	})
 
 	t.Run("NoReadyRepositories", func(t *testing.T) {
@@ -981,7 +981,7 @@ func TestReplicaCreateCommand(t *testing.T) {
 			}},
 		}
 
-		assert.Equal(t, 0, len(ReplicaCreateCommand(cluster, instance)))
+		assert.Equal(t, 0, len(ReplicaCreateCommand(cluster, instance, false)))
 	})
 
 	t.Run("SomeReadyRepositories", func(t *testing.T) {
@@ -993,7 +993,7 @@ func TestReplicaCreateCommand(t *testing.T) {
 			}},
 		}
 
-		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance), []string{
+		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance, false), []string{
 			"pgbackrest", "restore", "--delta", "--stanza=db", "--repo=2",
 			"--link-map=pg_wal=/pgdata/pg0_wal", "--type=standby",
 		})
@@ -1006,7 +1006,30 @@ func TestReplicaCreateCommand(t *testing.T) {
 			RepoName: "repo7",
 		}
 
-		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance), []string{
+		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance, true), []string{
+			"pgbackrest", "restore", "--delta", "--stanza=db", "--repo=7",
+			"--link-map=pg_wal=/pgdata/pg0_wal", "--type=standby",
+		})
+
+		// A promoted standby restores from its own repositories.
+		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance, false), []string{
+			"pgbackrest", "restore", "--delta", "--stanza=db", "--repo=2",
+			"--link-map=pg_wal=/pgdata/pg0_wal", "--type=standby",
+		})
+	})
+
+	t.Run("Held", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{
+			Enabled:  false,
+			RepoName: "repo7",
+		}
+
+		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance, false), []string{
+			"pgbackrest", "restore", "--delta", "--stanza=db", "--repo=2",
+			"--link-map=pg_wal=/pgdata/pg0_wal", "--type=standby",
+		})
+		assert.DeepEqual(t, ReplicaCreateCommand(cluster, instance, true), []string{
 			"pgbackrest", "restore", "--delta", "--stanza=db", "--repo=7",
 			"--link-map=pg_wal=/pgdata/pg0_wal", "--type=standby",
 		})
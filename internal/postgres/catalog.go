@@ -0,0 +1,686 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// These are the kinds of value a parameter can have.
+// - https://www.postgresql.org/docs/current/view-pg-settings.html
+const (
+	kindBool    = "bool"
+	kindEnum    = "enum"
+	kindInteger = "integer"
+	kindReal    = "real"
+	kindString  = "string"
+)
+
+// These are the contexts in which a parameter can change. Parameters in the
+// "internal" context cannot change at all. Those in the "postmaster" context
+// take effect only when PostgreSQL starts.
+// - https://www.postgresql.org/docs/current/view-pg-settings.html
+const (
+	contextInternal         = "internal"
+	contextPostmaster       = "postmaster"
+	contextSighup           = "sighup"
+	contextSuperuserBackend = "superuser-backend"
+	contextBackend          = "backend"
+	contextSuperuser        = "superuser"
+	contextUser             = "user"
+)
+
+// parameterDefinition describes one parameter of PostgreSQL.
+type parameterDefinition struct {
+	// Kind is the kind of value the parameter has.
+	Kind string
+
+	// Context is when the parameter can change.
+	Context string
+
+	// Unit is the implicit unit of an integer or real value. It is one of
+	// "B", "kB", "8kB", "MB", "us", "ms", "s", or "min".
+	Unit string
+
+	// Limits are the minimum and maximum of a numeric value in its Unit.
+	Limits *[2]float64
+
+	// Values are the allowed values of an enumerated parameter. An empty
+	// list allows any value.
+	Values []string
+
+	// Since and Until are the first and last major versions that have the
+	// parameter. Zero means there is no limit.
+	Since, Until int
+}
+
+// maxInt is the largest value of most integer parameters.
+const maxInt = math.MaxInt32
+
+// Limits of numeric parameters.
+var (
+	nonNegative = &[2]float64{0, maxInt}
+	disableable = &[2]float64{-1, maxInt}
+	fraction    = &[2]float64{0, 1}
+	maxBackends = &[2]float64{0, 262143}
+)
+
+// parameterCatalog describes the parameters of PostgreSQL 11 and later. The
+// names of extension parameters contain a dot and are not in this catalog.
+// - https://www.postgresql.org/docs/current/runtime-config.html
+var parameterCatalog = map[string]parameterDefinition{
+	// File Locations
+	"config_file":       {Kind: kindString, Context: contextPostmaster},
+	"data_directory":    {Kind: kindString, Context: contextPostmaster},
+	"external_pid_file": {Kind: kindString, Context: contextPostmaster},
+	"hba_file":          {Kind: kindString, Context: contextPostmaster},
+	"ident_file":        {Kind: kindString, Context: contextPostmaster},
+
+	// Connections and Authentication
+	"authentication_timeout":                 {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{1, 600}},
+	"bonjour":                                {Kind: kindBool, Context: contextPostmaster},
+	"bonjour_name":                           {Kind: kindString, Context: contextPostmaster},
+	"client_connection_check_interval":       {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative, Since: 14},
+	"db_user_namespace":                      {Kind: kindBool, Context: contextSighup, Until: 16},
+	"gss_accept_delegation":                  {Kind: kindBool, Context: contextSighup, Since: 16},
+	"krb_caseins_users":                      {Kind: kindBool, Context: contextSighup},
+	"krb_server_keyfile":                     {Kind: kindString, Context: contextSighup},
+	"listen_addresses":                       {Kind: kindString, Context: contextPostmaster},
+	"max_connections":                        {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{1, 262143}},
+	"password_encryption":                    {Kind: kindEnum, Context: contextUser, Values: []string{"md5", "scram-sha-256"}},
+	"port":                                   {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{1, 65535}},
+	"reserved_connections":                   {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends, Since: 16},
+	"scram_iterations":                       {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, maxInt}, Since: 16},
+	"ssl":                                    {Kind: kindBool, Context: contextSighup},
+	"ssl_ca_file":                            {Kind: kindString, Context: contextSighup},
+	"ssl_cert_file":                          {Kind: kindString, Context: contextSighup},
+	"ssl_ciphers":                            {Kind: kindString, Context: contextSighup},
+	"ssl_crl_dir":                            {Kind: kindString, Context: contextSighup, Since: 14},
+	"ssl_crl_file":                           {Kind: kindString, Context: contextSighup},
+	"ssl_dh_params_file":                     {Kind: kindString, Context: contextSighup},
+	"ssl_ecdh_curve":                         {Kind: kindString, Context: contextSighup},
+	"ssl_key_file":                           {Kind: kindString, Context: contextSighup},
+	"ssl_max_protocol_version":               {Kind: kindEnum, Context: contextSighup, Values: []string{"", "TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}, Since: 12},
+	"ssl_min_protocol_version":               {Kind: kindEnum, Context: contextSighup, Values: []string{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}, Since: 12},
+	"ssl_passphrase_command":                 {Kind: kindString, Context: contextSighup},
+	"ssl_passphrase_command_supports_reload": {Kind: kindBool, Context: contextSighup},
+	"ssl_prefer_server_ciphers":              {Kind: kindBool, Context: contextSighup},
+	"superuser_reserved_connections":         {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"tcp_keepalives_count":                   {Kind: kindInteger, Context: contextUser, Limits: nonNegative},
+	"tcp_keepalives_idle":                    {Kind: kindInteger, Context: contextUser, Unit: "s", Limits: nonNegative},
+	"tcp_keepalives_interval":                {Kind: kindInteger, Context: contextUser, Unit: "s", Limits: nonNegative},
+	"tcp_user_timeout":                       {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative, Since: 12},
+	"unix_socket_directories":                {Kind: kindString, Context: contextPostmaster},
+	"unix_socket_group":                      {Kind: kindString, Context: contextPostmaster},
+	"unix_socket_permissions":                {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{0, 0777}},
+
+	// Resource Consumption
+	"autovacuum_work_mem":              {Kind: kindInteger, Context: contextSighup, Unit: "kB", Limits: disableable},
+	"backend_flush_after":              {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{0, 256}},
+	"bgwriter_delay":                   {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: &[2]float64{10, 10000}},
+	"bgwriter_flush_after":             {Kind: kindInteger, Context: contextSighup, Unit: "8kB", Limits: &[2]float64{0, 256}},
+	"bgwriter_lru_maxpages":            {Kind: kindInteger, Context: contextSighup, Limits: &[2]float64{0, 1073741823}},
+	"bgwriter_lru_multiplier":          {Kind: kindReal, Context: contextSighup, Limits: &[2]float64{0, 10}},
+	"commit_timestamp_buffers":         {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"dynamic_shared_memory_type":       {Kind: kindEnum, Context: contextPostmaster, Values: []string{"posix", "sysv", "windows", "mmap"}},
+	"effective_io_concurrency":         {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1000}},
+	"hash_mem_multiplier":              {Kind: kindReal, Context: contextUser, Limits: &[2]float64{1, 1000}, Since: 13},
+	"huge_page_size":                   {Kind: kindInteger, Context: contextPostmaster, Unit: "kB", Limits: nonNegative, Since: 14},
+	"huge_pages":                       {Kind: kindEnum, Context: contextPostmaster, Values: []string{"off", "on", "try"}},
+	"io_combine_limit":                 {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{1, 32}, Since: 17},
+	"logical_decoding_work_mem":        {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: &[2]float64{64, maxInt}, Since: 13},
+	"maintenance_io_concurrency":       {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1000}, Since: 13},
+	"maintenance_work_mem":             {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: &[2]float64{1024, maxInt}},
+	"max_files_per_process":            {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{64, maxInt}},
+	"max_notify_queue_pages":           {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{64, maxInt}, Since: 17},
+	"max_parallel_maintenance_workers": {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1024}},
+	"max_parallel_workers":             {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1024}},
+	"max_parallel_workers_per_gather":  {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1024}},
+	"max_prepared_transactions":        {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"max_stack_depth":                  {Kind: kindInteger, Context: contextSuperuser, Unit: "kB", Limits: &[2]float64{100, maxInt}},
+	"max_worker_processes":             {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"min_dynamic_shared_memory":        {Kind: kindInteger, Context: contextPostmaster, Unit: "MB", Limits: nonNegative, Since: 14},
+	"multixact_member_buffers":         {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"multixact_offset_buffers":         {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"notify_buffers":                   {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"old_snapshot_threshold":           {Kind: kindInteger, Context: contextPostmaster, Unit: "min", Limits: &[2]float64{-1, 86400}, Until: 16},
+	"parallel_leader_participation":    {Kind: kindBool, Context: contextUser},
+	"serializable_buffers":             {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"shared_buffers":                   {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Limits: &[2]float64{16, 1073741823}},
+	"shared_memory_type":               {Kind: kindEnum, Context: contextPostmaster, Values: []string{"mmap", "sysv", "windows"}, Since: 12},
+	"subtransaction_buffers":           {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"temp_buffers":                     {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{100, 1073741823}},
+	"temp_file_limit":                  {Kind: kindInteger, Context: contextSuperuser, Unit: "kB", Limits: disableable},
+	"transaction_buffers":              {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Since: 17},
+	"vacuum_buffer_usage_limit":        {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: &[2]float64{0, 16777216}, Since: 16},
+	"vacuum_cost_delay":                {Kind: kindReal, Context: contextUser, Unit: "ms", Limits: &[2]float64{0, 100}},
+	"vacuum_cost_limit":                {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, 10000}},
+	"vacuum_cost_page_dirty":           {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 10000}},
+	"vacuum_cost_page_hit":             {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 10000}},
+	"vacuum_cost_page_miss":            {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 10000}},
+	"work_mem":                         {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: &[2]float64{64, maxInt}},
+
+	// Write Ahead Log
+	"archive_cleanup_command":      {Kind: kindString, Context: contextSighup, Since: 12},
+	"archive_command":              {Kind: kindString, Context: contextSighup},
+	"archive_library":              {Kind: kindString, Context: contextSighup, Since: 15},
+	"archive_mode":                 {Kind: kindEnum, Context: contextPostmaster, Values: []string{"always", "on", "off"}},
+	"archive_timeout":              {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{0, 1073741823}},
+	"checkpoint_completion_target": {Kind: kindReal, Context: contextSighup, Limits: fraction},
+	"checkpoint_flush_after":       {Kind: kindInteger, Context: contextSighup, Unit: "8kB", Limits: &[2]float64{0, 256}},
+	"checkpoint_timeout":           {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{30, 86400}},
+	"checkpoint_warning":           {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: nonNegative},
+	"commit_delay":                 {Kind: kindInteger, Context: contextSuperuser, Limits: &[2]float64{0, 100000}},
+	"commit_siblings":              {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1000}},
+	"fsync":                        {Kind: kindBool, Context: contextSighup},
+	"full_page_writes":             {Kind: kindBool, Context: contextSighup},
+	"max_wal_size":                 {Kind: kindInteger, Context: contextSighup, Unit: "MB", Limits: &[2]float64{2, maxInt}},
+	"min_wal_size":                 {Kind: kindInteger, Context: contextSighup, Unit: "MB", Limits: &[2]float64{2, maxInt}},
+	"recovery_end_command":         {Kind: kindString, Context: contextSighup, Since: 12},
+	"recovery_prefetch":            {Kind: kindEnum, Context: contextSighup, Values: []string{"off", "on", "try"}, Since: 15},
+	"recovery_target":              {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"recovery_target_action":       {Kind: kindEnum, Context: contextPostmaster, Values: []string{"pause", "promote", "shutdown"}, Since: 12},
+	"recovery_target_inclusive":    {Kind: kindBool, Context: contextPostmaster, Since: 12},
+	"recovery_target_lsn":          {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"recovery_target_name":         {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"recovery_target_time":         {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"recovery_target_timeline":     {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"recovery_target_xid":          {Kind: kindString, Context: contextPostmaster, Since: 12},
+	"restore_command":              {Kind: kindString, Context: contextSighup, Since: 12},
+	"summarize_wal":                {Kind: kindBool, Context: contextSighup, Since: 17},
+	"synchronous_commit":           {Kind: kindEnum, Context: contextUser, Values: []string{"local", "remote_write", "remote_apply", "on", "off"}},
+	"wal_buffers":                  {Kind: kindInteger, Context: contextPostmaster, Unit: "8kB", Limits: &[2]float64{-1, 262143}},
+	"wal_compression":              {Kind: kindString, Context: contextSuperuser},
+	"wal_decode_buffer_size":       {Kind: kindInteger, Context: contextPostmaster, Unit: "B", Since: 15},
+	"wal_init_zero":                {Kind: kindBool, Context: contextSuperuser, Since: 12},
+	"wal_level":                    {Kind: kindEnum, Context: contextPostmaster, Values: []string{"minimal", "replica", "logical"}},
+	"wal_log_hints":                {Kind: kindBool, Context: contextPostmaster},
+	"wal_recycle":                  {Kind: kindBool, Context: contextSuperuser, Since: 12},
+	"wal_skip_threshold":           {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: nonNegative, Since: 13},
+	"wal_summary_keep_time":        {Kind: kindInteger, Context: contextSighup, Unit: "min", Limits: &[2]float64{0, 35791394}, Since: 17},
+	"wal_sync_method":              {Kind: kindEnum, Context: contextSighup, Values: []string{"fsync", "fdatasync", "open_sync", "open_datasync", "fsync_writethrough"}},
+	"wal_writer_delay":             {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: &[2]float64{1, 10000}},
+	"wal_writer_flush_after":       {Kind: kindInteger, Context: contextSighup, Unit: "8kB", Limits: nonNegative},
+
+	// Replication
+	"hot_standby":                                 {Kind: kindBool, Context: contextPostmaster},
+	"hot_standby_feedback":                        {Kind: kindBool, Context: contextSighup},
+	"max_logical_replication_workers":             {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"max_parallel_apply_workers_per_subscription": {Kind: kindInteger, Context: contextSighup, Limits: maxBackends, Since: 16},
+	"max_replication_slots":                       {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"max_slot_wal_keep_size":                      {Kind: kindInteger, Context: contextSighup, Unit: "MB", Limits: disableable, Since: 13},
+	"max_standby_archive_delay":                   {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: disableable},
+	"max_standby_streaming_delay":                 {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: disableable},
+	"max_sync_workers_per_subscription":           {Kind: kindInteger, Context: contextSighup, Limits: maxBackends},
+	"max_wal_senders":                             {Kind: kindInteger, Context: contextPostmaster, Limits: maxBackends},
+	"primary_conninfo":                            {Kind: kindString, Context: contextSighup, Since: 12},
+	"primary_slot_name":                           {Kind: kindString, Context: contextSighup, Since: 12},
+	"promote_trigger_file":                        {Kind: kindString, Context: contextSighup, Since: 12, Until: 15},
+	"recovery_min_apply_delay":                    {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: nonNegative, Since: 12},
+	"sync_replication_slots":                      {Kind: kindBool, Context: contextSighup, Since: 17},
+	"synchronized_standby_slots":                  {Kind: kindString, Context: contextSighup, Since: 17},
+	"synchronous_standby_names":                   {Kind: kindString, Context: contextSighup},
+	"track_commit_timestamp":                      {Kind: kindBool, Context: contextPostmaster},
+	"vacuum_defer_cleanup_age":                    {Kind: kindInteger, Context: contextSighup, Limits: &[2]float64{0, 1000000}, Until: 15},
+	"wal_keep_segments":                           {Kind: kindInteger, Context: contextSighup, Limits: nonNegative, Until: 12},
+	"wal_keep_size":                               {Kind: kindInteger, Context: contextSighup, Unit: "MB", Limits: nonNegative, Since: 13},
+	"wal_receiver_create_temp_slot":               {Kind: kindBool, Context: contextSighup, Since: 13},
+	"wal_receiver_status_interval":                {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{0, 2147483}},
+	"wal_receiver_timeout":                        {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: nonNegative},
+	"wal_retrieve_retry_interval":                 {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: &[2]float64{1, maxInt}},
+	"wal_sender_timeout":                          {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative},
+
+	// Query Planning
+	"constraint_exclusion":              {Kind: kindEnum, Context: contextUser, Values: []string{"partition", "on", "off"}},
+	"cpu_index_tuple_cost":              {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"cpu_operator_cost":                 {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"cpu_tuple_cost":                    {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"cursor_tuple_fraction":             {Kind: kindReal, Context: contextUser, Limits: fraction},
+	"default_statistics_target":         {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, 10000}},
+	"effective_cache_size":              {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{1, maxInt}},
+	"enable_async_append":               {Kind: kindBool, Context: contextUser, Since: 14},
+	"enable_bitmapscan":                 {Kind: kindBool, Context: contextUser},
+	"enable_gathermerge":                {Kind: kindBool, Context: contextUser},
+	"enable_group_by_reordering":        {Kind: kindBool, Context: contextUser, Since: 17},
+	"enable_hashagg":                    {Kind: kindBool, Context: contextUser},
+	"enable_hashjoin":                   {Kind: kindBool, Context: contextUser},
+	"enable_incremental_sort":           {Kind: kindBool, Context: contextUser, Since: 13},
+	"enable_indexonlyscan":              {Kind: kindBool, Context: contextUser},
+	"enable_indexscan":                  {Kind: kindBool, Context: contextUser},
+	"enable_material":                   {Kind: kindBool, Context: contextUser},
+	"enable_memoize":                    {Kind: kindBool, Context: contextUser, Since: 14},
+	"enable_mergejoin":                  {Kind: kindBool, Context: contextUser},
+	"enable_nestloop":                   {Kind: kindBool, Context: contextUser},
+	"enable_parallel_append":            {Kind: kindBool, Context: contextUser},
+	"enable_parallel_hash":              {Kind: kindBool, Context: contextUser},
+	"enable_partition_pruning":          {Kind: kindBool, Context: contextUser},
+	"enable_partitionwise_aggregate":    {Kind: kindBool, Context: contextUser},
+	"enable_partitionwise_join":         {Kind: kindBool, Context: contextUser},
+	"enable_presorted_aggregate":        {Kind: kindBool, Context: contextUser, Since: 16},
+	"enable_seqscan":                    {Kind: kindBool, Context: contextUser},
+	"enable_sort":                       {Kind: kindBool, Context: contextUser},
+	"enable_tidscan":                    {Kind: kindBool, Context: contextUser},
+	"from_collapse_limit":               {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, maxInt}},
+	"geqo":                              {Kind: kindBool, Context: contextUser},
+	"geqo_effort":                       {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, 10}},
+	"geqo_generations":                  {Kind: kindInteger, Context: contextUser, Limits: nonNegative},
+	"geqo_pool_size":                    {Kind: kindInteger, Context: contextUser, Limits: nonNegative},
+	"geqo_seed":                         {Kind: kindReal, Context: contextUser, Limits: fraction},
+	"geqo_selection_bias":               {Kind: kindReal, Context: contextUser, Limits: &[2]float64{1.5, 2}},
+	"geqo_threshold":                    {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{2, maxInt}},
+	"jit":                               {Kind: kindBool, Context: contextUser},
+	"jit_above_cost":                    {Kind: kindReal, Context: contextUser, Limits: &[2]float64{-1, math.MaxFloat64}},
+	"jit_inline_above_cost":             {Kind: kindReal, Context: contextUser, Limits: &[2]float64{-1, math.MaxFloat64}},
+	"jit_optimize_above_cost":           {Kind: kindReal, Context: contextUser, Limits: &[2]float64{-1, math.MaxFloat64}},
+	"join_collapse_limit":               {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{1, maxInt}},
+	"min_parallel_index_scan_size":      {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{0, 715827882}},
+	"min_parallel_table_scan_size":      {Kind: kindInteger, Context: contextUser, Unit: "8kB", Limits: &[2]float64{0, 715827882}},
+	"parallel_setup_cost":               {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"parallel_tuple_cost":               {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"plan_cache_mode":                   {Kind: kindEnum, Context: contextUser, Values: []string{"auto", "force_generic_plan", "force_custom_plan"}, Since: 12},
+	"random_page_cost":                  {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"recursive_worktable_factor":        {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0.001, 1000000}, Since: 15},
+	"seq_page_cost":                     {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, math.MaxFloat64}},
+	"vacuum_cleanup_index_scale_factor": {Kind: kindReal, Context: contextUser, Limits: &[2]float64{0, 1e10}, Until: 13},
+
+	// Reporting and Logging
+	"application_name":                  {Kind: kindString, Context: contextUser},
+	"client_min_messages":               {Kind: kindEnum, Context: contextUser, Values: []string{"debug5", "debug4", "debug3", "debug2", "debug1", "debug", "log", "notice", "warning", "error"}},
+	"cluster_name":                      {Kind: kindString, Context: contextPostmaster},
+	"debug_pretty_print":                {Kind: kindBool, Context: contextUser},
+	"debug_print_parse":                 {Kind: kindBool, Context: contextUser},
+	"debug_print_plan":                  {Kind: kindBool, Context: contextUser},
+	"debug_print_rewritten":             {Kind: kindBool, Context: contextUser},
+	"event_source":                      {Kind: kindString, Context: contextPostmaster},
+	"log_autovacuum_min_duration":       {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: disableable},
+	"log_checkpoints":                   {Kind: kindBool, Context: contextSighup},
+	"log_connections":                   {Kind: kindBool, Context: contextSuperuserBackend},
+	"log_destination":                   {Kind: kindString, Context: contextSighup},
+	"log_directory":                     {Kind: kindString, Context: contextSighup},
+	"log_disconnections":                {Kind: kindBool, Context: contextSuperuserBackend},
+	"log_duration":                      {Kind: kindBool, Context: contextSuperuser},
+	"log_error_verbosity":               {Kind: kindEnum, Context: contextSuperuser, Values: []string{"terse", "default", "verbose"}},
+	"log_executor_stats":                {Kind: kindBool, Context: contextSuperuser},
+	"log_file_mode":                     {Kind: kindInteger, Context: contextSighup, Limits: &[2]float64{0, 0777}},
+	"log_filename":                      {Kind: kindString, Context: contextSighup},
+	"log_hostname":                      {Kind: kindBool, Context: contextSighup},
+	"log_line_prefix":                   {Kind: kindString, Context: contextSighup},
+	"log_lock_waits":                    {Kind: kindBool, Context: contextSuperuser},
+	"log_min_duration_sample":           {Kind: kindInteger, Context: contextSuperuser, Unit: "ms", Limits: disableable, Since: 13},
+	"log_min_duration_statement":        {Kind: kindInteger, Context: contextSuperuser, Unit: "ms", Limits: disableable},
+	"log_min_error_statement":           {Kind: kindEnum, Context: contextSuperuser, Values: []string{"debug5", "debug4", "debug3", "debug2", "debug1", "debug", "info", "notice", "warning", "error", "log", "fatal", "panic"}},
+	"log_min_messages":                  {Kind: kindEnum, Context: contextSuperuser, Values: []string{"debug5", "debug4", "debug3", "debug2", "debug1", "debug", "info", "notice", "warning", "error", "log", "fatal", "panic"}},
+	"log_parameter_max_length":          {Kind: kindInteger, Context: contextSuperuser, Unit: "B", Limits: &[2]float64{-1, 1073741823}, Since: 13},
+	"log_parameter_max_length_on_error": {Kind: kindInteger, Context: contextUser, Unit: "B", Limits: &[2]float64{-1, 1073741823}, Since: 13},
+	"log_parser_stats":                  {Kind: kindBool, Context: contextSuperuser},
+	"log_planner_stats":                 {Kind: kindBool, Context: contextSuperuser},
+	"log_recovery_conflict_waits":       {Kind: kindBool, Context: contextSighup, Since: 14},
+	"log_replication_commands":          {Kind: kindBool, Context: contextSuperuser},
+	"log_rotation_age":                  {Kind: kindInteger, Context: contextSighup, Unit: "min", Limits: &[2]float64{0, 35791394}},
+	"log_rotation_size":                 {Kind: kindInteger, Context: contextSighup, Unit: "kB", Limits: &[2]float64{0, 2097151}},
+	"log_startup_progress_interval":     {Kind: kindInteger, Context: contextSighup, Unit: "ms", Limits: nonNegative, Since: 15},
+	"log_statement":                     {Kind: kindEnum, Context: contextSuperuser, Values: []string{"none", "ddl", "mod", "all"}},
+	"log_statement_sample_rate":         {Kind: kindReal, Context: contextSuperuser, Limits: fraction, Since: 13},
+	"log_statement_stats":               {Kind: kindBool, Context: contextSuperuser},
+	"log_temp_files":                    {Kind: kindInteger, Context: contextSuperuser, Unit: "kB", Limits: disableable},
+	"log_timezone":                      {Kind: kindString, Context: contextSighup},
+	"log_transaction_sample_rate":       {Kind: kindReal, Context: contextSuperuser, Limits: fraction, Since: 12},
+	"log_truncate_on_rotation":          {Kind: kindBool, Context: contextSighup},
+	"logging_collector":                 {Kind: kindBool, Context: contextPostmaster},
+	"syslog_facility":                   {Kind: kindEnum, Context: contextSighup, Values: []string{"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7"}},
+	"syslog_ident":                      {Kind: kindString, Context: contextSighup},
+	"syslog_sequence_numbers":           {Kind: kindBool, Context: contextSighup},
+	"syslog_split_messages":             {Kind: kindBool, Context: contextSighup},
+	"update_process_title":              {Kind: kindBool, Context: contextSuperuser},
+
+	// Run-time Statistics
+	"compute_query_id":          {Kind: kindEnum, Context: contextSuperuser, Values: []string{"auto", "regress", "on", "off"}, Since: 14},
+	"stats_fetch_consistency":   {Kind: kindEnum, Context: contextUser, Values: []string{"none", "cache", "snapshot"}, Since: 15},
+	"stats_temp_directory":      {Kind: kindString, Context: contextSighup, Until: 14},
+	"track_activities":          {Kind: kindBool, Context: contextSuperuser},
+	"track_activity_query_size": {Kind: kindInteger, Context: contextPostmaster, Unit: "B", Limits: &[2]float64{100, 1048576}},
+	"track_counts":              {Kind: kindBool, Context: contextSuperuser},
+	"track_functions":           {Kind: kindEnum, Context: contextSuperuser, Values: []string{"none", "pl", "all"}},
+	"track_io_timing":           {Kind: kindBool, Context: contextSuperuser},
+	"track_wal_io_timing":       {Kind: kindBool, Context: contextSuperuser, Since: 14},
+
+	// Automatic Vacuuming
+	"autovacuum":                            {Kind: kindBool, Context: contextSighup},
+	"autovacuum_analyze_scale_factor":       {Kind: kindReal, Context: contextSighup, Limits: &[2]float64{0, 100}},
+	"autovacuum_analyze_threshold":          {Kind: kindInteger, Context: contextSighup, Limits: nonNegative},
+	"autovacuum_freeze_max_age":             {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{100000, 2000000000}},
+	"autovacuum_max_workers":                {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{1, 262143}},
+	"autovacuum_multixact_freeze_max_age":   {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{10000, 2000000000}},
+	"autovacuum_naptime":                    {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{1, 2147483}},
+	"autovacuum_vacuum_cost_delay":          {Kind: kindReal, Context: contextSighup, Unit: "ms", Limits: &[2]float64{-1, 100}},
+	"autovacuum_vacuum_cost_limit":          {Kind: kindInteger, Context: contextSighup, Limits: &[2]float64{-1, 10000}},
+	"autovacuum_vacuum_insert_scale_factor": {Kind: kindReal, Context: contextSighup, Limits: &[2]float64{0, 100}, Since: 13},
+	"autovacuum_vacuum_insert_threshold":    {Kind: kindInteger, Context: contextSighup, Limits: disableable, Since: 13},
+	"autovacuum_vacuum_scale_factor":        {Kind: kindReal, Context: contextSighup, Limits: &[2]float64{0, 100}},
+	"autovacuum_vacuum_threshold":           {Kind: kindInteger, Context: contextSighup, Limits: nonNegative},
+
+	// Client Connection Defaults
+	"bytea_output":                        {Kind: kindEnum, Context: contextUser, Values: []string{"escape", "hex"}},
+	"check_function_bodies":               {Kind: kindBool, Context: contextUser},
+	"client_encoding":                     {Kind: kindString, Context: contextUser},
+	"createrole_self_grant":               {Kind: kindString, Context: contextUser, Since: 16},
+	"datestyle":                           {Kind: kindString, Context: contextUser},
+	"default_table_access_method":         {Kind: kindString, Context: contextUser, Since: 12},
+	"default_tablespace":                  {Kind: kindString, Context: contextUser},
+	"default_text_search_config":          {Kind: kindString, Context: contextUser},
+	"default_toast_compression":           {Kind: kindEnum, Context: contextUser, Values: []string{"pglz", "lz4"}, Since: 14},
+	"default_transaction_deferrable":      {Kind: kindBool, Context: contextUser},
+	"default_transaction_isolation":       {Kind: kindEnum, Context: contextUser, Values: []string{"serializable", "repeatable read", "read committed", "read uncommitted"}},
+	"default_transaction_read_only":       {Kind: kindBool, Context: contextUser},
+	"dynamic_library_path":                {Kind: kindString, Context: contextSuperuser},
+	"event_triggers":                      {Kind: kindBool, Context: contextSuperuser, Since: 17},
+	"extra_float_digits":                  {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{-15, 3}},
+	"gin_fuzzy_search_limit":              {Kind: kindInteger, Context: contextUser, Limits: nonNegative},
+	"gin_pending_list_limit":              {Kind: kindInteger, Context: contextUser, Unit: "kB", Limits: &[2]float64{64, maxInt}},
+	"icu_validation_level":                {Kind: kindEnum, Context: contextUser, Values: []string{"disabled", "debug5", "debug4", "debug3", "debug2", "debug1", "log", "notice", "warning", "error"}, Since: 16},
+	"idle_in_transaction_session_timeout": {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative},
+	"idle_session_timeout":                {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative, Since: 14},
+	"intervalstyle":                       {Kind: kindEnum, Context: contextUser, Values: []string{"postgres", "postgres_verbose", "sql_standard", "iso_8601"}},
+	"jit_provider":                        {Kind: kindString, Context: contextPostmaster},
+	"lc_messages":                         {Kind: kindString, Context: contextSuperuser},
+	"lc_monetary":                         {Kind: kindString, Context: contextUser},
+	"lc_numeric":                          {Kind: kindString, Context: contextUser},
+	"lc_time":                             {Kind: kindString, Context: contextUser},
+	"local_preload_libraries":             {Kind: kindString, Context: contextUser},
+	"lock_timeout":                        {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative},
+	"restrict_nonsystem_relation_kind":    {Kind: kindString, Context: contextUser, Since: 12},
+	"row_security":                        {Kind: kindBool, Context: contextUser},
+	"search_path":                         {Kind: kindString, Context: contextUser},
+	"session_preload_libraries":           {Kind: kindString, Context: contextSuperuser},
+	"session_replication_role":            {Kind: kindEnum, Context: contextSuperuser, Values: []string{"origin", "replica", "local"}},
+	"shared_preload_libraries":            {Kind: kindString, Context: contextPostmaster},
+	"statement_timeout":                   {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative},
+	"temp_tablespaces":                    {Kind: kindString, Context: contextUser},
+	"timezone":                            {Kind: kindString, Context: contextUser},
+	"timezone_abbreviations":              {Kind: kindString, Context: contextUser},
+	"transaction_timeout":                 {Kind: kindInteger, Context: contextUser, Unit: "ms", Limits: nonNegative, Since: 17},
+	"vacuum_failsafe_age":                 {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 2100000000}, Since: 14},
+	"vacuum_freeze_min_age":               {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1000000000}},
+	"vacuum_freeze_table_age":             {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 2000000000}},
+	"vacuum_multixact_failsafe_age":       {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 2100000000}, Since: 14},
+	"vacuum_multixact_freeze_min_age":     {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 1000000000}},
+	"vacuum_multixact_freeze_table_age":   {Kind: kindInteger, Context: contextUser, Limits: &[2]float64{0, 2000000000}},
+	"xmlbinary":                           {Kind: kindEnum, Context: contextUser, Values: []string{"base64", "hex"}},
+	"xmloption":                           {Kind: kindEnum, Context: contextUser, Values: []string{"content", "document"}},
+
+	// Lock Management
+	"deadlock_timeout":               {Kind: kindInteger, Context: contextSuperuser, Unit: "ms", Limits: &[2]float64{1, maxInt}},
+	"max_locks_per_transaction":      {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{10, maxInt}},
+	"max_pred_locks_per_page":        {Kind: kindInteger, Context: contextSighup, Limits: nonNegative},
+	"max_pred_locks_per_relation":    {Kind: kindInteger, Context: contextSighup, Limits: &[2]float64{math.MinInt32, maxInt}},
+	"max_pred_locks_per_transaction": {Kind: kindInteger, Context: contextPostmaster, Limits: &[2]float64{10, maxInt}},
+
+	// Version and Platform Compatibility
+	"allow_alter_system":          {Kind: kindBool, Context: contextSighup, Since: 17},
+	"array_nulls":                 {Kind: kindBool, Context: contextUser},
+	"backslash_quote":             {Kind: kindEnum, Context: contextUser, Values: []string{"safe_encoding", "on", "off"}},
+	"default_with_oids":           {Kind: kindBool, Context: contextUser, Until: 11},
+	"escape_string_warning":       {Kind: kindBool, Context: contextUser},
+	"lo_compat_privileges":        {Kind: kindBool, Context: contextSuperuser},
+	"operator_precedence_warning": {Kind: kindBool, Context: contextUser, Until: 13},
+	"quote_all_identifiers":       {Kind: kindBool, Context: contextUser},
+	"standard_conforming_strings": {Kind: kindBool, Context: contextUser},
+	"synchronize_seqscans":        {Kind: kindBool, Context: contextUser},
+	"transform_null_equals":       {Kind: kindBool, Context: contextUser},
+
+	// Error Handling
+	"data_sync_retry":               {Kind: kindBool, Context: contextPostmaster},
+	"exit_on_error":                 {Kind: kindBool, Context: contextUser},
+	"recovery_init_sync_method":     {Kind: kindEnum, Context: contextSighup, Values: []string{"fsync", "syncfs"}, Since: 14},
+	"remove_temp_files_after_crash": {Kind: kindBool, Context: contextSighup, Since: 14},
+	"restart_after_crash":           {Kind: kindBool, Context: contextSighup},
+
+	// Preset Options
+	"block_size":                       {Kind: kindInteger, Context: contextInternal},
+	"data_checksums":                   {Kind: kindBool, Context: contextInternal},
+	"data_directory_mode":              {Kind: kindInteger, Context: contextInternal},
+	"debug_assertions":                 {Kind: kindBool, Context: contextInternal},
+	"huge_pages_status":                {Kind: kindEnum, Context: contextInternal, Since: 17},
+	"in_hot_standby":                   {Kind: kindBool, Context: contextInternal, Since: 14},
+	"integer_datetimes":                {Kind: kindBool, Context: contextInternal},
+	"lc_collate":                       {Kind: kindString, Context: contextInternal, Until: 15},
+	"lc_ctype":                         {Kind: kindString, Context: contextInternal, Until: 15},
+	"max_function_args":                {Kind: kindInteger, Context: contextInternal},
+	"max_identifier_length":            {Kind: kindInteger, Context: contextInternal},
+	"max_index_keys":                   {Kind: kindInteger, Context: contextInternal},
+	"segment_size":                     {Kind: kindInteger, Context: contextInternal},
+	"server_encoding":                  {Kind: kindString, Context: contextInternal},
+	"server_version":                   {Kind: kindString, Context: contextInternal},
+	"server_version_num":               {Kind: kindInteger, Context: contextInternal},
+	"shared_memory_size":               {Kind: kindInteger, Context: contextInternal, Since: 15},
+	"shared_memory_size_in_huge_pages": {Kind: kindInteger, Context: contextInternal, Since: 15},
+	"ssl_library":                      {Kind: kindString, Context: contextInternal, Since: 12},
+	"wal_block_size":                   {Kind: kindInteger, Context: contextInternal},
+	"wal_segment_size":                 {Kind: kindInteger, Context: contextInternal},
+
+	// Developer Options
+	"allow_in_place_tablespaces": {Kind: kindBool, Context: contextSuperuser, Since: 15},
+	"allow_system_table_mods":    {Kind: kindBool, Context: contextSuperuser},
+	"backtrace_functions":        {Kind: kindString, Context: contextSuperuser, Since: 13},
+	"debug_discard_caches":       {Kind: kindInteger, Context: contextSuperuser, Since: 14},
+	"debug_io_direct":            {Kind: kindString, Context: contextPostmaster, Since: 16},
+	"debug_parallel_query":       {Kind: kindEnum, Context: contextUser, Values: []string{"off", "on", "regress"}, Since: 16},
+	"force_parallel_mode":        {Kind: kindEnum, Context: contextUser, Values: []string{"off", "on", "regress"}, Until: 15},
+	"ignore_checksum_failure":    {Kind: kindBool, Context: contextSuperuser},
+	"ignore_invalid_pages":       {Kind: kindBool, Context: contextPostmaster, Since: 13},
+	"ignore_system_indexes":      {Kind: kindBool, Context: contextBackend},
+	"jit_debugging_support":      {Kind: kindBool, Context: contextSuperuserBackend},
+	"jit_dump_bitcode":           {Kind: kindBool, Context: contextSuperuser},
+	"jit_expressions":            {Kind: kindBool, Context: contextUser},
+	"jit_profiling_support":      {Kind: kindBool, Context: contextSuperuserBackend},
+	"jit_tuple_deforming":        {Kind: kindBool, Context: contextUser},
+	"post_auth_delay":            {Kind: kindInteger, Context: contextBackend, Unit: "s", Limits: &[2]float64{0, 2147}},
+	"pre_auth_delay":             {Kind: kindInteger, Context: contextSighup, Unit: "s", Limits: &[2]float64{0, 60}},
+	"send_abort_for_crash":       {Kind: kindBool, Context: contextSighup, Since: 16},
+	"send_abort_for_kill":        {Kind: kindBool, Context: contextSighup, Since: 16},
+	"trace_notify":               {Kind: kindBool, Context: contextUser},
+	"trace_recovery_messages":    {Kind: kindEnum, Context: contextSighup, Until: 16},
+	"trace_sort":                 {Kind: kindBool, Context: contextUser},
+	"wal_consistency_checking":   {Kind: kindString, Context: contextSuperuser},
+	"zero_damaged_pages":         {Kind: kindBool, Context: contextSuperuser},
+}
+
+// lookupParameter returns the definition of parameter name in PostgreSQL
+// version and whether or not that version has it.
+func lookupParameter(version int, name string) (parameterDefinition, bool) {
+	definition, ok := parameterCatalog[name]
+	ok = ok &&
+		(definition.Since == 0 || version >= definition.Since) &&
+		(definition.Until == 0 || version <= definition.Until)
+
+	// PostgreSQL 14 stopped accepting boolean values of "password_encryption".
+	// - https://www.postgresql.org/docs/release/14.0/
+	if ok && name == "password_encryption" && version < 14 {
+		definition.Values = append(slices.Clip(definition.Values), "on", "off")
+	}
+	return definition, ok
+}
+
+// allows returns whether or not value is one of the enumerated values of d.
+// Like PostgreSQL, the comparison is case-insensitive.
+func (d parameterDefinition) allows(value string) bool {
+	return len(d.Values) == 0 || slices.ContainsFunc(d.Values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// memoryUnits and timeUnits are the units PostgreSQL understands in numeric
+// values, as multiples of bytes and microseconds respectively.
+// - https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
+var (
+	memoryUnits = map[string]float64{"B": 1, "kB": 1 << 10, "8kB": 8 << 10, "MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40}
+	timeUnits   = map[string]float64{"us": 1, "ms": 1e3, "s": 1e6, "min": 60e6, "h": 3600e6, "d": 86400e6}
+)
+
+// parseNumber interprets value as a number in the Unit of d. Like PostgreSQL,
+// a value can have a unit of the same kind as that of d.
+func (d parameterDefinition) parseNumber(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	number := strings.TrimRightFunc(value, unicode.IsLetter)
+
+	// A hexadecimal integer continues through every hexadecimal digit.
+	if digits := strings.TrimLeft(value, "+-"); d.Kind == kindInteger &&
+		(strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X")) {
+		end := len(value) - len(digits) + 2
+		for end < len(value) && strings.ContainsRune("0123456789abcdefABCDEF", rune(value[end])) {
+			end++
+		}
+		number = value[:end]
+	}
+
+	unit := strings.TrimSpace(value[len(number):])
+	number = strings.TrimSpace(number)
+
+	// PostgreSQL reads integers the way strtol does, so they can be octal or
+	// hexadecimal. Other numbers are read the way strtod does.
+	// - https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
+	var result float64
+	var err error
+	if integer, ok := parseInteger(number); ok && d.Kind == kindInteger {
+		result = float64(integer)
+	} else {
+		result, err = strconv.ParseFloat(number, 64)
+	}
+	if err != nil || math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if unit == "" {
+		return result, nil
+	}
+
+	for _, units := range []map[string]float64{memoryUnits, timeUnits} {
+		if from, ok := units[unit]; ok {
+			if to, ok := units[d.Unit]; ok {
+				return result * from / to, nil
+			}
+		}
+	}
+	if d.Unit == "" {
+		return 0, fmt.Errorf("%q cannot have a unit", value)
+	}
+	return 0, fmt.Errorf("%q has an invalid unit; use a multiple of %q", value, d.Unit)
+}
+
+// parseInteger interprets number as a decimal, octal, or hexadecimal integer
+// like strtol does with base zero.
+func parseInteger(number string) (int64, bool) {
+	digits := strings.ToLower(strings.TrimLeft(number, "+-"))
+
+	// Go accepts underscores and some prefixes that strtol does not.
+	if strings.Contains(digits, "_") ||
+		strings.HasPrefix(digits, "0b") || strings.HasPrefix(digits, "0o") {
+		return 0, false
+	}
+
+	integer, err := strconv.ParseInt(number, 0, 64)
+	return integer, err == nil
+}
+
+// validate returns an error when value is not valid for d.
+func (d parameterDefinition) validate(value string) error {
+	switch d.Kind {
+	case kindBool:
+		// PostgreSQL accepts these and any unambiguous prefix of them.
+		// - https://www.postgresql.org/docs/current/config-setting.html
+		lower := strings.ToLower(strings.TrimSpace(value))
+		if lower == "on" || lower == "off" || lower == "of" || lower == "1" || lower == "0" ||
+			(lower != "" && slices.ContainsFunc([]string{"true", "false", "yes", "no"},
+				func(v string) bool { return strings.HasPrefix(v, lower) })) {
+			return nil
+		}
+		return fmt.Errorf("%q is not a boolean", value)
+
+	case kindEnum:
+		if !d.allows(strings.TrimSpace(value)) {
+			return fmt.Errorf("%q is not one of %q", value, d.Values)
+		}
+
+	case kindInteger, kindReal:
+		number, err := d.parseNumber(value)
+		if err == nil && d.Kind == kindInteger {
+			number = math.Round(number)
+		}
+		if err == nil && d.Limits != nil && (number < d.Limits[0] || number > d.Limits[1]) {
+			err = fmt.Errorf("%q is outside the range %s to %s",
+				value, strconv.FormatFloat(d.Limits[0], 'f', -1, 64),
+				strings.TrimSpace(strconv.FormatFloat(d.Limits[1], 'f', -1, 64)+" "+d.Unit))
+		}
+		return err
+	}
+	return nil
+}
+
+// patroniParameters are managed by Patroni, which ignores any other value.
+// - https://patroni.readthedocs.io/en/latest/patroni_configuration.html
+var patroniParameters = []string{
+	"cluster_name", "hot_standby", "listen_addresses", "port",
+	"primary_conninfo", "primary_slot_name", "promote_trigger_file",
+	"recovery_end_command", "recovery_min_apply_delay", "recovery_target",
+	"recovery_target_action", "recovery_target_inclusive", "recovery_target_lsn",
+	"recovery_target_name", "recovery_target_time", "recovery_target_timeline",
+	"recovery_target_xid", "restore_command", "archive_cleanup_command",
+}
+
+// SpecifiedParameters checks parameters against those known to PostgreSQL
+// version and returns the ones that can be applied. Each that cannot is
+// described in the returned list, sorted by name. Parameters in mandatory
+// belong to the operator and cannot be changed, with the exception of
+// "shared_preload_libraries" which is combined with the mandatory value.
+func SpecifiedParameters(
+	version int, parameters map[string]intstr.IntOrString, mandatory *ParameterSet,
+) (*ParameterSet, []string) {
+	result := NewParameterSet()
+	rejected := []string{}
+
+	for name, value := range parameters {
+		name := result.normalize(name)
+		definition, known := lookupParameter(version, name)
+		var reason string
+
+		switch {
+		case strings.Contains(name, "."):
+			// Extensions define parameters with a dot in their name.
+			// PostgreSQL accepts any such parameter, so do the same.
+
+		case !known:
+			reason = fmt.Sprintf("unknown in PostgreSQL %d", version)
+
+		case definition.Context == contextInternal:
+			reason = "cannot be changed"
+
+		case name != "shared_preload_libraries" && mandatory != nil && mandatory.Has(name):
+			reason = "managed by the operator"
+
+		case slices.Contains(patroniParameters, name):
+			reason = "managed by Patroni"
+
+		default:
+			if err := definition.validate(value.String()); err != nil {
+				reason = err.Error()
+			}
+		}
+
+		if reason == "" {
+			result.Add(name, value.String())
+		} else {
+			rejected = append(rejected, name+": "+reason)
+		}
+	}
+
+	slices.Sort(rejected)
+	return result, rejected
+}
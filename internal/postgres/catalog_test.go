@@ -0,0 +1,159 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParameterCatalog(t *testing.T) {
+	for name, definition := range parameterCatalog {
+		assert.Equal(t, name, strings.ToLower(name), "names should be normalized")
+		assert.Assert(t, !strings.Contains(name, "."), "%q belongs to an extension", name)
+
+		switch definition.Kind {
+		case kindBool, kindEnum, kindString:
+			assert.Equal(t, definition.Unit, "", "%q cannot have a unit", name)
+			assert.Assert(t, definition.Limits == nil, "%q cannot have limits", name)
+		case kindInteger, kindReal:
+			assert.Assert(t, len(definition.Values) == 0, "%q cannot have values", name)
+		default:
+			t.Errorf("%q has an unknown kind %q", name, definition.Kind)
+		}
+
+		if definition.Unit != "" {
+			_, memory := memoryUnits[definition.Unit]
+			_, time := timeUnits[definition.Unit]
+			assert.Assert(t, memory || time, "%q has an unknown unit %q", name, definition.Unit)
+		}
+		if definition.Limits != nil {
+			assert.Assert(t, definition.Limits[0] <= definition.Limits[1], "%q has inverted limits", name)
+		}
+	}
+}
+
+func TestLookupParameter(t *testing.T) {
+	_, ok := lookupParameter(17, "work_mem")
+	assert.Assert(t, ok)
+
+	_, ok = lookupParameter(17, "wrok_mem")
+	assert.Assert(t, !ok)
+
+	// Some parameters exist only in some versions.
+	_, ok = lookupParameter(12, "wal_keep_segments")
+	assert.Assert(t, ok)
+	_, ok = lookupParameter(13, "wal_keep_segments")
+	assert.Assert(t, !ok)
+	_, ok = lookupParameter(12, "wal_keep_size")
+	assert.Assert(t, !ok)
+	_, ok = lookupParameter(13, "wal_keep_size")
+	assert.Assert(t, ok)
+
+	// Some values exist only in some versions.
+	definition, _ := lookupParameter(13, "password_encryption")
+	assert.NilError(t, definition.validate("on"))
+	definition, _ = lookupParameter(14, "password_encryption")
+	assert.ErrorContains(t, definition.validate("on"), `"on" is not one of`)
+	assert.NilError(t, definition.validate("scram-sha-256"))
+	assert.DeepEqual(t, parameterCatalog["password_encryption"].Values,
+		[]string{"md5", "scram-sha-256"})
+}
+
+func TestParameterDefinitionValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name, value, err string
+	}{
+		{name: "jit", value: "on"},
+		{name: "jit", value: "FALSE"},
+		{name: "jit", value: "y"},
+		{name: "jit", value: "o", err: `"o" is not a boolean`},
+		{name: "jit", value: "maybe", err: `"maybe" is not a boolean`},
+
+		{name: "huge_pages", value: "Try"},
+		{name: "huge_pages", value: "sometimes", err: `"sometimes" is not one of`},
+
+		{name: "max_connections", value: "200"},
+		{name: "max_connections", value: "0", err: `"0" is outside the range 1 to 262143`},
+		{name: "max_connections", value: "many", err: `"many" is not a number`},
+		{name: "max_connections", value: "10MB", err: `"10MB" cannot have a unit`},
+
+		{name: "shared_buffers", value: "128MB"},
+		{name: "shared_buffers", value: "1.5 GB"},
+		{name: "shared_buffers", value: "16384"},
+		{name: "shared_buffers", value: "0x10 MB"},
+		{name: "shared_buffers", value: "64kB", err: `"64kB" is outside the range 16 to 1073741823 8kB`},
+		{name: "shared_buffers", value: "10s", err: `"10s" has an invalid unit; use a multiple of "8kB"`},
+
+		{name: "unix_socket_permissions", value: "0770"},
+		{name: "unix_socket_permissions", value: "0x1FF"},
+		{name: "unix_socket_permissions", value: "0x1FFB", err: "outside the range"},
+		{name: "unix_socket_permissions", value: "511"},
+		{name: "unix_socket_permissions", value: "0778", err: `"0778" is outside the range 0 to 511`},
+		{name: "unix_socket_permissions", value: "777", err: `"777" is outside the range 0 to 511`},
+		{name: "unix_socket_permissions", value: "0o770", err: `"0o770" is not a number`},
+
+		{name: "statement_timeout", value: "5min"},
+		{name: "statement_timeout", value: "-1", err: "outside the range"},
+
+		{name: "checkpoint_completion_target", value: "0.9"},
+		{name: "checkpoint_completion_target", value: "2", err: "outside the range 0 to 1"},
+
+		{name: "log_line_prefix", value: "%m [%p] "},
+	} {
+		definition, ok := parameterCatalog[tt.name]
+		assert.Assert(t, ok, "%q", tt.name)
+
+		err := definition.validate(tt.value)
+		if tt.err == "" {
+			assert.NilError(t, err, "%q = %q", tt.name, tt.value)
+		} else {
+			assert.ErrorContains(t, err, tt.err, "%q = %q", tt.name, tt.value)
+		}
+	}
+}
+
+func TestSpecifiedParameters(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		result, rejected := SpecifiedParameters(17, nil, nil)
+		assert.DeepEqual(t, result.AsMap(), map[string]string{})
+		assert.DeepEqual(t, rejected, []string{})
+	})
+
+	mandatory := NewParameters().Mandatory
+	mandatory.Add("shared_preload_libraries", "pgaudit")
+
+	result, rejected := SpecifiedParameters(13, map[string]intstr.IntOrString{
+		"Work_Mem":                 intstr.FromString("64MB"),
+		"max_connections":          intstr.FromInt32(200),
+		"pg_stat_statements.track": intstr.FromString("all"),
+		"shared_preload_libraries": intstr.FromString("pg_stat_statements"),
+
+		"block_size":           intstr.FromInt32(16384),
+		"enable_memoize":       intstr.FromString("off"),
+		"listen_addresses":     intstr.FromString("*"),
+		"max_wal_size":         intstr.FromString("lots"),
+		"recovery_target_name": intstr.FromString("x"),
+		"wal_level":            intstr.FromString("replica"),
+	}, mandatory)
+
+	assert.DeepEqual(t, result.AsMap(), map[string]string{
+		"max_connections":          "200",
+		"pg_stat_statements.track": "all",
+		"shared_preload_libraries": "pg_stat_statements",
+		"work_mem":                 "64MB",
+	})
+	assert.DeepEqual(t, rejected, []string{
+		"block_size: cannot be changed",
+		"enable_memoize: unknown in PostgreSQL 13",
+		"listen_addresses: managed by Patroni",
+		`max_wal_size: "lots" is not a number`,
+		"recovery_target_name: managed by Patroni",
+		"wal_level: managed by the operator",
+	})
+}
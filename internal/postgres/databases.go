@@ -8,8 +8,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"slices"
+	"strings"
 
 	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // CreateDatabasesInPostgreSQL calls exec to create databases that do not exist
@@ -70,3 +73,281 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 
 	return err
 }
+
+// WriteExtensionsInPostgreSQL calls exec to create the extensions of databases
+// that do not exist and to update or move those that do. Databases that do not
+// exist are skipped. An extension that cannot be created or updated does not
+// prevent the others. It returns the installed version of every specified
+// extension by database.
+func WriteExtensionsInPostgreSQL(
+	ctx context.Context, exec Executor, databases []v1beta1.PostgresDatabaseSpec,
+) (map[string]map[string]string, error) {
+	log := logging.FromContext(ctx)
+
+	extensions := make(map[string][]map[string]string, len(databases))
+	for _, database := range databases {
+		for _, extension := range database.Extensions {
+			data := map[string]string{"name": string(extension.Name)}
+			if extension.Schema != "" {
+				data["schema"] = string(extension.Schema)
+			}
+			if extension.Version != "" {
+				data["version"] = extension.Version
+			}
+			extensions[string(database.Name)] = append(extensions[string(database.Name)], data)
+		}
+	}
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+	encoded, _ := json.Marshal(extensions)
+
+	stdout, stderr, err := exec.ExecInDatabasesFromQuery(ctx,
+		strings.Join([]string{
+			// Prevent unexpected dereferences by emptying "search_path".
+			// The "pg_catalog" schema is still searched.
+			// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+			`SET search_path = '';`,
+
+			// Return the names of specified databases that exist and allow
+			// connections.
+			`SELECT datname FROM pg_catalog.pg_database`,
+			` WHERE datallowconn AND datname NOT IN ('template0')`,
+			`   AND datname IN (SELECT pg_catalog.json_object_keys(:'extensions'))`,
+		}, "\n"),
+		strings.Join([]string{
+			// Quiet NOTICE messages from IF NOT EXISTS statements.
+			// - https://www.postgresql.org/docs/current/runtime-config-client.html
+			`SET client_min_messages = WARNING;`,
+
+			// Do not wait for changes to be replicated. [Since PostgreSQL v9.1]
+			// - https://www.postgresql.org/docs/current/runtime-config-wal.html
+			`SET synchronous_commit = LOCAL;`,
+
+			// Prevent unexpected dereferences by emptying "search_path".
+			// The "pg_catalog" schema is still searched.
+			// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+			`SET search_path TO '';`,
+
+			`CREATE TEMPORARY TABLE input AS`,
+			`SELECT n, pg_catalog.json_extract_path_text(data, 'name') AS name,`,
+			`       pg_catalog.json_extract_path_text(data, 'schema') AS schema,`,
+			`       pg_catalog.json_extract_path_text(data, 'version') AS version`,
+			`  FROM pg_catalog.json_array_elements(pg_catalog.json_extract_path(`,
+			`       :'extensions', pg_catalog.current_database()::text))`,
+			`       WITH ORDINALITY AS extensions (data, n);`,
+
+			// Create any specified schemas.
+			// - https://www.postgresql.org/docs/current/sql-createschema.html
+			`SELECT pg_catalog.format('CREATE SCHEMA IF NOT EXISTS %I', input.schema)`,
+			`  FROM input WHERE input.schema IS NOT NULL ORDER BY input.n`,
+			`\gexec`,
+
+			// Create extensions that do not exist along with any they require.
+			// - https://www.postgresql.org/docs/current/sql-createextension.html
+			`SELECT pg_catalog.concat(pg_catalog.format('CREATE EXTENSION %I', input.name),`,
+			`       CASE WHEN input.schema IS NOT NULL THEN pg_catalog.format(' SCHEMA %I', input.schema) END,`,
+			`       CASE WHEN input.version IS NOT NULL THEN pg_catalog.format(' VERSION %L', input.version) END,`,
+			`       ' CASCADE')`,
+			`  FROM input`,
+			` WHERE NOT EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = input.name)`,
+			` ORDER BY input.n`,
+			`\gexec`,
+
+			// Move relocatable extensions to their specified schema, then update
+			// extensions to their specified version or the default version of
+			// the current image.
+			// - https://www.postgresql.org/docs/current/sql-alterextension.html
+			`SELECT pg_catalog.format('ALTER EXTENSION %I SET SCHEMA %I', input.name, input.schema)`,
+			`  FROM input JOIN pg_catalog.pg_extension ON extname = input.name`,
+			` WHERE input.schema IS NOT NULL AND extrelocatable`,
+			`   AND extnamespace NOT IN (SELECT oid FROM pg_catalog.pg_namespace WHERE nspname = input.schema)`,
+			` ORDER BY input.n`,
+			`\gexec`,
+
+			`SELECT pg_catalog.format('ALTER EXTENSION %I UPDATE TO %L',`,
+			`       input.name, COALESCE(input.version, available.default_version))`,
+			`  FROM input JOIN pg_catalog.pg_extension ON extname = input.name`,
+			`  LEFT JOIN pg_catalog.pg_available_extensions AS available ON available.name = input.name`,
+			` WHERE extversion <> COALESCE(input.version, available.default_version)`,
+			` ORDER BY input.n`,
+			`\gexec`,
+
+			// Print one line of JSON with the installed versions.
+			`\pset format unaligned`,
+			`\pset tuples_only on`,
+			`SELECT pg_catalog.json_build_object(`,
+			`       'database', pg_catalog.current_database(),`,
+			`       'extensions', COALESCE(pg_catalog.json_object_agg(input.name, extversion), '{}'))`,
+			`  FROM input JOIN pg_catalog.pg_extension ON extname = input.name;`,
+		}, "\n"),
+		map[string]string{
+			"extensions": string(encoded),
+
+			"QUIET": "on", // Do not print successful commands to stdout.
+		},
+	)
+
+	log.V(1).Info("wrote PostgreSQL extensions", "stdout", stdout, "stderr", stderr)
+
+	installed := make(map[string]map[string]string, len(extensions))
+	for _, line := range strings.Split(stdout, "\n") {
+		var result struct {
+			Database   string            `json:"database"`
+			Extensions map[string]string `json:"extensions"`
+		}
+		if json.Unmarshal([]byte(line), &result) == nil && result.Database != "" {
+			installed[result.Database] = result.Extensions
+		}
+	}
+
+	return installed, err
+}
+
+// WriteDatabasesInPostgreSQL calls exec to create the specified databases that
+// do not exist, to change the owner and connection limit of those that do, and
+// to drop the databases named in drop. A database that cannot be created or
+// changed does not prevent the others. It returns the state of every specified
+// and dropped database that exists.
+func WriteDatabasesInPostgreSQL(
+	ctx context.Context, exec Executor,
+	databases []v1beta1.PostgresDatabaseSpec, drop []string,
+) (map[string]v1beta1.PostgresDatabaseStatus, error) {
+	log := logging.FromContext(ctx)
+
+	if len(databases)+len(drop) == 0 {
+		return nil, nil
+	}
+
+	type input struct {
+		Name            string `json:"name"`
+		Owner           string `json:"owner,omitempty"`
+		ConnectionLimit *int32 `json:"connection_limit,omitempty"`
+		Encoding        string `json:"encoding,omitempty"`
+		Locale          string `json:"locale,omitempty"`
+		LocaleProvider  string `json:"locale_provider,omitempty"`
+		ICULocale       string `json:"icu_locale,omitempty"`
+		Template        string `json:"template,omitempty"`
+		Tablespace      string `json:"tablespace,omitempty"`
+	}
+	inputs := make([]input, 0, len(databases))
+	for _, database := range databases {
+		inputs = append(inputs, input{
+			Name:            string(database.Name),
+			Owner:           string(database.Owner),
+			ConnectionLimit: database.ConnectionLimit,
+			Encoding:        database.Encoding,
+			Locale:          database.Locale,
+			LocaleProvider:  database.LocaleProvider,
+			ICULocale:       database.ICULocale,
+			Template:        string(database.Template),
+			Tablespace:      string(database.Tablespace),
+		})
+	}
+
+	// These databases are necessary for PostgreSQL and the operator.
+	drop = slices.DeleteFunc(slices.Clone(drop), func(name string) bool {
+		return name == "postgres" || name == "template0" || name == "template1"
+	})
+
+	encodedInputs, _ := json.Marshal(inputs)
+	encodedDrop, _ := json.Marshal(append([]string{}, drop...))
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(strings.Join([]string{
+		// Quiet NOTICE messages from ALTER statements.
+		// - https://www.postgresql.org/docs/current/runtime-config-client.html
+		`SET client_min_messages = WARNING;`,
+
+		// Prevent unexpected dereferences by emptying "search_path".
+		// The "pg_catalog" schema is still searched.
+		// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+		`SET search_path TO '';`,
+
+		`CREATE TEMPORARY TABLE input AS`,
+		`SELECT * FROM pg_catalog.json_to_recordset(:'databases') AS input (`,
+		`       name text, owner text, connection_limit integer, encoding text, locale text,`,
+		`       locale_provider text, icu_locale text, template text, tablespace text);`,
+
+		// Create databases that do not exist, but only in a tablespace that
+		// exists. The owner is assigned later when it does not exist yet.
+		// - https://www.postgresql.org/docs/current/sql-createdatabase.html
+		`SELECT pg_catalog.concat(pg_catalog.format('CREATE DATABASE %I', input.name),`,
+		`       CASE WHEN input.owner IN (SELECT rolname FROM pg_catalog.pg_roles)`,
+		`            THEN pg_catalog.format(' OWNER %I', input.owner) END,`,
+		`       CASE WHEN input.template IS NOT NULL THEN pg_catalog.format(' TEMPLATE %I', input.template) END,`,
+		`       CASE WHEN input.encoding IS NOT NULL THEN pg_catalog.format(' ENCODING %L', input.encoding) END,`,
+		`       CASE WHEN input.locale IS NOT NULL THEN pg_catalog.format(' LOCALE %L', input.locale) END,`,
+		`       CASE WHEN input.locale_provider IS NOT NULL THEN pg_catalog.format(' LOCALE_PROVIDER %L', input.locale_provider) END,`,
+		`       CASE WHEN input.icu_locale IS NOT NULL THEN pg_catalog.format(' ICU_LOCALE %L', input.icu_locale) END,`,
+		`       CASE WHEN input.tablespace IS NOT NULL THEN pg_catalog.format(' TABLESPACE %I', input.tablespace) END,`,
+		`       CASE WHEN input.connection_limit IS NOT NULL THEN pg_catalog.format(' CONNECTION LIMIT %s', input.connection_limit) END)`,
+		`  FROM input`,
+		` WHERE NOT EXISTS (SELECT 1 FROM pg_catalog.pg_database WHERE datname = input.name)`,
+		`   AND (input.tablespace IS NULL OR input.tablespace IN (SELECT spcname FROM pg_catalog.pg_tablespace))`,
+		` ORDER BY input.name`,
+		`\gexec`,
+
+		// Change the owner and connection limit of databases that exist.
+		// - https://www.postgresql.org/docs/current/sql-alterdatabase.html
+		`SELECT pg_catalog.format('ALTER DATABASE %I OWNER TO %I', input.name, input.owner)`,
+		`  FROM input`,
+		`  JOIN pg_catalog.pg_database ON datname = input.name`,
+		`  JOIN pg_catalog.pg_roles ON rolname = input.owner`,
+		` WHERE datdba <> pg_roles.oid`,
+		` ORDER BY input.name`,
+		`\gexec`,
+
+		// A database without a connection limit in the spec has no limit.
+		`SELECT pg_catalog.format('ALTER DATABASE %I CONNECTION LIMIT %s', input.name, COALESCE(input.connection_limit, -1))`,
+		`  FROM input JOIN pg_catalog.pg_database ON datname = input.name`,
+		` WHERE datconnlimit <> COALESCE(input.connection_limit, -1)`,
+		` ORDER BY input.name`,
+		`\gexec`,
+
+		// End the sessions of databases being dropped, then drop them.
+		// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADMIN-SIGNAL
+		// - https://www.postgresql.org/docs/current/sql-dropdatabase.html
+		`SELECT pg_catalog.pg_terminate_backend(pid) FROM pg_catalog.pg_stat_activity`,
+		` WHERE datname IN (SELECT pg_catalog.json_array_elements_text(:'drop'))`,
+		`\g /dev/null`,
+
+		`SELECT pg_catalog.format('DROP DATABASE %I', datname) FROM pg_catalog.pg_database`,
+		` WHERE datname IN (SELECT pg_catalog.json_array_elements_text(:'drop'))`,
+		` ORDER BY datname`,
+		`\gexec`,
+
+		// Print one line of JSON for each database that exists.
+		`\pset format unaligned`,
+		`\pset tuples_only on`,
+		`SELECT pg_catalog.json_build_object(`,
+		`       'name', datname,`,
+		`       'owner', pg_catalog.pg_get_userbyid(datdba),`,
+		`       'connectionLimit', datconnlimit,`,
+		`       'encoding', pg_catalog.pg_encoding_to_char(encoding),`,
+		`       'collation', datcollate,`,
+		`       'tablespace', spcname)`,
+		`  FROM pg_catalog.pg_database`,
+		`  JOIN pg_catalog.pg_tablespace ON pg_tablespace.oid = dattablespace`,
+		` WHERE datname IN (SELECT name FROM input)`,
+		`    OR datname IN (SELECT pg_catalog.json_array_elements_text(:'drop'))`,
+		` ORDER BY datname;`,
+	}, "\n")),
+		map[string]string{
+			"databases": string(encodedInputs),
+			"drop":      string(encodedDrop),
+
+			"QUIET": "on", // Do not print successful commands to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL databases", "stdout", stdout, "stderr", stderr)
+
+	observed := make(map[string]v1beta1.PostgresDatabaseStatus, len(inputs)+len(drop))
+	for _, line := range strings.Split(stdout, "\n") {
+		var status v1beta1.PostgresDatabaseStatus
+		if json.Unmarshal([]byte(line), &status) == nil && status.Name != "" {
+			observed[status.Name] = status
+		}
+	}
+
+	return observed, err
+}
@@ -13,7 +13,9 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestCreateDatabasesInPostgreSQL(t *testing.T) {
@@ -90,3 +92,134 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 		assert.Equal(t, calls, 1)
 	})
 }
+
+func TestWriteExtensionsInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			t.Errorf("unexpected call: %v", command)
+			return nil
+		}
+
+		installed, err := WriteExtensionsInPostgreSQL(ctx, exec, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, installed == nil)
+
+		installed, err = WriteExtensionsInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{Name: "no-extensions"},
+		})
+		assert.NilError(t, err)
+		assert.Assert(t, installed == nil)
+	})
+
+	t.Run("Extensions", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(command,
+				`--set=extensions={"app":[{"name":"pgcrypto","schema":"crypto"},{"name":"vector","version":"0.7.0"}]}`))
+			assert.Assert(t, !strings.Contains(strings.Join(command, " "), "ON_ERROR_STOP"),
+				"expected every extension to be attempted")
+
+			assert.Assert(t, cmp.Contains(string(b), `CREATE EXTENSION %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER EXTENSION %I SET SCHEMA %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER EXTENSION %I UPDATE TO %L`))
+			assert.Assert(t, cmp.Contains(string(b), `available.default_version`))
+
+			_, _ = io.WriteString(stdout, ""+
+				`WARNING: something`+"\n"+
+				`{"database" : "app", "extensions" : {"pgcrypto" : "1.3"}}`+"\n")
+			return nil
+		}
+
+		installed, err := WriteExtensionsInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{Name: "app", Extensions: []v1beta1.PostgresExtensionSpec{
+				{Name: "pgcrypto", Schema: "crypto"},
+				{Name: "vector", Version: "0.7.0"},
+			}},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, calls, 1)
+		assert.DeepEqual(t, installed, map[string]map[string]string{
+			"app": {"pgcrypto": "1.3"},
+		})
+	})
+}
+
+func TestWriteDatabasesInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			t.Errorf("unexpected call: %v", command)
+			return nil
+		}
+
+		observed, err := WriteDatabasesInPostgreSQL(ctx, exec, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, observed == nil)
+	})
+
+	t.Run("Databases", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(command, `--set=databases=[`+
+				`{"name":"app","owner":"app","connection_limit":10,"encoding":"UTF8","template":"template0"},`+
+				`{"name":"icu","locale_provider":"icu","icu_locale":"en-US","tablespace":"fast"}]`))
+			assert.Assert(t, cmp.Contains(command, `--set=drop=["old"]`),
+				"expected necessary databases to be kept")
+			assert.Assert(t, !strings.Contains(strings.Join(command, " "), "ON_ERROR_STOP"),
+				"expected every database to be attempted")
+
+			assert.Assert(t, cmp.Contains(string(b), `CREATE DATABASE %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER DATABASE %I OWNER TO %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER DATABASE %I CONNECTION LIMIT %s`))
+			assert.Assert(t, cmp.Contains(string(b), `COALESCE(input.connection_limit, -1)`),
+				"expected an unset limit to remove any limit")
+			assert.Assert(t, cmp.Contains(string(b), `DROP DATABASE %I`))
+
+			_, _ = io.WriteString(stdout, ""+
+				`{"name" : "app", "owner" : "app", "connectionLimit" : 10, "encoding" : "UTF8", "collation" : "C", "tablespace" : "pg_default"}`+"\n"+
+				`{"name" : "old", "owner" : "postgres", "connectionLimit" : -1, "encoding" : "UTF8", "collation" : "C", "tablespace" : "pg_default"}`+"\n")
+			return nil
+		}
+
+		observed, err := WriteDatabasesInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{
+				Name: "app", Owner: "app", ConnectionLimit: initialize.Int32(10),
+				Encoding: "UTF8", Template: "template0",
+			},
+			{
+				Name: "icu", LocaleProvider: "icu", ICULocale: "en-US", Tablespace: "fast",
+			},
+		}, []string{"old", "postgres", "template1"})
+		assert.NilError(t, err)
+		assert.Equal(t, calls, 1)
+		assert.DeepEqual(t, observed, map[string]v1beta1.PostgresDatabaseStatus{
+			"app": {
+				Name: "app", Owner: "app", ConnectionLimit: initialize.Int32(10),
+				Encoding: "UTF8", Collation: "C", Tablespace: "pg_default",
+			},
+			"old": {
+				Name: "old", Owner: "postgres", ConnectionLimit: initialize.Int32(-1),
+				Encoding: "UTF8", Collation: "C", Tablespace: "pg_default",
+			},
+		})
+	})
+}
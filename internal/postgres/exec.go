@@ -41,6 +41,39 @@ func (exec Executor) Exec(
 	return stdout.String(), stderr.String(), err
 }
 
+// ExecInDatabase uses "bash" and "psql" to execute sql in database. The sql
+// statement(s) may contain psql variables that are assigned from the
+// variables map.
+// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-VARIABLES
+func (exec Executor) ExecInDatabase(
+	ctx context.Context, database string, sql io.Reader, variables map[string]string,
+) (string, string, error) {
+	// The database is passed as the first argument. Remaining arguments are
+	// passed through to `psql`.
+	args := []string{database}
+	for k, v := range variables {
+		args = append(args, "--set="+k+"="+v)
+	}
+
+	// The map iteration above is nondeterministic. Sort the variable arguments
+	// so that calls to exec are deterministic.
+	// - https://golang.org/ref/spec#For_range
+	sort.Strings(args[1:])
+
+	const script = `
+database="$1"
+shift 1
+
+PGDATABASE="${database}" exec psql "$@" -Xw --file=-
+`
+
+	// Execute the script with some error handling enabled.
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, sql, &stdout, &stderr,
+		append([]string{"bash", "-ceu", "--", script, "-"}, args...)...)
+	return stdout.String(), stderr.String(), err
+}
+
 // ExecInAllDatabases uses "bash" and "psql" to execute sql in every database
 // that allows connections, including templates. The sql command(s) may contain
 // psql variables that are assigned from the variables map.
@@ -66,6 +66,70 @@ func TestExecutorExec(t *testing.T) {
 	assert.Equal(t, stderr, "and stderr")
 }
 
+func TestExecutorExecInDatabase(t *testing.T) {
+	expected := errors.New("boom")
+	fn := func(
+		_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		b, err := io.ReadAll(stdin)
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), `statements; to run;`)
+
+		assert.DeepEqual(t, command, []string{
+			"bash", "-ceu", "--", `
+database="$1"
+shift 1
+
+PGDATABASE="${database}" exec psql "$@" -Xw --file=-
+`,
+			"-",
+			`host=127.0.0.1`,
+			"--set=CASE=sEnSiTiVe",
+			"--set=different=vars",
+		})
+
+		_, _ = io.WriteString(stdout, "some stdout")
+		_, _ = io.WriteString(stderr, "and stderr")
+		return expected
+	}
+
+	stdout, stderr, err := Executor(fn).ExecInDatabase(
+		context.Background(), `host=127.0.0.1`, strings.NewReader(`statements; to run;`),
+		map[string]string{
+			"different": "vars",
+			"CASE":      "sEnSiTiVe",
+		})
+
+	assert.Equal(t, expected, err, "expected function to be called")
+	assert.Equal(t, stdout, "some stdout")
+	assert.Equal(t, stderr, "and stderr")
+
+	t.Run("ShellCheck", func(t *testing.T) {
+		shellcheck := require.ShellCheck(t)
+
+		_, _, _ = Executor(func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			// Expect a bash command with an inline script.
+			assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+			assert.Assert(t, len(command) > 3)
+			script := command[3]
+
+			// Write out that inline script.
+			dir := t.TempDir()
+			file := filepath.Join(dir, "script.bash")
+			assert.NilError(t, os.WriteFile(file, []byte(script), 0o600))
+
+			// Expect shellcheck to be happy.
+			cmd := exec.Command(shellcheck, "--enable=all", file)
+			output, err := cmd.CombinedOutput()
+			assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+
+			return nil
+		}).ExecInDatabase(context.Background(), "", strings.NewReader(""), nil)
+	})
+}
+
 func TestExecutorExecInAllDatabases(t *testing.T) {
 	expected := errors.New("exact")
 	fn := func(
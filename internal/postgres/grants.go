@@ -0,0 +1,223 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// These are the privileges that can be granted on each kind of object. Only
+// these keywords are written into SQL; anything else in the spec is ignored.
+// - https://www.postgresql.org/docs/current/ddl-priv.html
+var (
+	databasePrivileges = []string{"CONNECT", "CREATE", "TEMPORARY"}
+	schemaPrivileges   = []string{"CREATE", "USAGE"}
+	sequencePrivileges = []string{"SELECT", "UPDATE", "USAGE"}
+	tablePrivileges    = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"}
+)
+
+// splitPrivileges returns the privileges of allowed that are in specified and
+// those that are not, each as a comma-separated list in the order of allowed.
+func splitPrivileges(allowed, specified []string) (grant, revoke string) {
+	var grants, revokes []string
+	for _, privilege := range allowed {
+		if slices.Contains(specified, privilege) {
+			grants = append(grants, privilege)
+		} else {
+			revokes = append(revokes, privilege)
+		}
+	}
+	return strings.Join(grants, ", "), strings.Join(revokes, ", ")
+}
+
+// grantStatements returns templates for pg_catalog.format() that revoke then
+// grant the privileges in spec. The templates take the following arguments:
+//
+//	%1$I the current database or schema
+//	%2$I a role that creates objects in the schema
+//	%3$I the user receiving privileges
+func grantStatements(
+	spec v1beta1.PostgresDatabaseGrant,
+) (database []string, schemas []map[string]any) {
+	// add appends statements that revoke then grant privileges on object.
+	add := func(statements []string, allowed, specified []string, object string) []string {
+		grant, revoke := splitPrivileges(allowed, specified)
+		if revoke != "" {
+			statements = append(statements, "REVOKE "+revoke+" ON "+object+" FROM %3$I")
+		}
+		if grant != "" {
+			statements = append(statements, "GRANT "+grant+" ON "+object+" TO %3$I")
+		}
+		return statements
+	}
+
+	database = add(nil, databasePrivileges, spec.Privileges, "DATABASE %1$I")
+
+	// Privileges on objects that do not exist yet are default privileges of
+	// the roles that create them. These are run once for each creator.
+	// - https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+	const defaults = "ALTER DEFAULT PRIVILEGES FOR ROLE %2$I IN SCHEMA %1$I "
+
+	for _, schema := range spec.Schemas {
+		var statements, creators []string
+		statements = add(statements, schemaPrivileges, schema.Privileges, "SCHEMA %1$I")
+		statements = add(statements, tablePrivileges, schema.Tables, "ALL TABLES IN SCHEMA %1$I")
+		statements = add(statements, sequencePrivileges, schema.Sequences, "ALL SEQUENCES IN SCHEMA %1$I")
+
+		var defaultStatements []string
+		for _, statement := range add(nil, tablePrivileges, schema.Tables, "TABLES") {
+			defaultStatements = append(defaultStatements, defaults+statement)
+		}
+		for _, statement := range add(nil, sequencePrivileges, schema.Sequences, "SEQUENCES") {
+			defaultStatements = append(defaultStatements, defaults+statement)
+		}
+
+		data := map[string]any{
+			"schema":     schema.Name,
+			"statements": statements,
+			"defaults":   defaultStatements,
+		}
+
+		// Without creators, the owner of the schema is the only creator.
+		for _, creator := range schema.Creators {
+			creators = append(creators, string(creator))
+		}
+		if len(creators) > 0 {
+			data["creators"] = creators
+		}
+
+		schemas = append(schemas, data)
+	}
+
+	return database, schemas
+}
+
+// WriteUserGrantsInPostgreSQL calls exec to revoke and grant the privileges
+// of users in their specified databases. Databases, schemas, and creators that
+// do not exist are skipped, as are schemas owned by the user. Privileges in
+// databases and schemas that are not specified are left alone.
+func WriteUserGrantsInPostgreSQL(
+	ctx context.Context, exec Executor, users []v1beta1.PostgresUserSpec,
+) error {
+	log := logging.FromContext(ctx)
+
+	var err error
+	for i := range users {
+		spec := users[i]
+
+		// The "postgres" user is a superuser; privileges do not apply.
+		if spec.Name == "postgres" || len(spec.Grants) == 0 {
+			continue
+		}
+
+		grants := make(map[string]any, len(spec.Grants))
+		for _, grant := range spec.Grants {
+			database, schemas := grantStatements(grant)
+
+			// Encode an empty array rather than null; PostgreSQL cannot
+			// extract elements from a JSON null.
+			if schemas == nil {
+				schemas = []map[string]any{}
+			}
+			grants[string(grant.Database)] = map[string]any{
+				"database": database,
+				"schemas":  schemas,
+			}
+		}
+		encoded, _ := json.Marshal(grants)
+
+		var stdout, stderr string
+		if err == nil {
+			stdout, stderr, err = exec.ExecInDatabasesFromQuery(ctx,
+				strings.Join([]string{
+					// Prevent unexpected dereferences by emptying "search_path".
+					// The "pg_catalog" schema is still searched.
+					// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+					`SET search_path = '';`,
+
+					// Return the names of specified databases that exist and
+					// allow connections.
+					`SELECT datname FROM pg_catalog.pg_database`,
+					` WHERE datallowconn AND datname NOT IN ('template0')`,
+					`   AND datname IN (SELECT pg_catalog.json_object_keys(:'grants'))`,
+				}, "\n"),
+				strings.Join([]string{
+					// Quiet NOTICE messages from REVOKE statements.
+					// - https://www.postgresql.org/docs/current/runtime-config-client.html
+					`SET client_min_messages = WARNING;`,
+
+					// Do not wait for changes to be replicated. [Since PostgreSQL v9.1]
+					// - https://www.postgresql.org/docs/current/runtime-config-wal.html
+					`SET synchronous_commit = LOCAL;`,
+
+					// Prevent unexpected dereferences by emptying "search_path".
+					// The "pg_catalog" schema is still searched.
+					// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+					`SET search_path TO '';`,
+
+					// Change privileges in a transaction so that no other session
+					// sees some privileges revoked and others not yet granted.
+					`BEGIN;`,
+
+					`SELECT pg_catalog.format(statement, pg_catalog.current_database(), NULL, :'username')`,
+					`  FROM pg_catalog.json_array_elements_text(pg_catalog.json_extract_path(`,
+					`       :'grants', pg_catalog.current_database()::text, 'database'))`,
+					`       WITH ORDINALITY AS statements (statement, n)`,
+					` ORDER BY statements.n`,
+					`\gexec`,
+
+					`SELECT pg_catalog.format(statement, nspname, NULL, :'username')`,
+					`  FROM pg_catalog.json_array_elements(pg_catalog.json_extract_path(`,
+					`       :'grants', pg_catalog.current_database()::text, 'schemas'))`,
+					`       WITH ORDINALITY AS schemas (data, n)`,
+					`  JOIN pg_catalog.pg_namespace ON nspname = pg_catalog.json_extract_path_text(schemas.data, 'schema')`,
+					`   AND nspowner NOT IN (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = :'username')`,
+					` CROSS JOIN LATERAL pg_catalog.json_array_elements_text(`,
+					`       pg_catalog.json_extract_path(schemas.data, 'statements'))`,
+					`       WITH ORDINALITY AS statements (statement, m)`,
+					` ORDER BY schemas.n, statements.m`,
+					`\gexec`,
+
+					// Change default privileges of each creator that exists. When
+					// there are no creators, the owner of the schema is the creator.
+					`SELECT pg_catalog.format(statement, nspname, rolname, :'username')`,
+					`  FROM pg_catalog.json_array_elements(pg_catalog.json_extract_path(`,
+					`       :'grants', pg_catalog.current_database()::text, 'schemas'))`,
+					`       WITH ORDINALITY AS schemas (data, n)`,
+					`  JOIN pg_catalog.pg_namespace ON nspname = pg_catalog.json_extract_path_text(schemas.data, 'schema')`,
+					`   AND nspowner NOT IN (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = :'username')`,
+					`  JOIN pg_catalog.pg_roles ON CASE`,
+					`       WHEN pg_catalog.json_extract_path(schemas.data, 'creators') IS NULL THEN pg_roles.oid = nspowner`,
+					`       ELSE rolname IN (SELECT pg_catalog.json_array_elements_text(`,
+					`            pg_catalog.json_extract_path(schemas.data, 'creators')))`,
+					`       END`,
+					` CROSS JOIN LATERAL pg_catalog.json_array_elements_text(`,
+					`       pg_catalog.json_extract_path(schemas.data, 'defaults'))`,
+					`       WITH ORDINALITY AS statements (statement, m)`,
+					` ORDER BY schemas.n, rolname, statements.m`,
+					`\gexec`,
+
+					`COMMIT;`,
+				}, "\n"),
+				map[string]string{
+					"grants":   string(encoded),
+					"username": string(spec.Name),
+
+					"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+					"QUIET":         "on", // Do not print successful commands to stdout.
+				},
+			)
+
+			log.V(1).Info("wrote PostgreSQL grants", "stdout", stdout, "stderr", stderr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestSplitPrivileges(t *testing.T) {
+	grant, revoke := splitPrivileges(tablePrivileges, nil)
+	assert.Equal(t, grant, "")
+	assert.Equal(t, revoke, "SELECT, INSERT, UPDATE, DELETE, TRUNCATE, REFERENCES, TRIGGER")
+
+	grant, revoke = splitPrivileges(schemaPrivileges, []string{"USAGE", "CREATE"})
+	assert.Equal(t, grant, "CREATE, USAGE")
+	assert.Equal(t, revoke, "")
+
+	// Unknown privileges are ignored.
+	grant, revoke = splitPrivileges(sequencePrivileges, []string{"usage", "DROP TABLE x", "SELECT"})
+	assert.Equal(t, grant, "SELECT")
+	assert.Equal(t, revoke, "UPDATE, USAGE")
+}
+
+func TestGrantStatements(t *testing.T) {
+	database, schemas := grantStatements(v1beta1.PostgresDatabaseGrant{
+		Database:   "db1",
+		Privileges: []string{"CONNECT", "TEMPORARY"},
+		Schemas: []v1beta1.PostgresSchemaGrant{{
+			Name:       "app",
+			Privileges: []string{"USAGE"},
+			Tables:     []string{"SELECT"},
+			Sequences:  []string{"SELECT", "UPDATE", "USAGE"},
+		}},
+	})
+
+	assert.DeepEqual(t, database, []string{
+		"REVOKE CREATE ON DATABASE %1$I FROM %3$I",
+		"GRANT CONNECT, TEMPORARY ON DATABASE %1$I TO %3$I",
+	})
+	assert.DeepEqual(t, schemas, []map[string]any{{
+		"schema": v1beta1.PostgresIdentifier("app"),
+		"statements": []string{
+			"REVOKE CREATE ON SCHEMA %1$I FROM %3$I",
+			"GRANT USAGE ON SCHEMA %1$I TO %3$I",
+			"REVOKE INSERT, UPDATE, DELETE, TRUNCATE, REFERENCES, TRIGGER ON ALL TABLES IN SCHEMA %1$I FROM %3$I",
+			"GRANT SELECT ON ALL TABLES IN SCHEMA %1$I TO %3$I",
+			"GRANT SELECT, UPDATE, USAGE ON ALL SEQUENCES IN SCHEMA %1$I TO %3$I",
+		},
+		"defaults": []string{
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %2$I IN SCHEMA %1$I REVOKE INSERT, UPDATE, DELETE, TRUNCATE, REFERENCES, TRIGGER ON TABLES FROM %3$I",
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %2$I IN SCHEMA %1$I GRANT SELECT ON TABLES TO %3$I",
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %2$I IN SCHEMA %1$I GRANT SELECT, UPDATE, USAGE ON SEQUENCES TO %3$I",
+		},
+	}})
+
+	t.Run("Creators", func(t *testing.T) {
+		_, schemas := grantStatements(v1beta1.PostgresDatabaseGrant{
+			Database: "db1",
+			Schemas: []v1beta1.PostgresSchemaGrant{{
+				Name:     "app",
+				Creators: []v1beta1.PostgresIdentifier{"migrator", "batch"},
+			}},
+		})
+
+		assert.Equal(t, len(schemas), 1)
+		assert.DeepEqual(t, schemas[0]["creators"], []string{"migrator", "batch"})
+	})
+}
+
+func TestWriteUserGrantsInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			t.Errorf("unexpected call: %v", command)
+			return nil
+		}
+
+		assert.NilError(t, WriteUserGrantsInPostgreSQL(ctx, exec, nil))
+		assert.NilError(t, WriteUserGrantsInPostgreSQL(ctx, exec, []v1beta1.PostgresUserSpec{
+			{Name: "no-grants", Databases: []v1beta1.PostgresIdentifier{"db1"}},
+			{Name: "postgres", Grants: []v1beta1.PostgresDatabaseGrant{{Database: "db1"}}},
+		}))
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+
+			// The first argument is a query for the databases that exist.
+			assert.Assert(t, cmp.Contains(command[5], `json_object_keys(:'grants')`))
+			assert.Assert(t, cmp.Contains(command, `--set=grants={"db1":{"database":["GRANT CONNECT, CREATE, TEMPORARY ON DATABASE %1$I TO %3$I"],"schemas":[]}}`))
+			assert.Assert(t, cmp.Contains(command, `--set=username=one`))
+
+			assert.Assert(t, cmp.Contains(string(b), `BEGIN;`))
+			assert.Assert(t, cmp.Contains(string(b), `pg_roles.oid = nspowner`))
+			assert.Assert(t, cmp.Contains(string(b), `COMMIT;`))
+			return nil
+		}
+
+		assert.NilError(t, WriteUserGrantsInPostgreSQL(ctx, exec, []v1beta1.PostgresUserSpec{
+			{Name: "one", Grants: []v1beta1.PostgresDatabaseGrant{{
+				Database:   "db1",
+				Privileges: []string{"CREATE", "CONNECT", "TEMPORARY"},
+			}}},
+		}))
+		assert.Equal(t, calls, 1)
+	})
+}
@@ -6,7 +6,17 @@ package postgres
 
 import (
 	"fmt"
+	"net"
+	"path"
+	"slices"
 	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // NewHBAs returns HostBasedAuthentication records required by this package.
@@ -35,8 +45,19 @@ func NewHBAs() HBAs {
 	}
 }
 
-// HBAs is a pairing of HostBasedAuthentication records.
-type HBAs struct{ Mandatory, Default []*HostBasedAuthentication }
+// HBAs is a grouping of HostBasedAuthentication records. Mandatory records
+// come before Users records, and those come before Specified records. Default
+// records apply when there are no Specified or other records. Confidential
+// records contain credentials; they are included from a Secret, after Users
+// records, rather than stored in Patroni configuration. Identities are the
+// user name maps referenced by any of the records.
+type HBAs struct {
+	Mandatory, Users, Specified, Default []*HostBasedAuthentication
+
+	Confidential []*HostBasedAuthentication
+
+	Identities []*IdentityMapping
+}
 
 // HostBasedAuthentication represents a single record for pg_hba.conf.
 // - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
@@ -77,6 +98,22 @@ func (hba *HostBasedAuthentication) Database(name string) *HostBasedAuthenticati
 	return hba
 }
 
+// Databases makes hba match connections made to any of the specific databases.
+func (hba *HostBasedAuthentication) Databases(names ...string) *HostBasedAuthentication {
+	quoted := make([]string, len(names))
+	for i := range names {
+		quoted[i] = hba.quote(names[i])
+	}
+	hba.database = strings.Join(quoted, ",")
+	return hba
+}
+
+// GSS makes hba match connection attempts made using TCP/IP with GSSAPI encryption.
+func (hba *HostBasedAuthentication) GSS() *HostBasedAuthentication {
+	hba.origin = "hostgssenc"
+	return hba
+}
+
 // Local makes hba match connection attempts using Unix-domain sockets.
 func (hba *HostBasedAuthentication) Local() *HostBasedAuthentication {
 	hba.origin = "local"
@@ -95,6 +132,12 @@ func (hba *HostBasedAuthentication) Network(block string) *HostBasedAuthenticati
 	return hba
 }
 
+// NoGSS makes hba match connection attempts made over TCP/IP without GSSAPI encryption.
+func (hba *HostBasedAuthentication) NoGSS() *HostBasedAuthentication {
+	hba.origin = "hostnogssenc"
+	return hba
+}
+
 // NoSSL makes hba match connection attempts made over TCP/IP without SSL.
 func (hba *HostBasedAuthentication) NoSSL() *HostBasedAuthentication {
 	hba.origin = "hostnossl"
@@ -104,8 +147,14 @@ func (hba *HostBasedAuthentication) NoSSL() *HostBasedAuthentication {
 // Options specifies any options for the authentication method.
 func (hba *HostBasedAuthentication) Options(opts map[string]string) *HostBasedAuthentication {
 	hba.options = ""
-	for k, v := range opts {
-		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(v))
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(opts[k]))
 	}
 	return hba
 }
@@ -147,6 +196,21 @@ func (hba *HostBasedAuthentication) User(name string) *HostBasedAuthentication {
 	return hba
 }
 
+// Users makes hba match connections by any of the specific users. Names that
+// begin with "+" match members of that role.
+func (hba *HostBasedAuthentication) Users(names ...string) *HostBasedAuthentication {
+	quoted := make([]string, len(names))
+	for i := range names {
+		if role, ok := strings.CutPrefix(names[i], "+"); ok {
+			quoted[i] = "+" + hba.quote(role)
+		} else {
+			quoted[i] = hba.quote(names[i])
+		}
+	}
+	hba.user = strings.Join(quoted, ",")
+	return hba
+}
+
 // String returns hba formatted for the pg_hba.conf file without a newline.
 func (hba *HostBasedAuthentication) String() string {
 	if hba.origin == "local" {
@@ -157,3 +221,308 @@ func (hba *HostBasedAuthentication) String() string {
 	return strings.TrimSpace(fmt.Sprintf("%s %s %s %s %s %s",
 		hba.origin, hba.database, hba.user, hba.address, hba.method, hba.options))
 }
+
+// SpecifiedHBAs returns the HostBasedAuthentication records of rules in order.
+// Each rule that cannot be rendered safely is described in the returned list
+// and has no records.
+func SpecifiedHBAs(rules []v1beta1.PostgresHBARule) ([]*HostBasedAuthentication, []string) {
+	records := []*HostBasedAuthentication{}
+	rejected := []string{}
+
+	// Control characters, like newlines, cannot be quoted in pg_hba.conf.
+	// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	unsafe := func(value string) bool { return strings.ContainsFunc(value, unicode.IsControl) }
+
+	for i, rule := range rules {
+		var reasons []string
+
+		databases := make([]string, len(rule.Databases))
+		for j := range rule.Databases {
+			databases[j] = string(rule.Databases[j])
+			if unsafe(databases[j]) {
+				reasons = append(reasons, fmt.Sprintf("database %q contains a control character", databases[j]))
+			}
+		}
+		users := make([]string, len(rule.Users))
+		for j := range rule.Users {
+			users[j] = string(rule.Users[j])
+			if unsafe(users[j]) {
+				reasons = append(reasons, fmt.Sprintf("user %q contains a control character", users[j]))
+			}
+		}
+		for _, address := range rule.Addresses {
+			if _, _, err := net.ParseCIDR(address); err != nil {
+				reasons = append(reasons, fmt.Sprintf("address %q is not in CIDR notation", address))
+			}
+		}
+		for k, v := range rule.Options {
+			if unsafe(v) {
+				reasons = append(reasons, fmt.Sprintf("option %q contains a control character", k))
+			}
+		}
+
+		if len(reasons) > 0 {
+			slices.Sort(reasons)
+			rejected = append(rejected, fmt.Sprintf("rule %d: %s", i, strings.Join(reasons, ", ")))
+			continue
+		}
+
+		build := func() *HostBasedAuthentication {
+			hba := NewHBA().Method(rule.Method)
+			switch rule.Connection {
+			case "local":
+				hba.Local()
+			case "host":
+				hba.TCP()
+			case "hostssl":
+				hba.TLS()
+			case "hostnossl":
+				hba.NoSSL()
+			case "hostgssenc":
+				hba.GSS()
+			case "hostnogssenc":
+				hba.NoGSS()
+			}
+			if len(databases) > 0 {
+				// PostgreSQL recognizes these keywords only when they are
+				// not quoted.
+				// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+				fields := make([]string, len(databases))
+				for j, name := range databases {
+					if slices.Contains([]string{"all", "replication", "samerole", "sameuser"}, name) {
+						fields[j] = name
+					} else {
+						fields[j] = hba.quote(name)
+					}
+				}
+				hba.database = strings.Join(fields, ",")
+			}
+			if len(users) > 0 {
+				hba.Users(users...)
+			}
+			if len(rule.Options) > 0 {
+				hba.Options(rule.Options)
+			}
+			return hba
+		}
+
+		if len(rule.Addresses) == 0 {
+			records = append(records, build())
+		}
+		for _, address := range rule.Addresses {
+			records = append(records, build().Network(address))
+		}
+	}
+
+	return records, rejected
+}
+
+// IdentityMapping represents a single record for pg_ident.conf.
+// - https://www.postgresql.org/docs/current/auth-username-maps.html
+type IdentityMapping struct {
+	mapName, system, user string
+}
+
+// NewIdentityMapping returns a record that maps the system user name to the
+// database user name in the map named mapName.
+func NewIdentityMapping(mapName, system, user string) *IdentityMapping {
+	return &IdentityMapping{mapName: mapName, system: system, user: user}
+}
+
+// String returns ident formatted for the pg_ident.conf file without a newline.
+func (ident *IdentityMapping) String() string {
+	quote := new(HostBasedAuthentication).quote
+	return fmt.Sprintf("%s %s %s", ident.mapName, quote(ident.system), quote(ident.user))
+}
+
+const (
+	// certificateIdentityMap is the name of the pg_ident.conf map used by
+	// certificate authentication of users.
+	certificateIdentityMap = "pgo-cert"
+
+	// kerberosIdentityMap is the name of the pg_ident.conf map used by GSSAPI
+	// authentication of users.
+	kerberosIdentityMap = "pgo-gss"
+)
+
+// UserHBAs appends the HostBasedAuthentication records and IdentityMapping
+// records of users that have an authentication method other than password to
+// outHBAs. The "postgres" user is skipped. LDAP bind passwords are looked up in
+// bindPasswords by user name, and records that contain them are Confidential.
+// Each user that cannot be rendered safely is described in the returned list
+// and has no records.
+func UserHBAs(
+	users []v1beta1.PostgresUserSpec, bindPasswords map[string]string, outHBAs *HBAs,
+) []string {
+	rejected := []string{}
+
+	// Control characters, like newlines, cannot be quoted in pg_hba.conf.
+	// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	unsafe := func(value string) bool { return strings.ContainsFunc(value, unicode.IsControl) }
+
+	for _, user := range users {
+		name := string(user.Name)
+		auth := user.Authentication
+		if name == "postgres" || auth == nil {
+			continue
+		}
+
+		var reasons []string
+		check := func(field string, values ...string) {
+			for _, value := range values {
+				if unsafe(value) {
+					reasons = append(reasons, fmt.Sprintf("%s %q contains a control character", field, value))
+				}
+			}
+		}
+		check("name", name)
+
+		switch {
+		case auth.Certificate != nil:
+			check("common name", auth.Certificate.CommonNames...)
+
+		case auth.GSS != nil:
+			check("principal", auth.GSS.Principals...)
+
+		case auth.LDAP != nil:
+			check("server", auth.LDAP.Server)
+			check("prefix", auth.LDAP.Prefix)
+			check("suffix", auth.LDAP.Suffix)
+			check("base DN", auth.LDAP.BaseDN)
+			check("bind DN", auth.LDAP.BindDN)
+			check("search attribute", auth.LDAP.SearchAttribute)
+
+			if unsafe(bindPasswords[name]) {
+				reasons = append(reasons, "bind password contains a control character")
+			}
+		}
+
+		if len(reasons) > 0 {
+			slices.Sort(reasons)
+			rejected = append(rejected, fmt.Sprintf("user %q: %s", name, strings.Join(reasons, ", ")))
+			continue
+		}
+
+		switch {
+		case auth.Certificate != nil:
+			// The certificate issued by PGO has the user name as its CN.
+			// - https://www.postgresql.org/docs/current/auth-cert.html
+			outHBAs.Users = append(outHBAs.Users, NewHBA().TLS().User(name).Method("cert").
+				Options(map[string]string{"map": certificateIdentityMap}))
+
+			outHBAs.Identities = append(outHBAs.Identities,
+				NewIdentityMapping(certificateIdentityMap, name, name))
+			for _, cn := range auth.Certificate.CommonNames {
+				outHBAs.Identities = append(outHBAs.Identities,
+					NewIdentityMapping(certificateIdentityMap, cn, name))
+			}
+
+		case auth.GSS != nil:
+			// Principals include their realm so they can be mapped exactly.
+			// - https://www.postgresql.org/docs/current/gssapi-auth.html
+			options := map[string]string{"include_realm": "1", "map": kerberosIdentityMap}
+			outHBAs.Users = append(outHBAs.Users,
+				NewHBA().GSS().User(name).Method("gss").Options(options),
+				NewHBA().TLS().User(name).Method("gss").Options(options))
+
+			for _, principal := range auth.GSS.Principals {
+				outHBAs.Identities = append(outHBAs.Identities,
+					NewIdentityMapping(kerberosIdentityMap, principal, name))
+			}
+
+		case auth.LDAP != nil:
+			// - https://www.postgresql.org/docs/current/auth-ldap.html
+			options := map[string]string{"ldapserver": auth.LDAP.Server}
+			if auth.LDAP.Port != nil {
+				options["ldapport"] = fmt.Sprint(*auth.LDAP.Port)
+			}
+			if auth.LDAP.Scheme != "" {
+				options["ldapscheme"] = auth.LDAP.Scheme
+			}
+			if auth.LDAP.StartTLS != nil && *auth.LDAP.StartTLS {
+				options["ldaptls"] = "1"
+			}
+
+			if auth.LDAP.BaseDN == "" {
+				options["ldapprefix"] = auth.LDAP.Prefix
+				options["ldapsuffix"] = auth.LDAP.Suffix
+			} else {
+				options["ldapbasedn"] = auth.LDAP.BaseDN
+				if auth.LDAP.BindDN != "" {
+					options["ldapbinddn"] = auth.LDAP.BindDN
+				}
+				if auth.LDAP.SearchAttribute != "" {
+					options["ldapsearchattribute"] = auth.LDAP.SearchAttribute
+				}
+				if password, ok := bindPasswords[name]; ok {
+					options["ldapbindpasswd"] = password
+					outHBAs.Confidential = append(outHBAs.Confidential,
+						NewHBA().TLS().User(name).Method("ldap").Options(options))
+					continue
+				}
+			}
+
+			outHBAs.Users = append(outHBAs.Users, NewHBA().TLS().User(name).Method("ldap").Options(options))
+		}
+	}
+
+	return rejected
+}
+
+const (
+	// confidentialHBAsKey is the key of the Secret that contains the records
+	// of [HBAs.Confidential].
+	confidentialHBAsKey = "pg_hba.conf"
+
+	// ConfidentialHBAsPath is where the records of [HBAs.Confidential] are
+	// mounted. This is in the certificate volume so that PostgreSQL reloads
+	// them when they change; see [reloadCommand].
+	ConfidentialHBAsPath = naming.CertMountPath + "/pg_hba_confidential.conf"
+)
+
+// IncludesConfidentialHBAs reports whether any user in cluster has records
+// that belong in [HBAs.Confidential]. These can be included from a file since
+// PostgreSQL v16.
+// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+func IncludesConfidentialHBAs(cluster *v1beta1.PostgresCluster) bool {
+	if cluster.Spec.PostgresVersion < 16 {
+		return false
+	}
+	for _, user := range cluster.Spec.Users {
+		if user.Name != "postgres" && user.Authentication != nil &&
+			user.Authentication.LDAP != nil && user.Authentication.LDAP.BindPassword != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfidentialHBASecret populates outSecret with the records of
+// inHBAs.Confidential, one per line.
+func ConfidentialHBASecret(inHBAs HBAs, outSecret *corev1.Secret) {
+	initialize.Map(&outSecret.Data)
+
+	var file strings.Builder
+	for i := range inHBAs.Confidential {
+		_, _ = file.WriteString(inHBAs.Confidential[i].String() + "\n")
+	}
+	outSecret.Data[confidentialHBAsKey] = []byte(file.String())
+}
+
+// confidentialHBAsProjection returns a projection of the Secret that contains
+// the records of [HBAs.Confidential]. It is optional so that PostgreSQL can
+// start before the Secret exists.
+func confidentialHBAsProjection(cluster *v1beta1.PostgresCluster) corev1.VolumeProjection {
+	return corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: naming.PostgresHBASecret(cluster).Name,
+			},
+			Items: []corev1.KeyToPath{{
+				Key:  confidentialHBAsKey,
+				Path: path.Base(ConfidentialHBAsPath),
+			}},
+			Optional: initialize.Bool(true),
+		},
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"testing"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestNewHBAs(t *testing.T) {
@@ -59,4 +61,173 @@ func TestHostBasedAuthentication(t *testing.T) {
 
 	assert.Equal(t, `hostnossl all all all reject`,
 		NewHBA().NoSSL().Method("reject").String())
+
+	assert.Equal(t, `hostgssenc "one","t""wo" "app",+"readers" all gss  include_realm="0" krb_realm="EXAMPLE.COM"`,
+		NewHBA().GSS().Databases("one", `t"wo`).Users("app", "+readers").
+			Method("gss").Options(map[string]string{"krb_realm": "EXAMPLE.COM", "include_realm": "0"}).
+			String())
+
+	assert.Equal(t, `hostnogssenc all all all reject`,
+		NewHBA().NoGSS().Method("reject").String())
+}
+
+func TestSpecifiedHBAs(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		records, rejected := SpecifiedHBAs(nil)
+		assert.Equal(t, len(records), 0)
+		assert.Equal(t, len(rejected), 0)
+	})
+
+	records, rejected := SpecifiedHBAs([]v1beta1.PostgresHBARule{
+		{Connection: "hostssl", Databases: []v1beta1.PostgresIdentifier{"app"}, Method: "scram-sha-256"},
+		{Connection: "hostssl", Databases: []v1beta1.PostgresIdentifier{"replication", "sameuser", "Other"}, Method: "cert"},
+		{Connection: "host", Users: []v1beta1.PostgresIdentifier{"+ops", "x\nlocal all all trust"}, Method: "md5"},
+		{Connection: "host", Addresses: []string{"10.0.0.0/8", "fd00::/8"}, Method: "reject"},
+		{Connection: "hostssl", Addresses: []string{"10.0.0.1"}, Method: "md5"},
+		{Connection: "local", Users: []v1beta1.PostgresIdentifier{"app"}, Method: "peer",
+			Options: map[string]string{"map": "omicron"}},
+	})
+
+	printed := make([]string, len(records))
+	for i := range records {
+		printed[i] = records[i].String()
+	}
+	assert.DeepEqual(t, printed, []string{
+		`hostssl "app" all all scram-sha-256`,
+		`hostssl replication,sameuser,"Other" all all cert`,
+		`host all all "10.0.0.0/8" reject`,
+		`host all all "fd00::/8" reject`,
+		`local all "app" peer  map="omicron"`,
+	})
+	assert.DeepEqual(t, rejected, []string{
+		`rule 2: user "x\nlocal all all trust" contains a control character`,
+		`rule 4: address "10.0.0.1" is not in CIDR notation`,
+	})
+}
+
+func TestIdentityMapping(t *testing.T) {
+	assert.Equal(t, NewIdentityMapping("m", "sys", "db").String(), `m "sys" "db"`)
+	assert.Equal(t, NewIdentityMapping("m", `a"b`, "c").String(), `m "a""b" "c"`)
+}
+
+func TestUserHBAs(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		var hbas HBAs
+		rejected := UserHBAs(nil, nil, &hbas)
+		assert.Equal(t, len(hbas.Users), 0)
+		assert.Equal(t, len(hbas.Confidential), 0)
+		assert.Equal(t, len(hbas.Identities), 0)
+		assert.Equal(t, len(rejected), 0)
+	})
+
+	port := int32(636)
+	var hbas HBAs
+	rejected := UserHBAs([]v1beta1.PostgresUserSpec{
+		{Name: "postgres", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			Certificate: &v1beta1.PostgresCertificateAuthentication{},
+		}},
+		{Name: "plain"},
+		{Name: "alice", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			Certificate: &v1beta1.PostgresCertificateAuthentication{CommonNames: []string{"alice.example.com"}},
+		}},
+		{Name: "bob", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			GSS: &v1beta1.PostgresGSSAuthentication{Principals: []string{"bob@EXAMPLE.COM"}},
+		}},
+		{Name: "carol", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{
+				Server: "ldap.example.com", Prefix: "cn=", Suffix: ",dc=example,dc=com",
+			},
+		}},
+		{Name: "dave", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{
+				Server: "ldap.example.com", Port: &port, Scheme: "ldaps",
+				BaseDN: "dc=example,dc=com", BindDN: "cn=pg", SearchAttribute: "cn",
+			},
+		}},
+		{Name: "eve", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{
+				Server: "ldap.example.com", BaseDN: "dc=example,dc=com",
+			},
+		}},
+		{Name: "mallory", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			GSS: &v1beta1.PostgresGSSAuthentication{Principals: []string{"x\nlocal all all trust"}},
+		}},
+	}, map[string]string{
+		"dave": "secret",
+		"eve":  "a\nb",
+	}, &hbas)
+
+	printed := make([]string, len(hbas.Users))
+	for i := range hbas.Users {
+		printed[i] = hbas.Users[i].String()
+	}
+	assert.DeepEqual(t, printed, []string{
+		`hostssl all "alice" all cert  map="pgo-cert"`,
+		`hostgssenc all "bob" all gss  include_realm="1" map="pgo-gss"`,
+		`hostssl all "bob" all gss  include_realm="1" map="pgo-gss"`,
+		`hostssl all "carol" all ldap  ldapprefix="cn=" ldapserver="ldap.example.com" ldapsuffix=",dc=example,dc=com"`,
+	})
+
+	// Records with bind passwords are kept apart from the others.
+	assert.Equal(t, len(hbas.Confidential), 1)
+	assert.Equal(t, hbas.Confidential[0].String(),
+		`hostssl all "dave" all ldap  ldapbasedn="dc=example,dc=com" ldapbinddn="cn=pg" ldapbindpasswd="secret" ldapport="636" ldapscheme="ldaps" ldapsearchattribute="cn" ldapserver="ldap.example.com"`)
+
+	mapped := make([]string, len(hbas.Identities))
+	for i := range hbas.Identities {
+		mapped[i] = hbas.Identities[i].String()
+	}
+	assert.DeepEqual(t, mapped, []string{
+		`pgo-cert "alice" "alice"`,
+		`pgo-cert "alice.example.com" "alice"`,
+		`pgo-gss "bob@EXAMPLE.COM" "bob"`,
+	})
+
+	assert.DeepEqual(t, rejected, []string{
+		`user "eve": bind password contains a control character`,
+		`user "mallory": principal "x\nlocal all all trust" contains a control character`,
+	})
+}
+
+func TestIncludesConfidentialHBAs(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.PostgresVersion = 16
+	assert.Assert(t, !IncludesConfidentialHBAs(cluster))
+
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+		{Name: "postgres", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{BindPassword: &corev1.SecretKeySelector{}},
+		}},
+		{Name: "anonymous", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{},
+		}},
+	}
+	assert.Assert(t, !IncludesConfidentialHBAs(cluster), "expected no bind password")
+
+	cluster.Spec.Users = append(cluster.Spec.Users, v1beta1.PostgresUserSpec{
+		Name: "searcher", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+			LDAP: &v1beta1.PostgresLDAPAuthentication{BindPassword: &corev1.SecretKeySelector{}},
+		},
+	})
+	assert.Assert(t, IncludesConfidentialHBAs(cluster))
+
+	cluster.Spec.PostgresVersion = 15
+	assert.Assert(t, !IncludesConfidentialHBAs(cluster), "expected no include directive")
+}
+
+func TestConfidentialHBASecret(t *testing.T) {
+	secret := new(corev1.Secret)
+	ConfidentialHBASecret(HBAs{}, secret)
+	assert.DeepEqual(t, secret.Data, map[string][]byte{"pg_hba.conf": []byte("")})
+
+	ConfidentialHBASecret(HBAs{
+		Users: []*HostBasedAuthentication{NewHBA().TLS().Method("cert")},
+		Confidential: []*HostBasedAuthentication{
+			NewHBA().TLS().User("a").Method("ldap").Options(map[string]string{"ldapbindpasswd": "x"}),
+			NewHBA().TLS().User("b").Method("ldap"),
+		},
+	}, secret)
+	assert.Equal(t, string(secret.Data["pg_hba.conf"]), ``+
+		`hostssl all "a" all ldap  ldapbindpasswd="x"`+"\n"+
+		`hostssl all "b" all ldap`+"\n")
 }
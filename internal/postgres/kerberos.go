@@ -0,0 +1,36 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// kerberosKeytabPath is where the keytab of the PostgreSQL service principal
+// is mounted, next to any additional config files.
+const kerberosKeytabPath = configMountPath + "/krb5.keytab"
+
+// KerberosKeytab returns the Secret key of the first user in cluster with
+// GSSAPI authentication, or nil when there is none.
+func KerberosKeytab(cluster *v1beta1.PostgresCluster) *corev1.SecretKeySelector {
+	for _, user := range cluster.Spec.Users {
+		if user.Name != "postgres" &&
+			user.Authentication != nil && user.Authentication.GSS != nil {
+			return &user.Authentication.GSS.Keytab
+		}
+	}
+	return nil
+}
+
+// SetKerberosKeytab sets the PostgreSQL parameter "krb_server_keyfile" when
+// any user in cluster authenticates with GSSAPI.
+// - https://www.postgresql.org/docs/current/runtime-config-connection.html#GUC-KRB-SERVER-KEYFILE
+func SetKerberosKeytab(cluster *v1beta1.PostgresCluster, pgParameters *Parameters) {
+	if KerberosKeytab(cluster) != nil {
+		pgParameters.Mandatory.Add("krb_server_keyfile", kerberosKeytabPath)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestSetKerberosKeytab(t *testing.T) {
+	gss := func(name string) *v1beta1.PostgresUserAuthenticationSpec {
+		return &v1beta1.PostgresUserAuthenticationSpec{
+			GSS: &v1beta1.PostgresGSSAuthentication{
+				Principals: []string{"someone@EXAMPLE.COM"},
+				Keytab: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Key:                  "keytab",
+				},
+			},
+		}
+	}
+
+	t.Run("NoUsers", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		assert.Assert(t, KerberosKeytab(cluster) == nil)
+
+		pgParameters := NewParameters()
+		SetKerberosKeytab(cluster, &pgParameters)
+		assert.Equal(t, pgParameters.Mandatory.Has("krb_server_keyfile"), false)
+	})
+
+	t.Run("NoGSS", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "postgres", Authentication: gss("ignored")},
+			{Name: "app", Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+				Certificate: &v1beta1.PostgresCertificateAuthentication{},
+			}},
+		}
+		assert.Assert(t, KerberosKeytab(cluster) == nil)
+
+		pgParameters := NewParameters()
+		SetKerberosKeytab(cluster, &pgParameters)
+		assert.Equal(t, pgParameters.Mandatory.Has("krb_server_keyfile"), false)
+	})
+
+	t.Run("FirstUser", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "app"},
+			{Name: "one", Authentication: gss("first")},
+			{Name: "two", Authentication: gss("second")},
+		}
+
+		keytab := KerberosKeytab(cluster)
+		assert.Assert(t, keytab != nil)
+		assert.Equal(t, keytab.Name, "first")
+
+		pgParameters := NewParameters()
+		SetKerberosKeytab(cluster, &pgParameters)
+		assert.Equal(t, pgParameters.Mandatory.Value("krb_server_keyfile"), "/etc/postgres/krb5.keytab")
+	})
+}
@@ -50,8 +50,9 @@ func NewParameters() Parameters {
 	return parameters
 }
 
-// Parameters is a pairing of ParameterSets.
-type Parameters struct{ Mandatory, Default *ParameterSet }
+// Parameters is a grouping of ParameterSets. Mandatory parameters take
+// precedence over Specified parameters which take precedence over Default ones.
+type Parameters struct{ Mandatory, Specified, Default *ParameterSet }
 
 // ParameterSet is a collection of PostgreSQL parameters.
 // - https://www.postgresql.org/docs/current/config-setting.html
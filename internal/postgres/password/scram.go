@@ -12,6 +12,8 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -109,6 +111,34 @@ func (s *SCRAMPassword) Build() (string, error) {
 	return verifier, nil
 }
 
+// Verify reports whether verifier was built from the password of s. It builds
+// a verifier using the iterations and salt of verifier and compares the two.
+func (s *SCRAMPassword) Verify(verifier string) bool {
+	parts := strings.Split(verifier, "$")
+	if len(parts) != 3 || parts[0] != "SCRAM-SHA-256" {
+		return false
+	}
+
+	iterations, encodedSalt, ok := strings.Cut(parts[1], ":")
+	if !ok {
+		return false
+	}
+
+	rebuild := *s
+	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+	if err == nil {
+		rebuild.Iterations, err = strconv.Atoi(iterations)
+	}
+	if err != nil || rebuild.Iterations < 1 || len(salt) < 1 {
+		return false
+	}
+
+	rebuild.generateSalt = func(int) ([]byte, error) { return salt, nil }
+	built, err := rebuild.Build()
+
+	return err == nil && hmac.Equal([]byte(built), []byte(verifier))
+}
+
 // encode creates a base64 encoding of a value that's returned as a string
 func (s *SCRAMPassword) encode(value []byte) string {
 	return base64.StdEncoding.EncodeToString(value)
@@ -119,6 +119,40 @@ func TestSCRAMBuild(t *testing.T) {
 	})
 }
 
+func TestSCRAMVerify(t *testing.T) {
+	const verifier = `SCRAM-SHA-256$4096:aDFwcDBwNHJ0eTIwMjA=$xHkOo65LX9eBB8a6v+axqvs3+aMBTH0sCT7w/Nxzh5M=:PXuFoeJNuAGSeExskYSqkwUyiUJu8LPC9DgwDWQ9ARQ=`
+
+	t.Run("valid", func(t *testing.T) {
+		if !NewSCRAMPassword(`datalake`).Verify(verifier) {
+			t.Errorf("expected %q to verify", verifier)
+		}
+
+		// a verifier with a random salt
+		built, err := NewSCRAMPassword(`øásis`).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !NewSCRAMPassword(`øásis`).Verify(built) {
+			t.Errorf("expected %q to verify", built)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tt := range []struct{ password, verifier string }{
+			{`datalake`, ``},
+			{`datalake`, `md53a0689aa9e31a50b5621971fc89f0c64`},
+			{`datalake`, `SCRAM-SHA-256$4096$xHkOo65LX9eBB8a6v+axqvs3+aMBTH0sCT7w/Nxzh5M=`},
+			{`datalake`, `SCRAM-SHA-256$0:aDFwcDBwNHJ0eTIwMjA=$xHkOo65LX9eBB8a6v+axqvs3+aMBTH0sCT7w/Nxzh5M=:PXuFoeJNuAGSeExskYSqkwUyiUJu8LPC9DgwDWQ9ARQ=`},
+			{`datalake`, `SCRAM-SHA-256$4096:!!!$xHkOo65LX9eBB8a6v+axqvs3+aMBTH0sCT7w/Nxzh5M=:PXuFoeJNuAGSeExskYSqkwUyiUJu8LPC9DgwDWQ9ARQ=`},
+			{`datalakE`, verifier},
+		} {
+			if NewSCRAMPassword(tt.password).Verify(tt.verifier) {
+				t.Errorf("expected %q not to verify %q", tt.password, tt.verifier)
+			}
+		}
+	})
+}
+
 func TestSCRAMEncode(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		scram := SCRAMPassword{}
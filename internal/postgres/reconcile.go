@@ -86,6 +86,13 @@ func InstancePod(ctx context.Context,
 		},
 	}
 
+	// Project any HBA records that contain credentials next to the
+	// certificates so that PostgreSQL reloads them when they change.
+	if IncludesConfidentialHBAs(inCluster) {
+		certVolume.Projected.Sources = append(certVolume.Projected.Sources,
+			confidentialHBAsProjection(inCluster))
+	}
+
 	dataVolumeMount := DataVolumeMount()
 	dataVolume := corev1.Volume{
 		Name: dataVolumeMount.Name,
@@ -232,11 +239,28 @@ func InstancePod(ctx context.Context,
 		startup.VolumeMounts = append(startup.VolumeMounts, tablespaceVolumeMount)
 	}
 
-	if len(inCluster.Spec.Config.Files) != 0 {
+	// Project the keytab of any user with GSSAPI authentication alongside the
+	// additional config files.
+	additionalConfigSources := append([]corev1.VolumeProjection{}, inCluster.Spec.Config.Files...)
+	if keytab := KerberosKeytab(inCluster); keytab != nil {
+		additionalConfigSources = append(additionalConfigSources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: keytab.LocalObjectReference,
+				Items: []corev1.KeyToPath{{
+					Key:  keytab.Key,
+					Path: "krb5.keytab",
+					Mode: initialize.Int32(0o600),
+				}},
+				Optional: keytab.Optional,
+			},
+		})
+	}
+
+	if len(additionalConfigSources) != 0 {
 		additionalConfigVolumeMount := AdditionalConfigVolumeMount()
 		additionalConfigVolume := corev1.Volume{Name: additionalConfigVolumeMount.Name}
 		additionalConfigVolume.Projected = &corev1.ProjectedVolumeSource{
-			Sources: append([]corev1.VolumeProjection{}, inCluster.Spec.Config.Files...),
+			Sources: additionalConfigSources,
 		}
 		container.VolumeMounts = append(container.VolumeMounts, additionalConfigVolumeMount)
 		outInstancePod.Volumes = append(outInstancePod.Volumes, additionalConfigVolume)
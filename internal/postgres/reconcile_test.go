@@ -523,6 +523,81 @@ volumes:
   name: postgres-data`), "expected WAL mount, no downwardAPI mount in %q container", pod.InitContainers[0].Name)
 	})
 
+	t.Run("WithKerberosKeytab", func(t *testing.T) {
+		clusterWithKeytab := cluster.DeepCopy()
+		clusterWithKeytab.Spec.Users = []v1beta1.PostgresUserSpec{{
+			Name: "alice",
+			Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+				GSS: &v1beta1.PostgresGSSAuthentication{
+					Principals: []string{"alice@EXAMPLE.COM"},
+					Keytab: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "kerberos"},
+						Key:                  "postgres.keytab",
+					},
+				},
+			},
+		}}
+
+		pod := new(corev1.PodSpec)
+		InstancePod(ctx, clusterWithKeytab, instance,
+			serverSecretProjection, clientSecretProjection, dataVolume, nil, nil, pod)
+
+		assert.Assert(t, cmp.MarshalContains(pod.Containers[0].VolumeMounts, `
+- mountPath: /etc/postgres
+  name: postgres-config
+  readOnly: true`))
+		assert.Assert(t, cmp.MarshalContains(pod.Volumes, `
+- name: postgres-config
+  projected:
+    sources:
+    - secret:
+        items:
+        - key: postgres.keytab
+          mode: 384
+          path: krb5.keytab
+        name: kerberos`))
+	})
+
+	t.Run("WithLDAPBindPassword", func(t *testing.T) {
+		clusterWithLDAP := cluster.DeepCopy()
+		clusterWithLDAP.Name = "hippo"
+		clusterWithLDAP.Spec.PostgresVersion = 16
+		clusterWithLDAP.Spec.Users = []v1beta1.PostgresUserSpec{{
+			Name: "alice",
+			Authentication: &v1beta1.PostgresUserAuthenticationSpec{
+				LDAP: &v1beta1.PostgresLDAPAuthentication{
+					Server: "ldap.example.com", BaseDN: "dc=example",
+					BindPassword: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "ldap"},
+						Key:                  "password",
+					},
+				},
+			},
+		}}
+
+		pod := new(corev1.PodSpec)
+		InstancePod(ctx, clusterWithLDAP, instance,
+			serverSecretProjection, clientSecretProjection, dataVolume, nil, nil, pod)
+
+		// The records are projected next to the certificates, not the bind password.
+		assert.Equal(t, pod.Volumes[0].Name, "cert-volume")
+		sources := pod.Volumes[0].Projected.Sources
+		assert.Equal(t, len(sources), 3)
+		assert.Assert(t, cmp.MarshalMatches(sources[2], `
+secret:
+  items:
+  - key: pg_hba.conf
+    path: pg_hba_confidential.conf
+  name: hippo-pg-hba
+  optional: true`))
+
+		clusterWithLDAP.Spec.PostgresVersion = 15
+		pod = new(corev1.PodSpec)
+		InstancePod(ctx, clusterWithLDAP, instance,
+			serverSecretProjection, clientSecretProjection, dataVolume, nil, nil, pod)
+		assert.Equal(t, len(pod.Volumes[0].Projected.Sources), 2)
+	})
+
 	t.Run("WithCustomSidecarContainer", func(t *testing.T) {
 		sidecarInstance := new(v1beta1.PostgresInstanceSetSpec)
 		sidecarInstance.Containers = []corev1.Container{
@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"slices"
 	"strings"
 
 	pg_query "github.com/pganalyze/pg_query_go/v5"
@@ -56,13 +57,19 @@ func sanitizeAlterRoleOptions(options string) string {
 	return strings.TrimPrefix(sql, AlterRolePrefix)
 }
 
+// AlternateUserName returns the name of the role that holds one of the
+// passwords of user name during a password rotation grace period. Names in the
+// spec cannot contain underscore, U+005F, so this never matches another user.
+func AlternateUserName(name string) string { return name + "_alt" }
+
 // WriteUsersInPostgreSQL calls exec to create users that do not exist in
 // PostgreSQL. Once they exist, it updates their options and passwords and
 // grants them access to their specified databases. The databases must already
-// exist.
+// exist. Both verifiers and alternates are indexed by user name; users that are
+// in alternates have an alternate role with that verifier.
 func WriteUsersInPostgreSQL(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, exec Executor,
-	users []v1beta1.PostgresUserSpec, verifiers map[string]string,
+	users []v1beta1.PostgresUserSpec, verifiers, alternates map[string]string,
 ) error {
 	log := logging.FromContext(ctx)
 
@@ -92,21 +99,54 @@ CREATE TEMPORARY TABLE input (id serial, data json);
 		spec := users[i]
 
 		databases := spec.Databases
+		memberOf := spec.MemberOf
 		options := sanitizeAlterRoleOptions(spec.Options)
 
+		// Users can login unless their options say otherwise. This restores
+		// the LOGIN option of a user that was revoked then specified again.
+		if !slices.ContainsFunc(strings.Fields(options), func(word string) bool {
+			return word == "LOGIN" || word == "NOLOGIN"
+		}) {
+			options = strings.TrimSpace("LOGIN " + options)
+		}
+
+		// Privileges in grants replace ALL on their databases.
+		if len(spec.Grants) > 0 {
+			databases = slices.DeleteFunc(slices.Clone(databases), func(d v1beta1.PostgresIdentifier) bool {
+				return slices.ContainsFunc(spec.Grants, func(g v1beta1.PostgresDatabaseGrant) bool {
+					return g.Database == d
+				})
+			})
+		}
+
 		// The "postgres" user must always be a superuser that can login to
 		// the "postgres" database.
 		if spec.Name == "postgres" {
 			databases = append(databases[:0:0], "postgres")
+			memberOf = nil
 			options = `LOGIN SUPERUSER`
 		}
 
+		// The alternate role of a user acts as that user with a password of
+		// its own. It exists only when there is a verifier for it. An existing
+		// alternate role without a verifier has its password removed.
+		var alternate, alternateVerifier any
+		if v, ok := alternates[string(spec.Name)]; ok && spec.Name != "postgres" {
+			alternate = AlternateUserName(string(spec.Name))
+			if v != "" {
+				alternateVerifier = v
+			}
+		}
+
 		if err == nil {
 			err = encoder.Encode(map[string]any{
-				"databases": databases,
-				"options":   options,
-				"username":  spec.Name,
-				"verifier":  verifiers[string(spec.Name)],
+				"alternate":          alternate,
+				"alternate_verifier": alternateVerifier,
+				"databases":          databases,
+				"member_of":          memberOf,
+				"options":            options,
+				"username":           spec.Name,
+				"verifier":           verifiers[string(spec.Name)],
 			})
 		}
 	}
@@ -141,6 +181,40 @@ SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
        pg_catalog.json_extract_path_text(input.data, 'verifier'))
   FROM input ORDER BY input.id
 \gexec
+`)
+
+	// Create alternate roles that do not already exist as members of their
+	// users, then set their options and passwords. Each alternate role assumes
+	// its user in every session so that objects it creates belong to its user.
+	// - https://www.postgresql.org/docs/current/role-membership.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('CREATE USER %I IN ROLE %I',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'alternate_verifier') IS NOT NULL
+   AND NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_roles
+       WHERE rolname = pg_catalog.json_extract_path_text(input.data, 'alternate'))
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'options'),
+       pg_catalog.json_extract_path_text(input.data, 'alternate_verifier'))
+  FROM input JOIN pg_catalog.pg_roles
+    ON rolname = pg_catalog.json_extract_path_text(input.data, 'alternate')
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('ALTER ROLE %I SET role TO %I',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'alternate_verifier') IS NOT NULL
+ ORDER BY input.id
+\gexec
 `)
 
 	// Grant access to any specified databases.
@@ -153,6 +227,19 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
        pg_catalog.json_extract_path_text(input.data, 'username'))
   FROM input ORDER BY input.id
 \gexec
+`)
+
+	// Grant membership in any specified roles that exist.
+	// - https://www.postgresql.org/docs/current/sql-grant.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('GRANT %I TO %I',
+       rolname, pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input, pg_catalog.json_array_elements_text(
+       pg_catalog.json_extract_path(
+       pg_catalog.json_strip_nulls(input.data), 'member_of')) AS roles (member_of)
+  JOIN pg_catalog.pg_roles ON rolname = roles.member_of
+ ORDER BY input.id
+\gexec
 `)
 
 	// Commit (finish) the transaction.
@@ -177,6 +264,130 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
 		}
 	}
 
+	// Revoke and grant privileges in specified databases. This happens in
+	// each database, so it is not part of the transaction above.
+	if err == nil {
+		err = WriteUserGrantsInPostgreSQL(ctx, exec, users)
+	}
+
+	return err
+}
+
+// ReclaimUsersInPostgreSQL calls exec to disable or drop users that have been
+// removed from the spec along with their alternate roles. Revoked users cannot
+// login and have no access to any database. They keep the objects they own and
+// their privileges on objects inside databases. Dropped users have the objects
+// they own in every database reassigned to the "postgres" user.
+func ReclaimUsersInPostgreSQL(
+	ctx context.Context, exec Executor, revoke, drop []string,
+) error {
+	log := logging.FromContext(ctx)
+
+	// Pair each user with the name of its alternate role. Roles that do not
+	// exist are ignored. An alternate role is included only when it is still a
+	// member of its user, the way WriteUsersInPostgreSQL creates it. Names in
+	// the spec never match an alternate role; see [AlternateUserName].
+	roles := func(names []string) string {
+		all := make(map[string]string, len(names))
+		for _, name := range names {
+			all[name] = AlternateUserName(name)
+		}
+		b, _ := json.Marshal(all)
+		return string(b)
+	}
+
+	// Prevent unexpected dereferences by emptying "search_path". The "pg_catalog"
+	// schema is still searched, and only temporary objects can be created.
+	// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+	// - https://www.postgresql.org/docs/current/catalog-pg-auth-members.html
+	const prefix = `SET search_path TO '';
+CREATE TEMPORARY TABLE input AS
+SELECT r.rolname
+  FROM pg_catalog.json_each_text(:'roles') AS names (username, alternate)
+  JOIN pg_catalog.pg_roles AS r
+    ON r.rolname = names.username
+    OR (r.rolname = names.alternate AND EXISTS (
+       SELECT 1 FROM pg_catalog.pg_auth_members
+         JOIN pg_catalog.pg_roles AS u ON u.oid = roleid
+        WHERE member = r.oid AND u.rolname = names.username))
+ WHERE names.username <> 'postgres';
+`
+
+	var err error
+	if len(revoke) > 0 {
+		// Remove the password and LOGIN option, end any sessions, then revoke
+		// access to every database.
+		// - https://www.postgresql.org/docs/current/sql-alterrole.html
+		// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADMIN-SIGNAL
+		// - https://www.postgresql.org/docs/current/sql-revoke.html
+		var stdout, stderr string
+		stdout, stderr, err = exec.Exec(ctx, strings.NewReader(prefix+`
+SELECT pg_catalog.format('ALTER ROLE %I WITH NOLOGIN PASSWORD NULL', rolname)
+  FROM input ORDER BY rolname
+\gexec
+
+SELECT pg_catalog.pg_terminate_backend(pid)
+  FROM pg_catalog.pg_stat_activity JOIN input ON usename = rolname
+\g /dev/null
+
+SELECT pg_catalog.format('REVOKE ALL PRIVILEGES ON DATABASE %I FROM %I', datname, rolname)
+  FROM pg_catalog.pg_database, input
+ WHERE datname NOT IN ('template0')
+ ORDER BY datname, rolname
+\gexec
+`),
+			map[string]string{
+				"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+				"QUIET":         "on", // Do not print successful statements to stdout.
+				"roles":         roles(revoke),
+			})
+
+		log.V(1).Info("revoked PostgreSQL users", "stdout", stdout, "stderr", stderr)
+	}
+
+	if err == nil && len(drop) > 0 {
+		// End any sessions, then reassign and drop the objects owned by each
+		// role in every database. Privileges granted to each role are dropped
+		// as well.
+		// - https://www.postgresql.org/docs/current/sql-reassign-owned.html
+		// - https://www.postgresql.org/docs/current/sql-drop-owned.html
+		var stdout, stderr string
+		stdout, stderr, err = exec.ExecInAllDatabases(ctx, prefix+`
+SELECT pg_catalog.pg_terminate_backend(pid)
+  FROM pg_catalog.pg_stat_activity JOIN input ON usename = rolname
+\g /dev/null
+
+SELECT pg_catalog.format('REASSIGN OWNED BY %I TO postgres', rolname),
+       pg_catalog.format('DROP OWNED BY %I', rolname)
+  FROM input ORDER BY rolname
+\gexec
+`,
+			map[string]string{
+				"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+				"QUIET":         "on", // Do not print successful statements to stdout.
+				"roles":         roles(drop),
+			})
+
+		log.V(1).Info("reassigned objects of PostgreSQL users", "stdout", stdout, "stderr", stderr)
+
+		// Roles are shared by all databases; drop them once.
+		// - https://www.postgresql.org/docs/current/sql-droprole.html
+		if err == nil {
+			stdout, stderr, err = exec.Exec(ctx, strings.NewReader(prefix+`
+SELECT pg_catalog.format('DROP ROLE %I', rolname)
+  FROM input ORDER BY rolname
+\gexec
+`),
+				map[string]string{
+					"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+					"QUIET":         "on", // Do not print successful statements to stdout.
+					"roles":         roles(drop),
+				})
+
+			log.V(1).Info("dropped PostgreSQL users", "stdout", stdout, "stderr", stderr)
+		}
+	}
+
 	return err
 }
 
@@ -50,7 +50,7 @@ func TestWriteUsersInPostgreSQL(t *testing.T) {
 		}
 
 		cluster := new(v1beta1.PostgresCluster)
-		assert.Equal(t, expected, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil))
+		assert.Equal(t, expected, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil, nil))
 	})
 
 	t.Run("Empty", func(t *testing.T) {
@@ -84,6 +84,34 @@ SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
   FROM input ORDER BY input.id
 \gexec
 
+SELECT pg_catalog.format('CREATE USER %I IN ROLE %I',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'alternate_verifier') IS NOT NULL
+   AND NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_roles
+       WHERE rolname = pg_catalog.json_extract_path_text(input.data, 'alternate'))
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'options'),
+       pg_catalog.json_extract_path_text(input.data, 'alternate_verifier'))
+  FROM input JOIN pg_catalog.pg_roles
+    ON rolname = pg_catalog.json_extract_path_text(input.data, 'alternate')
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('ALTER ROLE %I SET role TO %I',
+       pg_catalog.json_extract_path_text(input.data, 'alternate'),
+       pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'alternate_verifier') IS NOT NULL
+ ORDER BY input.id
+\gexec
+
 SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
        pg_catalog.json_array_elements_text(
        pg_catalog.json_extract_path(
@@ -91,18 +119,27 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
        pg_catalog.json_extract_path_text(input.data, 'username'))
   FROM input ORDER BY input.id
 \gexec
+
+SELECT pg_catalog.format('GRANT %I TO %I',
+       rolname, pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input, pg_catalog.json_array_elements_text(
+       pg_catalog.json_extract_path(
+       pg_catalog.json_strip_nulls(input.data), 'member_of')) AS roles (member_of)
+  JOIN pg_catalog.pg_roles ON rolname = roles.member_of
+ ORDER BY input.id
+\gexec
 COMMIT;`))
 			return nil
 		}
 
 		cluster := new(v1beta1.PostgresCluster)
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil, nil))
 		assert.Equal(t, calls, 1)
 
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, []v1beta1.PostgresUserSpec{}, nil))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, []v1beta1.PostgresUserSpec{}, nil, nil))
 		assert.Equal(t, calls, 2)
 
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, map[string]string{}))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, map[string]string{}, map[string]string{}))
 		assert.Equal(t, calls, 3)
 	})
 
@@ -118,10 +155,11 @@ COMMIT;`))
 			assert.NilError(t, err)
 			assert.Assert(t, cmp.Contains(string(b), `
 \copy input (data) from stdin with (format text)
-{"databases":["db1"],"options":"","username":"user-no-options","verifier":""}
-{"databases":null,"options":"CREATEDB CREATEROLE","username":"user-no-databases","verifier":""}
-{"databases":null,"options":"","username":"user-with-verifier","verifier":"some$verifier"}
-{"databases":null,"options":"LOGIN","username":"user-invalid-options","verifier":""}
+{"alternate":null,"alternate_verifier":null,"databases":["db1"],"member_of":null,"options":"LOGIN","username":"user-no-options","verifier":""}
+{"alternate":null,"alternate_verifier":null,"databases":null,"member_of":null,"options":"LOGIN CREATEDB CREATEROLE","username":"user-no-databases","verifier":""}
+{"alternate":"user-with-verifier_alt","alternate_verifier":"other$verifier","databases":null,"member_of":null,"options":"LOGIN","username":"user-with-verifier","verifier":"some$verifier"}
+{"alternate":null,"alternate_verifier":null,"databases":null,"member_of":null,"options":"LOGIN","username":"user-invalid-options","verifier":""}
+{"alternate":"user-no-login_alt","alternate_verifier":null,"databases":null,"member_of":null,"options":"NOLOGIN","username":"user-no-login","verifier":""}
 \.
 `))
 			return nil
@@ -144,11 +182,20 @@ COMMIT;`))
 					Name:    "user-invalid-options",
 					Options: "login password 'doot' --",
 				},
+				{
+					Name:    "user-no-login",
+					Options: "nologin",
+				},
 			},
 			map[string]string{
 				"no-user":            "ignored",
 				"user-with-verifier": "some$verifier",
 			},
+			map[string]string{
+				"no-user":            "ignored",
+				"user-no-login":      "",
+				"user-with-verifier": "other$verifier",
+			},
 		))
 		assert.Equal(t, calls, 1)
 	})
@@ -165,7 +212,7 @@ COMMIT;`))
 			assert.NilError(t, err)
 			assert.Assert(t, cmp.Contains(string(b), `
 \copy input (data) from stdin with (format text)
-{"databases":["postgres"],"options":"LOGIN SUPERUSER","username":"postgres","verifier":"allowed"}
+{"alternate":null,"alternate_verifier":null,"databases":["postgres"],"member_of":null,"options":"LOGIN SUPERUSER","username":"postgres","verifier":"allowed"}
 \.
 `))
 			return nil
@@ -182,9 +229,128 @@ COMMIT;`))
 			map[string]string{
 				"postgres": "allowed",
 			},
+			map[string]string{
+				"postgres": "ignored",
+			},
 		))
 		assert.Equal(t, calls, 1)
 	})
+
+	t.Run("Grants", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		var scripts []string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			scripts = append(scripts, strings.Join(command, " ")+"\n"+string(b))
+			return nil
+		}
+
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec,
+			[]v1beta1.PostgresUserSpec{
+				{
+					Name:      "reader",
+					Databases: []v1beta1.PostgresIdentifier{"db1", "db2"},
+					Grants: []v1beta1.PostgresDatabaseGrant{{
+						Database:   "db2",
+						Privileges: []string{"CONNECT"},
+					}},
+					MemberOf: []v1beta1.PostgresIdentifier{"pg_read_all_stats"},
+				},
+				{
+					Name:     "postgres",
+					Grants:   []v1beta1.PostgresDatabaseGrant{{Database: "db1"}},
+					MemberOf: []v1beta1.PostgresIdentifier{"ignored"},
+				},
+			}, nil, nil))
+
+		// Users are written then granted privileges in their databases.
+		assert.Equal(t, len(scripts), 2)
+		assert.Assert(t, cmp.Contains(scripts[0], `
+{"alternate":null,"alternate_verifier":null,"databases":["db1"],"member_of":["pg_read_all_stats"],"options":"LOGIN","username":"reader","verifier":""}
+{"alternate":null,"alternate_verifier":null,"databases":["postgres"],"member_of":null,"options":"LOGIN SUPERUSER","username":"postgres","verifier":""}
+`))
+		assert.Assert(t, cmp.Contains(scripts[1], `--set=username=reader`))
+		assert.Assert(t, cmp.Contains(scripts[1],
+			`"db2":{"database":["REVOKE CREATE, TEMPORARY ON DATABASE %1$I FROM %3$I","GRANT CONNECT ON DATABASE %1$I TO %3$I"],"schemas":[]}`))
+	})
+}
+
+func TestReclaimUsersInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			t.Errorf("unexpected call: %v", command)
+			return nil
+		}
+
+		assert.NilError(t, ReclaimUsersInPostgreSQL(ctx, exec, nil, nil))
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(command, `--set=roles={"one":"one_alt","two":"two_alt"}`))
+			assert.Assert(t, cmp.Contains(string(b), `username <> 'postgres'`))
+			assert.Assert(t, cmp.Contains(string(b), `FROM pg_catalog.pg_auth_members`),
+				"expected only alternate roles that are members of their user")
+			assert.Assert(t, cmp.Contains(string(b), `'ALTER ROLE %I WITH NOLOGIN PASSWORD NULL'`))
+			assert.Assert(t, cmp.Contains(string(b), `'REVOKE ALL PRIVILEGES ON DATABASE %I FROM %I'`))
+			assert.Assert(t, !strings.Contains(string(b), `DROP`))
+			return nil
+		}
+
+		assert.NilError(t, ReclaimUsersInPostgreSQL(ctx, exec, []string{"one", "two"}, nil))
+		assert.Equal(t, calls, 1)
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		var scripts []string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(command, `--set=roles={"three":"three_alt"}`))
+
+			scripts = append(scripts, string(b))
+			return nil
+		}
+
+		assert.NilError(t, ReclaimUsersInPostgreSQL(ctx, exec, nil, []string{"three"}))
+		assert.Equal(t, len(scripts), 2)
+
+		// Objects are reassigned in every database before roles are dropped.
+		assert.Assert(t, cmp.Contains(scripts[0], `'REASSIGN OWNED BY %I TO postgres'`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'DROP OWNED BY %I'`))
+		assert.Assert(t, cmp.Contains(scripts[1], `'DROP ROLE %I'`))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+			return expected
+		}
+
+		assert.Equal(t, expected,
+			ReclaimUsersInPostgreSQL(ctx, exec, []string{"one"}, []string{"two"}))
+		assert.Equal(t, calls, 1, "expected to stop after the first error")
+	})
 }
 
 func TestWriteUsersSchemasInPostgreSQL(t *testing.T) {
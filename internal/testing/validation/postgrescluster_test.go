@@ -11,6 +11,7 @@ import (
 
 	"gotest.tools/v3/assert"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -113,6 +114,45 @@ func TestPostgresUserOptions(t *testing.T) {
 		assert.Equal(t, status.Details.Causes[0].Field, "spec.users[0].options")
 	})
 
+	t.Run("Grants", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: reader,
+			memberOf: [pg_read_all_stats],
+			grants: [{
+				database: app,
+				privileges: [CONNECT],
+				schemas: [{ name: public, privileges: [USAGE], tables: [SELECT], sequences: [SELECT] }],
+			}],
+		}]`), &cluster.Spec.Users))
+
+		assert.NilError(t, cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll))
+
+		cluster.Spec.Users[0].Grants[0].Schemas[0].Tables = []string{"SELECT", "ALL"}
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "spec.users[0].grants[0].schemas[0].tables[1]")
+	})
+
+	t.Run("ReclaimPolicy", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "postgres", ReclaimPolicy: "Drop"},
+			{Name: "removable", ReclaimPolicy: "Revoke"},
+		}
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "must be retained")
+
+		//nolint:errorlint // This is a test, and a panic is unlikely.
+		status := err.(apierrors.APIStatus).Status()
+		assert.Assert(t, status.Details != nil)
+		assert.Equal(t, len(status.Details.Causes), 1)
+		assert.Equal(t, status.Details.Causes[0].Field, "spec.users[0]")
+	})
+
 	t.Run("Valid", func(t *testing.T) {
 		cluster := base.DeepCopy()
 		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
@@ -123,3 +163,613 @@ func TestPostgresUserOptions(t *testing.T) {
 		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
 	})
 }
+
+func TestPostgresUserPasswordRotation(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-user-rotation"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("Interval", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: frequent,
+			password: { type: ASCII, rotation: { interval: 5m } },
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "at least one hour")
+	})
+
+	t.Run("GracePeriod", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: postgres,
+			password: { type: ASCII, rotation: { gracePeriod: 1h } },
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "gracePeriod requires")
+	})
+
+	t.Run("GracePeriodInterval", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app,
+			password: { type: ASCII, rotation: { interval: 24h, gracePeriod: 24h } },
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "less than interval")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app,
+			password: {
+				type: AlphaNumeric,
+				rotation: {
+					interval: 720h,
+					requestedAt: "2024-05-01T00:00:00Z",
+					gracePeriod: 24h,
+				},
+			},
+		}]`), &cluster.Spec.Users))
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+}
+
+func TestPostgresUserPasswordSource(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-user-source"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[
+			{ name: secret, password: { source: { secret: { name: vault, key: app } } } },
+			{ name: file, password: { source: { file: app/password } } },
+		]`), &cluster.Spec.Users))
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app,
+			password: {
+				rotation: { interval: 720h },
+				source: { secret: { name: vault, key: app } },
+			},
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "rotation cannot be used")
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app,
+			password: { source: { file: pw, secret: { name: vault, key: app } } },
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "exactly one of secret or file")
+	})
+
+	t.Run("File", func(t *testing.T) {
+		for _, file := range []string{"/etc/passwd", "../pw", "app/../../pw"} {
+			cluster := base.DeepCopy()
+			cluster.Spec.Users = []v1beta1.PostgresUserSpec{{
+				Name: "app",
+				Password: &v1beta1.PostgresPasswordSpec{
+					Type:   v1beta1.PostgresPasswordTypeASCII,
+					Source: &v1beta1.PostgresPasswordSource{File: file},
+				},
+			}}
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err), "%q", file)
+			assert.ErrorContains(t, err, "relative path")
+		}
+	})
+}
+
+func TestPostgresUserAuthentication(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-user-authentication"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[
+			{ name: cert, authentication: { certificate: { commonNames: [cert.example.com] } } },
+			{ name: gss, authentication: { gss: {
+				principals: [gss@EXAMPLE.COM], keytab: { name: kerberos, key: keytab },
+			} } },
+			{ name: simple, authentication: { ldap: {
+				server: ldap.example.com, scheme: ldaps, prefix: "cn=", suffix: ",dc=example",
+			} } },
+			{ name: search, authentication: { ldap: {
+				server: ldap.example.com, port: 389, startTLS: true, baseDN: "dc=example",
+				bindDN: "cn=pg", bindPassword: { name: ldap, key: password },
+			} } },
+		]`), &cluster.Spec.Users))
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+
+	t.Run("ExactlyOne", func(t *testing.T) {
+		for _, auth := range []string{
+			`{}`,
+			`{ certificate: {}, ldap: { server: ldap.example.com, baseDN: "dc=example" } }`,
+		} {
+			cluster := base.DeepCopy()
+			assert.NilError(t, yaml.Unmarshal([]byte(`[{ name: app, authentication: `+auth+` }]`),
+				&cluster.Spec.Users))
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err), "%s", auth)
+			assert.ErrorContains(t, err, "exactly one of certificate, gss, or ldap")
+		}
+	})
+
+	t.Run("GSS", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app, authentication: { gss: { principals: [], keytab: { name: kerberos, key: keytab } } },
+		}]`), &cluster.Spec.Users))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "principals")
+	})
+
+	t.Run("LDAP", func(t *testing.T) {
+		for _, tt := range []struct{ ldap, message string }{
+			{`{ server: ldap.example.com }`, "either baseDN or prefix and suffix"},
+			{`{ server: ldap.example.com, prefix: "cn=", baseDN: "dc=example" }`, "either baseDN or prefix and suffix"},
+			{`{ server: ldap.example.com, prefix: "cn=", bindDN: "cn=pg" }`, "require baseDN"},
+			{`{ server: ldap.example.com, baseDN: "dc=example", scheme: http }`, "scheme"},
+		} {
+			cluster := base.DeepCopy()
+			assert.NilError(t, yaml.Unmarshal([]byte(`[{ name: app, authentication: { ldap: `+tt.ldap+` } }]`),
+				&cluster.Spec.Users))
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err), "%s", tt.ldap)
+			assert.ErrorContains(t, err, tt.message)
+		}
+	})
+}
+
+func TestPostgresDatabases(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-databases"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("Extensions", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[{
+			name: app,
+			extensions: [
+				{ name: pg_stat_statements },
+				{ name: pgcrypto, schema: crypto, version: "1.3" },
+			],
+		}]`), &cluster.Spec.Databases))
+
+		assert.NilError(t, cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll))
+
+		cluster.Spec.Databases[0].Extensions = append(cluster.Spec.Databases[0].Extensions,
+			v1beta1.PostgresExtensionSpec{Name: "pgcrypto"})
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "Duplicate value")
+	})
+
+	t.Run("ReclaimPolicy", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[
+			{ name: app, owner: app, reclaimPolicy: Drop },
+			{ name: postgres, reclaimPolicy: Retain },
+		]`), &cluster.Spec.Databases))
+
+		assert.NilError(t, cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll))
+
+		cluster.Spec.Databases[1].ReclaimPolicy = "Drop"
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "must be retained")
+	})
+
+	t.Run("Locale", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`[
+			{ name: app, encoding: UTF8, locale: C, template: template0 },
+			{ name: icu, localeProvider: icu, icuLocale: en-US, template: template0 },
+		]`), &cluster.Spec.Databases))
+
+		assert.NilError(t, cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll))
+
+		t.Run("ICULocale", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Databases[1].LocaleProvider = "libc"
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err))
+			assert.ErrorContains(t, err, "icuLocale")
+		})
+
+		t.Run("PostgresVersion", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.PostgresVersion = 14
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err))
+			assert.ErrorContains(t, err, "PostgreSQL 15")
+		})
+	})
+}
+
+func TestDatabaseInitSQL(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "database-init-sql"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("Scripts", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			scripts: [
+				{ name: schema, configMap: { name: sql, key: schema.sql } },
+				{ name: data, secret: { name: sql, key: data.sql }, database: app, run: OnChange },
+			],
+		}`), &cluster.Spec.DatabaseInitSQL))
+
+		assert.NilError(t, cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll))
+
+		cluster.Spec.DatabaseInitSQL.Scripts[0].Secret = cluster.Spec.DatabaseInitSQL.Scripts[1].Secret
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "exactly one of configMap or secret")
+	})
+
+	t.Run("NameAndKey", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.DatabaseInitSQL = &v1beta1.DatabaseInitSQL{Name: "sql"}
+
+		err := cc.Create(ctx, cluster.DeepCopy(), client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "name and key must be specified together")
+
+		cluster.Spec.DatabaseInitSQL = &v1beta1.DatabaseInitSQL{}
+
+		err = cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "are required")
+	})
+}
+
+func TestPostgresInstanceConfig(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-instance-config"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	for _, tt := range []struct {
+		parameter string
+		message   string
+	}{
+		{parameter: "data_directory", message: "cannot change file locations"},
+		{parameter: "hba_file", message: "cannot change file locations"},
+		{parameter: "max_connections", message: "must be the same on every instance"},
+		{parameter: "wal_level", message: "must be the same on every instance"},
+	} {
+		t.Run(tt.parameter, func(t *testing.T) {
+			cluster := base.DeepCopy()
+			cluster.Spec.InstanceSets[0].Config = &v1beta1.PostgresInstanceConfig{
+				Parameters: map[string]intstr.IntOrString{
+					tt.parameter: intstr.FromString("x"),
+				},
+			}
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err))
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.InstanceSets[0].Config = &v1beta1.PostgresInstanceConfig{
+			Parameters: map[string]intstr.IntOrString{
+				"max_parallel_workers": intstr.FromInt32(16),
+				"work_mem":             intstr.FromString("256MB"),
+			},
+		}
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+}
+
+func TestPostgresAuthenticationRules(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "postgres-authentication-rules"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	for _, tt := range []struct {
+		name    string
+		rule    string
+		message string
+	}{
+		{name: "Trust", rule: `{ connection: host, method: trust }`, message: `Unsupported value: "trust"`},
+		{name: "UnknownMethod", rule: `{ connection: host, method: kerberos }`, message: `Unsupported value: "kerberos"`},
+		{name: "MethodCase", rule: `{ connection: host, method: MD5 }`, message: `Unsupported value: "MD5"`},
+		{name: "LocalAddress", rule: `{ connection: local, addresses: [10.0.0.0/8], method: peer }`, message: `cannot have addresses`},
+		{name: "Connection", rule: `{ connection: hostx, method: md5 }`, message: `Unsupported value`},
+		{name: "Option", rule: `{ connection: host, method: ldap, options: { "a b": c } }`, message: `option names`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := base.DeepCopy()
+			cluster.Spec.Authentication = new(v1beta1.PostgresAuthenticationSpec)
+			cluster.Spec.Authentication.Rules = make([]v1beta1.PostgresHBARule, 1)
+			assert.NilError(t, yaml.Unmarshal([]byte(tt.rule), &cluster.Spec.Authentication.Rules[0]))
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err))
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			rules: [
+				{ connection: hostssl, databases: [app], users: [app, +readers], addresses: [10.0.0.0/8], method: scram-sha-256 },
+				{ connection: host, method: reject },
+				{ connection: local, method: peer },
+				{ connection: hostssl, method: cert, options: { clientname: DN } },
+				{ connection: host, method: ldap, options: { ldapserver: ldap.example.com } },
+			],
+		}`), &cluster.Spec.Authentication))
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+}
+
+func TestPatroniReplicationSlots(t *testing.T) {
+	ctx := context.Background()
+	cc := require.Kubernetes(t)
+	t.Parallel()
+
+	namespace := require.Namespace(t, cc)
+	base := v1beta1.NewPostgresCluster()
+
+	// Start with a bunch of required fields.
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		postgresVersion: 16,
+		backups: {
+			pgbackrest: {
+				repos: [{ name: repo1 }],
+			},
+		},
+		instances: [{
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 1Mi } },
+			},
+		}],
+	}`), &base.Spec))
+
+	base.Namespace = namespace.Name
+	base.Name = "patroni-replication-slots"
+
+	assert.NilError(t, cc.Create(ctx, base.DeepCopy(), client.DryRunAll),
+		"expected this base cluster to be valid")
+
+	t.Run("SameName", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			logicalSlots: [{ name: cdc, database: app }],
+			physicalSlots: [{ name: cdc }],
+		}`), &cluster.Spec.Patroni))
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err))
+		assert.ErrorContains(t, err, "same name")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			logicalSlots: [{ name: cdc, database: app }],
+			physicalSlots: [{ name: standby }],
+		}`), &cluster.Spec.Patroni))
+
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+}
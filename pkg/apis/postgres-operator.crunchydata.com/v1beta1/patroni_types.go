@@ -4,8 +4,12 @@
 
 package v1beta1
 
-import "k8s.io/apimachinery/pkg/api/resource"
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
+// +kubebuilder:validation:XValidation:rule=`!has(self.logicalSlots) || !has(self.physicalSlots) || !self.logicalSlots.exists(l, self.physicalSlots.exists(p, p.name == l.name))`,message="logical and physical slots cannot have the same name"
 type PatroniSpec struct {
 	// Patroni dynamic configuration settings. Changes to this value will be
 	// automatically reloaded without validation. Changes to certain PostgreSQL
@@ -17,6 +21,22 @@ type PatroniSpec struct {
 	// +kubebuilder:validation:Type=object
 	DynamicConfiguration SchemalessObject `json:"dynamicConfiguration,omitempty"`
 
+	// The kind of Kubernetes object in which Patroni keeps its distributed
+	// configuration and leader lock. ConfigMaps are watched by fewer clients
+	// than Endpoints, which reduces load on the Kubernetes API. This cannot be
+	// changed after the cluster is created.
+	// More info: https://patroni.readthedocs.io/en/latest/kubernetes.html
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=15
+	//
+	// +kubebuilder:validation:Enum={Endpoints,ConfigMaps}
+	// +kubebuilder:validation:XValidation:rule=`self == oldSelf`,message="immutable"
+	// +kubebuilder:default=Endpoints
+	// +optional
+	DistributedConfigurationStore string `json:"dcs,omitempty"`
+
 	// TTL of the cluster leader lock. "Think of it as the
 	// length of time before initiation of the automatic failover process."
 	// Changing this value causes PostgreSQL to restart.
@@ -29,6 +49,39 @@ type PatroniSpec struct {
 	// +optional
 	Logging *PatroniLogConfig `json:"logging,omitempty"`
 
+	// Logical replication slots that Patroni keeps on the leader and copies
+	// to replicas so they survive failover. Declaring any slot enables the
+	// Patroni "postgresql.use_slots" setting, overriding any value in
+	// dynamicConfiguration, so Patroni also keeps a physical slot on the
+	// leader for each replica.
+	// More info: https://patroni.readthedocs.io/en/latest/dynamic_configuration.html
+	// ---
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	LogicalSlots []PatroniLogicalSlot `json:"logicalSlots,omitempty"`
+
+	// Physical replication slots that Patroni keeps on the leader and
+	// replicas so they survive failover. Declaring any slot enables the
+	// Patroni "postgresql.use_slots" setting, overriding any value in
+	// dynamicConfiguration, so Patroni also keeps a physical slot on the
+	// leader for each replica.
+	// More info: https://patroni.readthedocs.io/en/latest/dynamic_configuration.html
+	// ---
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PhysicalSlots []PatroniPhysicalSlot `json:"physicalSlots,omitempty"`
+
+	// Whether or not Patroni is in maintenance mode. While paused, Patroni does
+	// not perform automatic failover and the operator does not perform
+	// switchovers or redeploy instances.
+	// More info: https://patroni.readthedocs.io/en/latest/pause.html
+	// +optional
+	Pause *bool `json:"pause,omitempty"`
+
 	// The port on which Patroni should listen.
 	// Changing this value causes PostgreSQL to restart.
 	// +optional
@@ -44,16 +97,24 @@ type PatroniSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	SyncPeriodSeconds *int32 `json:"syncPeriodSeconds,omitempty"`
 
+	// Reinitialize gives options to rebuild a replica in a PostgresCluster.
+	// +optional
+	Reinitialize *PatroniReinitialize `json:"reinitialize,omitempty"`
+
 	// Switchover gives options to perform ad hoc switchovers in a PostgresCluster.
 	// +optional
 	Switchover *PatroniSwitchover `json:"switchover,omitempty"`
 
-	// TODO(cbandy): Add UseConfigMaps bool, default false.
 	// TODO(cbandy): Allow other DCS: etcd, raft, etc?
-	// N.B. changing this will cause downtime.
-	// - https://patroni.readthedocs.io/en/latest/kubernetes.html
+	// N.B. Patroni does not label Pods with their role when using these.
 }
 
+// PatroniSpec DCS kinds.
+const (
+	PatroniDCSConfigMaps = "ConfigMaps"
+	PatroniDCSEndpoints  = "Endpoints"
+)
+
 type PatroniLogConfig struct {
 
 	// Limits the total amount of space taken by Patroni log files.
@@ -78,6 +139,51 @@ type PatroniLogConfig struct {
 	Level *string `json:"level,omitempty"`
 }
 
+// PatroniInstanceSetSpec defines Patroni settings for every instance in an
+// instance set. These become Patroni "tags" on each instance.
+// More info: https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+type PatroniInstanceSetSpec struct {
+
+	// The priority with which instances of this set are chosen during automatic
+	// failover. Instances with higher values are preferred. Zero prevents these
+	// instances from ever becoming primary, like noFailover.
+	// Patroni applies changes to this value without restarting PostgreSQL.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailoverPriority *int32 `json:"failoverPriority,omitempty"`
+
+	// Whether or not to prevent instances of this set from becoming primary
+	// during automatic failover or switchover.
+	// Patroni applies changes to this value without restarting PostgreSQL.
+	// +optional
+	NoFailover *bool `json:"noFailover,omitempty"`
+
+	// Whether or not to exclude instances of this set from the replica Service.
+	// Patroni applies changes to this value without restarting PostgreSQL.
+	// +optional
+	NoLoadBalance *bool `json:"noLoadBalance,omitempty"`
+
+	// Whether or not to prevent instances of this set from becoming synchronous
+	// replicas when synchronous replication is enabled.
+	// Patroni applies changes to this value without restarting PostgreSQL.
+	// +optional
+	NoSync *bool `json:"noSync,omitempty"`
+}
+
+type PatroniReinitialize struct {
+
+	// Whether or not the operator should allow replicas to be reinitialized
+	// in a PostgresCluster
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// The replica instance to reinitialize. Patroni removes its data directory
+	// and creates it again from the primary or from a pgBackRest repository.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	TargetInstance string `json:"targetInstance"`
+}
+
 type PatroniSwitchover struct {
 
 	// Whether or not the operator should allow switchovers in a PostgresCluster
@@ -118,6 +224,9 @@ const (
 // - Patroni's API port
 // - Frequency of syncing with Kube API
 func (s *PatroniSpec) Default() {
+	if s.DistributedConfigurationStore == "" {
+		s.DistributedConfigurationStore = PatroniDCSEndpoints
+	}
 	if s.LeaderLeaseDurationSeconds == nil {
 		s.LeaderLeaseDurationSeconds = new(int32)
 		*s.LeaderLeaseDurationSeconds = 30
@@ -142,6 +251,46 @@ type PatroniStatus struct {
 	// +optional
 	SystemIdentifier string `json:"systemIdentifier,omitempty"`
 
+	// The instance most recently observed as the Patroni leader.
+	// +optional
+	Leader string `json:"leader,omitempty"`
+
+	// The leader that the operator most recently asked Patroni to replace.
+	// It is cleared when the leader changes.
+	// +optional
+	LeaderChangeRequested string `json:"leaderChangeRequested,omitempty"`
+
+	// The most recent changes of the Patroni leader, oldest first.
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=atomic
+	// +optional
+	LeaderHistory []PatroniLeaderChange `json:"leaderHistory,omitempty"`
+
+	// The permanent logical replication slots as observed in each instance.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	LogicalSlots []PatroniLogicalSlotStatus `json:"logicalSlots,omitempty"`
+
+	// Whether or not Patroni is in maintenance mode according to its
+	// distributed configuration.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
+	// The permanent physical replication slots as observed on the leader.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PhysicalSlots []PatroniPhysicalSlotStatus `json:"physicalSlots,omitempty"`
+
+	// Tracks the execution of the reinitialize requests.
+	// +optional
+	Reinitialize *string `json:"reinitialize,omitempty"`
+
+	// Tracks the most recent reinitialize request until it succeeds.
+	// +optional
+	ReinitializeProgress *PatroniReinitializeProgress `json:"reinitializeProgress,omitempty"`
+
 	// Tracks the execution of the switchover requests.
 	// +optional
 	Switchover *string `json:"switchover,omitempty"`
@@ -149,4 +298,165 @@ type PatroniStatus struct {
 	// Tracks the current timeline during switchovers
 	// +optional
 	SwitchoverTimeline *int64 `json:"switchoverTimeline,omitempty"`
+
+	// The PostgreSQL timeline most recently observed in Patroni's
+	// distributed configuration.
+	// +optional
+	Timeline int64 `json:"timeline,omitempty"`
+}
+
+const (
+	PatroniLeaderChangeFailover   = "Failover"
+	PatroniLeaderChangeSwitchover = "Switchover"
+	PatroniLeaderChangeUnknown    = "Unknown"
+)
+
+// PatroniLogicalSlot describes a permanent logical replication slot.
+type PatroniLogicalSlot struct {
+
+	// The name of the replication slot.
+	// More info: https://www.postgresql.org/docs/current/warm-standby.html#STREAMING-REPLICATION-SLOTS-MANIPULATION
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]+$`
+	// +required
+	Name string `json:"name"`
+
+	// The database in which the slot decodes changes.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Database string `json:"database"`
+
+	// The output plugin that formats decoded changes.
+	// +kubebuilder:default=pgoutput
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// PatroniPhysicalSlot describes a permanent physical replication slot.
+type PatroniPhysicalSlot struct {
+
+	// The name of the replication slot.
+	// More info: https://www.postgresql.org/docs/current/warm-standby.html#STREAMING-REPLICATION-SLOTS-MANIPULATION
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]+$`
+	// +required
+	Name string `json:"name"`
+
+	// The amount of WAL the slot can retain on the leader before the
+	// "ReplicationSlotsRetainingWAL" condition warns about it. The slot keeps
+	// retaining WAL beyond this amount.
+	// More info: https://kubernetes.io/docs/reference/kubernetes-api/common-definitions/quantity
+	// +optional
+	RetainedWALThreshold *resource.Quantity `json:"retainedWALThreshold,omitempty"`
+}
+
+// PatroniPhysicalSlotStatus describes a permanent physical replication slot
+// as observed on the leader.
+type PatroniPhysicalSlotStatus struct {
+
+	// The name of the replication slot.
+	// +required
+	Name string `json:"name"`
+
+	// Whether or not a consumer is streaming from the slot.
+	// +optional
+	Active bool `json:"active,omitempty"`
+
+	// The number of bytes of WAL that the slot retains on the leader, rounded
+	// down to a multiple of 16MiB.
+	// +optional
+	RetainedWALBytes *int64 `json:"retainedWALBytes,omitempty"`
+}
+
+// PatroniLogicalSlotStatus describes a permanent logical replication slot as
+// observed in each instance.
+type PatroniLogicalSlotStatus struct {
+
+	// The name of the replication slot.
+	// +required
+	Name string `json:"name"`
+
+	// The position up to which the consumer of the slot has confirmed
+	// receiving changes on the leader.
+	// +optional
+	ConfirmedFlushLSN string `json:"confirmedFlushLSN,omitempty"`
+
+	// The number of bytes of WAL written on the leader since the position
+	// confirmed by the consumer of the slot, rounded down to a multiple of 16MiB.
+	// +optional
+	LagBytes *int64 `json:"lagBytes,omitempty"`
+
+	// Instances on which the slot does not exist. The slot is lost when one of
+	// these becomes the leader.
+	// +listType=atomic
+	// +optional
+	MissingInstances []string `json:"missingInstances,omitempty"`
+}
+
+// PatroniLeaderChange describes a change of the Patroni leader observed by
+// the operator.
+type PatroniLeaderChange struct {
+
+	// When the change was observed.
+	// +required
+	Time metav1.Time `json:"time"`
+
+	// The instance that was the leader before the change, if known.
+	// +optional
+	PreviousLeader string `json:"previousLeader,omitempty"`
+
+	// The instance that became the leader.
+	// +required
+	Leader string `json:"leader"`
+
+	// The PostgreSQL timeline of the leader after the change, if known.
+	// +optional
+	Timeline int64 `json:"timeline,omitempty"`
+
+	// Whether the previous leader stepped down while healthy (Switchover),
+	// was replaced while unhealthy (Failover), or neither could be determined.
+	// +kubebuilder:validation:Enum={Failover,Switchover,Unknown}
+	// +required
+	Reason string `json:"reason"`
 }
+
+// PatroniReinitializeProgress describes a replica that Patroni is creating
+// again.
+type PatroniReinitializeProgress struct {
+
+	// The value of the annotation that requested this reinitialize.
+	// +required
+	Request string `json:"request"`
+
+	// The instance being reinitialized.
+	// +required
+	Instance string `json:"instance"`
+
+	// When Patroni was asked to reinitialize the instance.
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+
+	// When PostgreSQL of the instance started before this request, as reported
+	// by Patroni. The request succeeds once PostgreSQL starts again.
+	// +optional
+	PostmasterStartTime string `json:"postmasterStartTime,omitempty"`
+
+	// The state of this request. A request that fails or is rejected is not
+	// tried again; change the annotation to make another request.
+	// +kubebuilder:validation:Enum={Running,Failed,Rejected}
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Details about a request that failed or was rejected.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	PatroniReinitializeRunning  = "Running"
+	PatroniReinitializeFailed   = "Failed"
+	PatroniReinitializeRejected = "Rejected"
+)
@@ -4,6 +4,11 @@
 
 package v1beta1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // PostgreSQL identifiers are limited in length but may contain any character.
 // More info: https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-SYNTAX-IDENTIFIERS
 //
@@ -11,6 +16,100 @@ package v1beta1
 // +kubebuilder:validation:MaxLength=63
 type PostgresIdentifier string
 
+type PostgresAuthenticationSpec struct {
+	// PostgreSQL compares every new connection to these rules in the order they
+	// are defined, after the rules the operator requires. The first rule that
+	// matches determines if and how the connection must authenticate. When this
+	// is omitted or empty, the rules in spec.patroni.dynamicConfiguration apply
+	// or, without those, encrypted connections with a password are allowed.
+	// More info: https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	// ---
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	Rules []PostgresHBARule `json:"rules,omitempty"`
+}
+
+// PostgresAuthenticationStatus describes the authentication rules of a cluster.
+type PostgresAuthenticationStatus struct {
+	// Rules in spec.authentication.rules that were not applied, each followed
+	// by the reason.
+	// +listType=atomic
+	// +optional
+	Rejected []string `json:"rejected,omitempty"`
+}
+
+// PostgresHBARule is one or more records of the "pg_hba.conf" file. It is one
+// record for every address.
+// ---
+// +kubebuilder:validation:XValidation:rule=`self.connection != "local" || !has(self.addresses)`,message=`"local" connections cannot have addresses`
+// +structType=atomic
+type PostgresHBARule struct {
+	// The kind of connection this rule matches. "local" matches Unix-domain
+	// sockets. "host" matches TCP/IP connections with or without encryption;
+	// "hostssl" and "hostnossl" match them with and without TLS; "hostgssenc"
+	// and "hostnogssenc" match them with and without GSSAPI encryption.
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=15
+	//
+	// +kubebuilder:validation:Enum={local,host,hostssl,hostnossl,hostgssenc,hostnogssenc}
+	// +required
+	Connection string `json:"connection"`
+
+	// The databases this rule matches. The keywords "all", "sameuser",
+	// "samerole", and "replication" have their special meaning in PostgreSQL.
+	// When omitted or empty, this rule matches all databases.
+	// ---
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	Databases []PostgresIdentifier `json:"databases,omitempty"`
+
+	// The users this rule matches. A name that begins with "+" matches every
+	// member of that role. When omitted or empty, this rule matches all users.
+	// ---
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	Users []PostgresIdentifier `json:"users,omitempty"`
+
+	// The client IP addresses this rule matches in CIDR notation, e.g.
+	// "10.0.0.0/8" or "fd00::/8". When omitted or empty, this rule matches
+	// all addresses.
+	// ---
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:MaxLength=43
+	// +kubebuilder:validation:items:Pattern=`^[0-9a-fA-F:.]+/[0-9]+$`
+	// +listType=atomic
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// The authentication method to use when a connection matches this rule.
+	// The special value "reject" refuses connections that match this rule.
+	// The "trust" method is not allowed.
+	// More info: https://www.postgresql.org/docs/current/auth-methods.html
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=15
+	//
+	// +kubebuilder:validation:Enum={reject,scram-sha-256,md5,password,gss,sspi,ident,peer,ldap,radius,cert,pam,bsd}
+	// +required
+	Method string `json:"method"`
+
+	// Options of the authentication method.
+	// More info: https://www.postgresql.org/docs/current/auth-methods.html
+	// ---
+	// +kubebuilder:validation:MaxProperties=20
+	// +kubebuilder:validation:XValidation:rule=`self.all(k, k.matches("^[a-zA-Z_]+$"))`,message=`option names contain only letters and underscores`
+	// +mapType=atomic
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule=`!has(self.source) || !has(self.rotation)`,message="rotation cannot be used with a password source"
 type PostgresPasswordSpec struct {
 	// Type of password to generate. Defaults to ASCII. Valid options are ASCII
 	// and AlphaNumeric.
@@ -24,6 +123,211 @@ type PostgresPasswordSpec struct {
 	// +kubebuilder:default=ASCII
 	// +kubebuilder:validation:Enum={ASCII,AlphaNumeric}
 	Type string `json:"type"`
+
+	// When and how to replace the generated password.
+	// +optional
+	Rotation *PostgresPasswordRotationSpec `json:"rotation,omitempty"`
+
+	// A password that is managed outside of PGO. When set, no password is
+	// generated and the Secret of this user contains neither a password nor
+	// connection URIs; PGO reads the password only to compute its verifier.
+	// +optional
+	Source *PostgresPasswordSource `json:"source,omitempty"`
+}
+
+// PostgresPasswordSource is where to read a password that is managed outside
+// of PGO. PGO never writes to it.
+// ---
+// +kubebuilder:validation:XValidation:rule=`has(self.secret) != has(self.file)`,message="exactly one of secret or file is required"
+type PostgresPasswordSource struct {
+	// A Secret key, in the same namespace as the cluster, that contains the
+	// password. The Secret can be maintained by a tool such as External Secrets.
+	// +optional
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// The path of a file that contains the password, relative to a directory
+	// named for the namespace of the cluster inside the PGO_PASSWORD_DIRECTORY
+	// environment variable of PGO. That directory can be mounted into PGO by
+	// a CSI driver, such as the Secrets Store CSI Driver. The entire file,
+	// without one trailing newline, is the password. PGO reads the file again
+	// every minute.
+	// ---
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:XValidation:rule=`!self.startsWith("/") && !self.matches("(^|/)[.][.](/|$)")`,message="must be a relative path that does not contain '..'"
+	// +optional
+	File string `json:"file,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule=`!has(self.interval) || !has(self.gracePeriod) || duration(self.gracePeriod) < duration(self.interval)`,message="gracePeriod must be less than interval"
+type PostgresPasswordRotationSpec struct {
+	// How often to generate a new password, e.g. "720h". When omitted, a new
+	// password is generated only by setting requestedAt.
+	// ---
+	// +kubebuilder:validation:XValidation:rule=`duration(self) >= duration("1h")`,message=`must be at least one hour`
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Setting or changing this value generates a new password when the
+	// current one was generated before this time. A time in the future
+	// generates a new password when that time arrives.
+	// +optional
+	RequestedAt *metav1.Time `json:"requestedAt,omitempty"`
+
+	// How long the previous password continues to work after a new one is
+	// generated. The passwords belong to two roles that act as this user: the
+	// user itself and one with "_alt" appended to its name. Each new password
+	// goes to the role that does not have the previous one, and the "user"
+	// key of the Secret names that role. This must be less than interval.
+	// ---
+	// +kubebuilder:validation:XValidation:rule=`duration(self) >= duration("0s")`,message=`cannot be negative`
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// GetRotation returns the rotation policy of s, if any.
+func (s *PostgresPasswordSpec) GetRotation() *PostgresPasswordRotationSpec {
+	if s == nil {
+		return nil
+	}
+	return s.Rotation
+}
+
+// GetSource returns the external source of s, if any.
+func (s *PostgresPasswordSpec) GetSource() *PostgresPasswordSource {
+	if s == nil {
+		return nil
+	}
+	return s.Source
+}
+
+// PostgresUserAuthenticationSpec is a way for a user to authenticate other
+// than with a password.
+// ---
+// +kubebuilder:validation:XValidation:rule=`[has(self.certificate), has(self.gss), has(self.ldap)].filter(x, x).size() == 1`,message="exactly one of certificate, gss, or ldap is required"
+type PostgresUserAuthenticationSpec struct {
+	// Authenticate with a TLS client certificate. PGO issues a certificate
+	// for this user and stores it in the Secret of this user as "tls.crt" and
+	// "tls.key" with its authority in "ca.crt". PostgreSQL trusts that
+	// certificate only when the cluster uses the authority generated by PGO.
+	// More info: https://www.postgresql.org/docs/current/auth-cert.html
+	// +optional
+	Certificate *PostgresCertificateAuthentication `json:"certificate,omitempty"`
+
+	// Authenticate with Kerberos through GSSAPI. The Kerberos configuration
+	// goes in spec.config.files as "krb5.conf".
+	// More info: https://www.postgresql.org/docs/current/gssapi-auth.html
+	// +optional
+	GSS *PostgresGSSAuthentication `json:"gss,omitempty"`
+
+	// Authenticate with an LDAP server.
+	// More info: https://www.postgresql.org/docs/current/auth-ldap.html
+	// +optional
+	LDAP *PostgresLDAPAuthentication `json:"ldap,omitempty"`
+}
+
+type PostgresCertificateAuthentication struct {
+	// Other certificate common names (CN) that map to this user. The
+	// certificate issued by PGO has the name of this user as its CN.
+	// ---
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=64
+	// +kubebuilder:validation:items:Pattern=`^[^/]`
+	// +optional
+	CommonNames []string `json:"commonNames,omitempty"`
+}
+
+type PostgresGSSAuthentication struct {
+	// Kerberos principals, including their realm, that map to this user,
+	// e.g. "alice@EXAMPLE.COM".
+	// ---
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=256
+	// +kubebuilder:validation:items:Pattern=`^[^/]`
+	// +required
+	Principals []string `json:"principals"`
+
+	// A Secret key that contains the keytab of the PostgreSQL service
+	// principal. It is mounted into every instance as "krb5.keytab" next to
+	// the files of spec.config.files. Users with GSS authentication should
+	// all refer to the same keytab; only the first is mounted.
+	// +required
+	Keytab corev1.SecretKeySelector `json:"keytab"`
+}
+
+// PostgresLDAPAuthentication configures either simple bind, with prefix and
+// suffix, or search+bind, with baseDN.
+// ---
+// +kubebuilder:validation:XValidation:rule=`has(self.baseDN) != (has(self.prefix) || has(self.suffix))`,message="either baseDN or prefix and suffix are required"
+// +kubebuilder:validation:XValidation:rule=`has(self.baseDN) || !(has(self.bindDN) || has(self.bindPassword) || has(self.searchAttribute))`,message="bindDN, bindPassword, and searchAttribute require baseDN"
+type PostgresLDAPAuthentication struct {
+	// Names or IP addresses of LDAP servers, separated by spaces.
+	// ---
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=512
+	// +required
+	Server string `json:"server"`
+
+	// Port number on the LDAP server. Defaults to the port of scheme.
+	// ---
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// Set to "ldaps" to use LDAP over TLS. The certificate authority of the
+	// LDAP server can go in spec.config.files as "ldap/ca.crt".
+	// ---
+	// +kubebuilder:validation:Enum={ldap,ldaps}
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+
+	// Whether to encrypt the connection to the LDAP server with StartTLS.
+	// +optional
+	StartTLS *bool `json:"startTLS,omitempty"`
+
+	// Simple bind: the distinguished name to bind as is prefix, then the
+	// name of this user, then suffix.
+	// ---
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+
+	// Search+bind: the directory to search for the distinguished name of
+	// this user.
+	// ---
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	BaseDN string `json:"baseDN,omitempty"`
+
+	// The distinguished name to bind as when searching. Defaults to an
+	// anonymous bind.
+	// ---
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+
+	// A Secret key that contains the password of bindDN. The password is
+	// written to a Secret that pg_hba.conf includes, not to the configuration
+	// of Patroni. This requires PostgreSQL 16 or later.
+	// +optional
+	BindPassword *corev1.SecretKeySelector `json:"bindPassword,omitempty"`
+
+	// The attribute to match against the name of this user when searching.
+	// Defaults to "uid".
+	// ---
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	SearchAttribute string `json:"searchAttribute,omitempty"`
 }
 
 // PostgresPasswordSpec types.
@@ -32,6 +336,9 @@ const (
 	PostgresPasswordTypeASCII        = "ASCII"
 )
 
+// The "_alt" role of a grace period must fit in a PostgreSQL identifier.
+// +kubebuilder:validation:XValidation:rule=`!has(self.password) || !has(self.password.rotation) || !has(self.password.rotation.gracePeriod) || (self.name != "postgres" && size(self.name) <= 59)`,message=`gracePeriod requires a name other than "postgres" that is at most 59 characters`
+// +kubebuilder:validation:XValidation:rule=`!has(self.reclaimPolicy) || self.reclaimPolicy == "Retain" || self.name != "postgres"`,message=`the "postgres" user must be retained`
 type PostgresUserSpec struct {
 
 	// This value goes into the name of a corev1.Secret and a label value, so
@@ -61,7 +368,277 @@ type PostgresUserSpec struct {
 	// +optional
 	Options string `json:"options,omitempty"`
 
+	// Privileges of this user in particular databases. When a database is also
+	// in the list of databases, these privileges replace ALL. Privileges that
+	// are not listed in a grant are revoked. Removing a database or schema from
+	// this list does NOT revoke privileges there. This field is ignored for the
+	// "postgres" user.
+	// More info: https://www.postgresql.org/docs/current/ddl-priv.html
+	// +listType=map
+	// +listMapKey=database
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Grants []PostgresDatabaseGrant `json:"grants,omitempty"`
+
+	// Roles in which this user is a member. Roles that do not exist are
+	// ignored. Removing a role from this list does NOT revoke membership.
+	// This field is ignored for the "postgres" user.
+	// More info: https://www.postgresql.org/docs/current/role-membership.html
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	MemberOf []PostgresIdentifier `json:"memberOf,omitempty"`
+
 	// Properties of the password generated for this user.
 	// +optional
 	Password *PostgresPasswordSpec `json:"password,omitempty"`
+
+	// How this user authenticates over TCP instead of with its password. PGO
+	// writes HBA rules for this user ahead of those in spec.authentication.
+	// This field is ignored for the "postgres" user.
+	// +optional
+	Authentication *PostgresUserAuthenticationSpec `json:"authentication,omitempty"`
+
+	// What happens to this user after it is removed from the list of users.
+	// "Retain" leaves the user and its access in PostgreSQL. "Revoke" prevents
+	// the user from logging in and revokes its access to every database. The
+	// user keeps the objects it owns and its privileges on objects inside
+	// databases. "Drop" reassigns the objects it owns in every database to the
+	// "postgres" user then drops it. The Secret of the user is deleted once
+	// this is done.
+	// Defaults to Retain.
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=10
+	//
+	// +kubebuilder:validation:Enum={Retain,Revoke,Drop}
+	// +optional
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule=`!has(self.reclaimPolicy) || self.reclaimPolicy == "Retain" || !(self.name in ["postgres", "template0", "template1"])`,message=`this database must be retained`
+// +kubebuilder:validation:XValidation:rule=`!has(self.icuLocale) || (has(self.localeProvider) && self.localeProvider == "icu")`,message=`icuLocale requires the "icu" localeProvider`
+type PostgresDatabaseSpec struct {
+	// The name of a database.
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// The role that owns the database. When the role does not exist yet, it
+	// becomes the owner once it does. Defaults to the "postgres" user.
+	// +optional
+	Owner PostgresIdentifier `json:"owner,omitempty"`
+
+	// The maximum number of concurrent connections to the database. The value
+	// -1 means no limit. When omitted, any existing limit is removed.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int32 `json:"connectionLimit,omitempty"`
+
+	// The character set of the database. This is used only when creating the
+	// database and usually requires the "template0" template.
+	// More info: https://www.postgresql.org/docs/current/multibyte.html
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The collation and character classification of the database. This is used
+	// only when creating the database and usually requires the "template0"
+	// template.
+	// More info: https://www.postgresql.org/docs/current/locale.html
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// The library that provides the collation of the database. This is used
+	// only when creating the database. Requires PostgreSQL 15 or later.
+	// More info: https://www.postgresql.org/docs/current/locale.html#LOCALE-PROVIDERS
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=4
+	//
+	// +kubebuilder:validation:Enum={icu,libc}
+	// +optional
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
+	// The ICU collation of the database when the localeProvider is "icu".
+	// This is used only when creating the database.
+	// More info: https://www.postgresql.org/docs/current/collation.html#ICU-COLLATIONS
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	ICULocale string `json:"icuLocale,omitempty"`
+
+	// The database to copy when creating this one. Defaults to "template1".
+	// +optional
+	Template PostgresIdentifier `json:"template,omitempty"`
+
+	// The tablespace of the database. The database is not created until the
+	// tablespace exists. This is used only when creating the database.
+	// +optional
+	Tablespace PostgresIdentifier `json:"tablespace,omitempty"`
+
+	// What happens to this database after it is removed from the list of
+	// databases. "Retain" leaves the database in PostgreSQL. "Drop" ends its
+	// sessions and drops it, unless some user still lists it. Defaults to Retain.
+	// ---
+	// Kubernetes assumes the evaluation cost of an enum value is very large.
+	// TODO(k8s-1.29): Drop MaxLength after Kubernetes 1.29; https://issue.k8s.io/119511
+	// +kubebuilder:validation:MaxLength=10
+	//
+	// +kubebuilder:validation:Enum={Retain,Drop}
+	// +optional
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+
+	// Extensions to create in this database. Each is updated whenever its
+	// version changes, including its default version in a new image. Removing
+	// an extension from this list does NOT drop the extension.
+	// More info: https://www.postgresql.org/docs/current/extend-extensions.html
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Extensions []PostgresExtensionSpec `json:"extensions,omitempty"`
+}
+
+type PostgresExtensionSpec struct {
+	// The name of an extension available in the PostgreSQL image.
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// The schema in which to create the extension's objects. The schema is
+	// created when it does not exist. An existing extension moves to this
+	// schema when it is relocatable. Defaults to the schema the extension
+	// chooses or the first schema in the search_path.
+	// +optional
+	Schema PostgresIdentifier `json:"schema,omitempty"`
+
+	// The version of the extension to install or update to. Defaults to the
+	// default version of the extension in the PostgreSQL image.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// PostgresDatabaseSpec reclaim policies.
+const (
+	PostgresDatabaseReclaimDrop   = "Drop"
+	PostgresDatabaseReclaimRetain = "Retain"
+)
+
+type PostgresDatabaseStatus struct {
+	// The name of a database in spec.databases or one that is being dropped.
+	// +required
+	Name string `json:"name"`
+
+	// The role that owns the database. This is empty when the database does
+	// not exist.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// The maximum number of concurrent connections to the database.
+	// +optional
+	ConnectionLimit *int32 `json:"connectionLimit,omitempty"`
+
+	// The character set of the database.
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The collation of the database.
+	// +optional
+	Collation string `json:"collation,omitempty"`
+
+	// The tablespace of the database.
+	// +optional
+	Tablespace string `json:"tablespace,omitempty"`
+
+	// The reclaim policy of the database when it was last in spec.databases.
+	// +optional
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+
+	// The extensions of the database.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []PostgresExtensionStatus `json:"extensions,omitempty"`
+}
+
+type PostgresExtensionStatus struct {
+	// The name of an extension in spec.databases.
+	// +required
+	Name string `json:"name"`
+
+	// The version in spec.databases, if any.
+	// +optional
+	RequestedVersion string `json:"requestedVersion,omitempty"`
+
+	// The version installed in the database. This is empty when the extension
+	// could not be installed.
+	// +optional
+	InstalledVersion string `json:"installedVersion,omitempty"`
 }
+
+type PostgresDatabaseGrant struct {
+	// The name of a database. Databases that do not exist are ignored.
+	// +required
+	Database PostgresIdentifier `json:"database"`
+
+	// Privileges on the database itself.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum={CONNECT,CREATE,TEMPORARY}
+	// +optional
+	Privileges []string `json:"privileges,omitempty"`
+
+	// Privileges on schemas in the database and the objects in them.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Schemas []PostgresSchemaGrant `json:"schemas,omitempty"`
+}
+
+type PostgresSchemaGrant struct {
+	// The name of a schema. Schemas that do not exist are ignored.
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// Privileges on the schema itself.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum={CREATE,USAGE}
+	// +optional
+	Privileges []string `json:"privileges,omitempty"`
+
+	// Roles that create tables and sequences in the schema. Objects that these
+	// roles create later receive the privileges below. Defaults to the owner
+	// of the schema.
+	// More info: https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Creators []PostgresIdentifier `json:"creators,omitempty"`
+
+	// Privileges on every sequence in the schema, including sequences that
+	// its creators make later.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum={SELECT,UPDATE,USAGE}
+	// +optional
+	Sequences []string `json:"sequences,omitempty"`
+
+	// Privileges on every table in the schema, including tables that its
+	// creators make later.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum={SELECT,INSERT,UPDATE,DELETE,TRUNCATE,REFERENCES,TRIGGER}
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+}
+
+// PostgresUserSpec reclaim policies.
+const (
+	PostgresUserReclaimDrop   = "Drop"
+	PostgresUserReclaimRetain = "Retain"
+	PostgresUserReclaimRevoke = "Revoke"
+)
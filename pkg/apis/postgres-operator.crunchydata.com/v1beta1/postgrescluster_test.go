@@ -45,6 +45,7 @@ spec:
   config: {}
   instances: null
   patroni:
+    dcs: Endpoints
     leaderLeaseDurationSeconds: 30
     port: 8008
     syncPeriodSeconds: 10
@@ -83,6 +84,7 @@ spec:
     replicas: 1
     resources: {}
   patroni:
+    dcs: Endpoints
     leaderLeaseDurationSeconds: 30
     port: 8008
     syncPeriodSeconds: 10
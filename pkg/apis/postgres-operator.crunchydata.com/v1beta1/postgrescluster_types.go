@@ -13,6 +13,7 @@ import (
 )
 
 // PostgresClusterSpec defines the desired state of PostgresCluster
+// +kubebuilder:validation:XValidation:rule=`self.postgresVersion >= 15 || !has(self.databases) || self.databases.all(d, !has(d.localeProvider))`,message=`localeProvider requires PostgreSQL 15 or later`
 type PostgresClusterSpec struct {
 	// +optional
 	Metadata *Metadata `json:"metadata,omitempty"`
@@ -21,6 +22,10 @@ type PostgresClusterSpec struct {
 	// +optional
 	DataSource *DataSource `json:"dataSource,omitempty"`
 
+	// Authentication settings for the PostgreSQL server
+	// +optional
+	Authentication *PostgresAuthenticationSpec `json:"authentication,omitempty"`
+
 	// PostgreSQL backup configuration
 	// +optional
 	Backups Backups `json:"backups,omitempty"`
@@ -46,9 +51,19 @@ type PostgresClusterSpec struct {
 	// +optional
 	CustomReplicationClientTLSSecret *corev1.SecretProjection `json:"customReplicationTLSSecret,omitempty"`
 
+	// Databases to create inside PostgreSQL and the extensions they should
+	// have. Removing a database from this list does NOT drop the database
+	// unless its reclaimPolicy says otherwise.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Databases []PostgresDatabaseSpec `json:"databases,omitempty"`
+
 	// DatabaseInitSQL defines a ConfigMap containing custom SQL that will
-	// be run after the cluster is initialized. This ConfigMap must be in the same
-	// namespace as the cluster.
+	// be run after the cluster is initialized, and ordered scripts that run
+	// once or whenever they change. These ConfigMaps and Secrets must be in
+	// the same namespace as the cluster.
 	// +optional
 	DatabaseInitSQL *DatabaseInitSQL `json:"databaseInitSQL,omitempty"`
 	// Whether or not the PostgreSQL cluster should use the defined default
@@ -147,6 +162,13 @@ type PostgresClusterSpec struct {
 	// +optional
 	ReplicaService *ServiceSpec `json:"replicaService,omitempty"`
 
+	// Setting or changing this value restarts PostgreSQL in every instance
+	// that started before this time, one at a time: replicas first, then the
+	// primary after a switchover. Fenced and stopped instances are skipped,
+	// and a time in the future is ignored.
+	// +optional
+	RestartRequestedAt *metav1.Time `json:"restartRequestedAt,omitempty"`
+
 	// Whether or not the PostgreSQL cluster should be stopped.
 	// When this is true, workloads are scaled to zero and CronJobs
 	// are suspended.
@@ -158,6 +180,11 @@ type PostgresClusterSpec struct {
 	// +optional
 	Standby *PostgresStandbySpec `json:"standby,omitempty"`
 
+	// Fail over between this cluster and a peer in another Kubernetes cluster.
+	// This field requires enabling the DisasterRecovery feature gate.
+	// +optional
+	DisasterRecovery *DisasterRecoverySpec `json:"disasterRecovery,omitempty"`
+
 	// A list of group IDs applied to the process of a container. These can be
 	// useful when accessing shared file systems with constrained permissions.
 	// More info: https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/pod-v1/#security-context
@@ -177,7 +204,8 @@ type PostgresClusterSpec struct {
 	// Users to create inside PostgreSQL and the databases they should access.
 	// The default creates one user that can access one database matching the
 	// PostgresCluster name. An empty list creates no users. Removing a user
-	// from this list does NOT drop the user nor revoke their access.
+	// from this list does NOT drop the user nor revoke their access unless
+	// its reclaimPolicy says otherwise.
 	// +listType=map
 	// +listMapKey=name
 	// +kubebuilder:validation:MaxItems=64
@@ -241,14 +269,84 @@ type DataSourceVolume struct {
 // DatabaseInitSQL defines a ConfigMap containing custom SQL that will
 // be run after the cluster is initialized. This ConfigMap must be in the same
 // namespace as the cluster.
+// ---
+// +kubebuilder:validation:XValidation:rule=`has(self.name) == has(self.key)`,message="name and key must be specified together"
+// +kubebuilder:validation:XValidation:rule=`has(self.name) || has(self.scripts)`,message="name and key or scripts are required"
 type DatabaseInitSQL struct {
 	// Name is the name of a ConfigMap
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Key is the ConfigMap data key that points to a SQL string
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Scripts to run in order after the SQL in name and key. Each script runs
+	// with ON_ERROR_STOP; when one fails, the scripts after it wait until it
+	// succeeds. Scripts are read from ConfigMaps or Secrets in the same
+	// namespace as the cluster.
+	// ---
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Scripts []DatabaseInitSQLScript `json:"scripts,omitempty"`
+}
+
+// DatabaseInitSQLScript defines one SQL script and where to run it.
+// ---
+// +kubebuilder:validation:XValidation:rule=`has(self.configMap) != has(self.secret)`,message="exactly one of configMap or secret is required"
+type DatabaseInitSQLScript struct {
+	// The name of this script in status. Changing it runs the script again.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
 	// +required
 	Name string `json:"name"`
 
-	// Key is the ConfigMap data key that points to a SQL string
+	// A ConfigMap data key that contains the SQL of this script.
+	// +optional
+	ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
+
+	// A Secret data key that contains the SQL of this script. The Secret must
+	// have a "postgres-operator.crunchydata.com/database-init-sql" label
+	// whose value is the name of this cluster.
+	// +optional
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// The database in which to run this script. Defaults to "postgres".
+	// +optional
+	Database PostgresIdentifier `json:"database,omitempty"`
+
+	// When to run this script. "Once" runs it one time after it first
+	// succeeds. "OnChange" runs it again whenever its SQL or database changes.
+	// Defaults to "Once".
+	// ---
+	// +kubebuilder:validation:Enum={Once,OnChange}
+	// +optional
+	Run string `json:"run,omitempty"`
+}
+
+// DatabaseInitSQLScript run values.
+const (
+	DatabaseInitSQLRunOnChange = "OnChange"
+	DatabaseInitSQLRunOnce     = "Once"
+)
+
+// DatabaseInitSQLScriptStatus describes the last run of one SQL script.
+type DatabaseInitSQLScriptStatus struct {
+	// The name of the script in spec.
 	// +required
-	Key string `json:"key"`
+	Name string `json:"name"`
+
+	// A hash of the database and SQL that last ran successfully.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Why the script could not run or did not succeed the last time it was
+	// attempted. It contains the line and SQLSTATE at which the script failed
+	// but never its SQL. Empty when the last attempt succeeded.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PostgresClusterDataSource defines a data source for bootstrapping PostgreSQL clusters using a
@@ -341,6 +439,12 @@ type PostgresClusterStatus struct {
 	// Identifies the databases that have been installed into PostgreSQL.
 	DatabaseRevision string `json:"databaseRevision,omitempty"`
 
+	// Current state of the databases in spec.databases.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PostgresDatabaseStatus `json:"databases,omitempty"`
+
 	// Current state of PostgreSQL instances.
 	// +listType=map
 	// +listMapKey=name
@@ -365,10 +469,26 @@ type PostgresClusterStatus struct {
 	// +optional
 	PostgresVersion int `json:"postgresVersion"`
 
+	// Current state of PostgreSQL parameters in spec.config.parameters.
+	// +optional
+	Parameters *PostgresParametersStatus `json:"parameters,omitempty"`
+
+	// Current state of the rules in spec.authentication.
+	// +optional
+	Authentication *PostgresAuthenticationStatus `json:"authentication,omitempty"`
+
 	// Current state of the PostgreSQL proxy.
 	// +optional
 	Proxy PostgresProxyStatus `json:"proxy,omitempty"`
 
+	// Current state of a standby cluster and its promotion.
+	// +optional
+	Standby *PostgresStandbyStatus `json:"standby,omitempty"`
+
+	// Current state of this cluster and its disaster recovery peer.
+	// +optional
+	DisasterRecovery *DisasterRecoveryStatus `json:"disasterRecovery,omitempty"`
+
 	// The instance that should be started first when bootstrapping and/or starting a
 	// PostgresCluster.
 	// +optional
@@ -393,6 +513,17 @@ type PostgresClusterStatus struct {
 	// +optional
 	DatabaseInitSQL *string `json:"databaseInitSQL,omitempty"`
 
+	// The state of each script in spec.databaseInitSQL.scripts
+	// ---
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DatabaseInitSQLScripts []DatabaseInitSQLScriptStatus `json:"databaseInitSQLScripts,omitempty"`
+
+	// The value of spec.restartRequestedAt once every instance has restarted.
+	// +optional
+	RestartRequestedAt *metav1.Time `json:"restartRequestedAt,omitempty"`
+
 	// observedGeneration represents the .metadata.generation on which the status was based.
 	// +optional
 	// +kubebuilder:validation:Minimum=0
@@ -443,6 +574,16 @@ type PostgresInstanceSetSpec struct {
 	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?)?$`
 	Name string `json:"name"`
 
+	// Number of seconds replicas in this set wait before replaying changes
+	// from the primary. A delayed replica is a window in which to recover
+	// from mistakes like an accidental DROP TABLE. Instances of a delayed set
+	// are never promoted and are excluded from the replica Service. Changing
+	// this value causes PostgreSQL to restart.
+	// More info: https://www.postgresql.org/docs/current/runtime-config-replication.html#GUC-RECOVERY-MIN-APPLY-DELAY
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ApplyDelaySeconds *int32 `json:"applyDelaySeconds,omitempty"`
+
 	// Scheduling constraints of a PostgreSQL pod. Changing this value causes
 	// PostgreSQL to restart.
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node
@@ -454,6 +595,21 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	Containers []corev1.Container `json:"containers,omitempty"`
 
+	// PostgreSQL configuration of the instances in this set.
+	// +optional
+	Config *PostgresInstanceConfig `json:"config,omitempty"`
+
+	// Instances of this set to take out of service without deleting them.
+	// A fenced instance receives no connections through Services, does not
+	// count toward the PodDisruptionBudget, and is never promoted. A fenced
+	// primary remains in service until another instance takes over. A name
+	// that matches no instance of this set is reported in the "FenceValid"
+	// condition.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Fence []InstanceFence `json:"fence,omitempty"`
+
 	// Defines a PersistentVolumeClaim for PostgreSQL data.
 	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes
 	// ---
@@ -478,12 +634,25 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// Patroni settings for the instances in this set.
+	// +optional
+	Patroni *PatroniInstanceSetSpec `json:"patroni,omitempty"`
+
 	// Number of desired PostgreSQL pods.
 	// +optional
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Minimum=1
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Name of another instance set from which replicas in this set stream
+	// changes. When unset, or when the other set has no instances, replicas
+	// stream from the primary. Sets must not replicate from one another in a
+	// cycle. Patroni applies changes to this value without restarting.
+	// More info: https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?)?$`
+	ReplicateFrom string `json:"replicateFrom,omitempty"`
+
 	// Minimum number of pods that should be available at a time.
 	// Defaults to one when the replicas field is greater than one.
 	// +optional
@@ -569,6 +738,30 @@ type TablespaceVolume struct {
 	DataVolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimSpec"`
 }
 
+// InstanceFence describes an instance that is out of service.
+type InstanceFence struct {
+	// The name of an instance in this set, e.g. "hippo-00-abcd".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Whether or not to stop PostgreSQL in the instance. When true, the Pod of
+	// the instance is removed and its volumes are kept. Defaults to false.
+	// +optional
+	StopPostgres *bool `json:"stopPostgres,omitempty"`
+}
+
+// FencedInstance returns the fence of the named instance of s, or nil when
+// that instance is not fenced.
+func (s *PostgresInstanceSetSpec) FencedInstance(name string) *InstanceFence {
+	for i := range s.Fence {
+		if s.Fence[i].Name == name {
+			return &s.Fence[i]
+		}
+	}
+	return nil
+}
+
 // InstanceSidecars defines the configuration for instance sidecar containers
 type InstanceSidecars struct {
 	// Defines the configuration for the replica cert copy sidecar container
@@ -591,14 +784,31 @@ func (s *PostgresInstanceSetSpec) Default(i int) {
 type PostgresInstanceSetStatus struct {
 	Name string `json:"name"`
 
+	// Number of seconds the replicas of this set are configured to wait before
+	// replaying changes from the primary, according to the running pods. This
+	// is the setting of recovery_min_apply_delay, not a measurement of replay
+	// lag. During a rollout this is the smallest delay of any pod.
+	// +optional
+	ConfiguredApplyDelaySeconds *int32 `json:"configuredApplyDelaySeconds,omitempty"`
+
 	// Total number of ready pods.
 	// +optional
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 
+	// Total number of pods that have restarted PostgreSQL since
+	// spec.restartRequestedAt. Reported while a restart is in progress.
+	// +optional
+	RestartedReplicas int32 `json:"restartedReplicas,omitempty"`
+
 	// Total number of pods.
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// Names of the instances that are fenced according to spec and Patroni.
+	// +listType=set
+	// +optional
+	FencedInstances []string `json:"fencedInstances,omitempty"`
+
 	// Total number of pods that have the desired specification.
 	// +optional
 	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
@@ -652,6 +862,105 @@ type PostgresStandbySpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=1024
 	Port *int32 `json:"port,omitempty"`
+
+	// Checks to perform before a standby cluster is promoted. When this is set
+	// and enabled changes to false, the cluster continues to follow its source
+	// until every check passes. Set this before changing enabled.
+	// +optional
+	Promotion *PostgresStandbyPromotionSpec `json:"promotion,omitempty"`
+}
+
+type PostgresStandbyPromotionSpec struct {
+	// Whether or not the source PostgreSQL server has been stopped or otherwise
+	// prevented from accepting writes. When this is false, the cluster is
+	// promoted only when the source host cannot be reached.
+	// +optional
+	SourceFenced bool `json:"sourceFenced,omitempty"`
+}
+
+type DisasterRecoverySpec struct {
+	// The PostgresCluster at the other site. One of the two clusters should
+	// be a standby of the other according to their spec.standby fields.
+	// Promote the standby by setting its spec.standby.enabled to false; the
+	// other cluster follows it once the peer is reachable.
+	// +required
+	Peer DisasterRecoveryPeer `json:"peer"`
+
+	// Number of seconds the peer must be unreachable before this standby
+	// cluster is promoted. The peer must have been reached at least once and
+	// reported itself as primary. When this is not set, the peer is observed
+	// but this cluster is never promoted automatically.
+	// +optional
+	// +kubebuilder:validation:Minimum=60
+	FailoverAfterSeconds *int32 `json:"failoverAfterSeconds,omitempty"`
+}
+
+type DisasterRecoveryPeer struct {
+	// A key of a Secret in this namespace that contains a kubeconfig for the
+	// Kubernetes cluster of the peer. It must allow getting PostgresClusters.
+	// Its server certificate authority, token, and client certificate must be
+	// embedded; exec credentials, auth providers, and file paths are rejected.
+	// +required
+	KubeconfigSecret corev1.SecretKeySelector `json:"kubeconfigSecret"`
+
+	// Name of the peer PostgresCluster.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the peer PostgresCluster.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+type DisasterRecoveryStatus struct {
+	// The last time the peer PostgresCluster was read successfully.
+	// +optional
+	LastPeerContactTime *metav1.Time `json:"lastPeerContactTime,omitempty"`
+
+	// The role of the peer according to its status.
+	// +optional
+	PeerRole string `json:"peerRole,omitempty"`
+
+	// Number of seconds between now and the last transaction replayed by this
+	// standby cluster.
+	// +optional
+	ReplicationLagSeconds *int64 `json:"replicationLagSeconds,omitempty"`
+
+	// The role of this cluster: "primary" or "standby".
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// When this standby cluster was last promoted.
+	// +optional
+	SiteFailover *DisasterRecoveryEvent `json:"siteFailover,omitempty"`
+
+	// When this cluster became a standby because its peer was promoted. It
+	// remains in effect until spec.standby.enabled is true.
+	// +optional
+	SiteDemotion *DisasterRecoveryEvent `json:"siteDemotion,omitempty"`
+
+	// When this standby cluster was promoted because its peer was unreachable
+	// for spec.disasterRecovery.failoverAfterSeconds. It remains in effect until
+	// spec.standby.enabled is false or the peer is promoted again.
+	// +optional
+	SitePromotion *DisasterRecoveryEvent `json:"sitePromotion,omitempty"`
+}
+
+type DisasterRecoveryEvent struct {
+	// The generation of the PostgresCluster when this happened.
+	// +required
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// +required
+	Time metav1.Time `json:"time"`
+}
+
+type PostgresStandbyStatus struct {
+	// The PostgreSQL timeline that began when the standby cluster was promoted.
+	// +optional
+	PromotionTimeline *int64 `json:"promotionTimeline,omitempty"`
 }
 
 // UserInterfaceSpec is a union of the supported PostgreSQL user interfaces.
@@ -678,6 +987,58 @@ type PostgresUserInterfaceStatus struct {
 
 type PostgresAdditionalConfig struct {
 	Files []corev1.VolumeProjection `json:"files,omitempty"`
+
+	// PostgreSQL parameters of every instance in the cluster. Each is checked
+	// against the parameters known to spec.postgresVersion; those that are
+	// unknown, invalid, or managed by the operator are reported in
+	// status.parameters and not applied. These take precedence over parameters
+	// in spec.patroni.dynamicConfiguration.
+	// More info: https://www.postgresql.org/docs/current/runtime-config.html
+	// ---
+	// +kubebuilder:validation:MaxProperties=50
+	//
+	// The operator and Patroni manage where PostgreSQL files are.
+	// +kubebuilder:validation:XValidation:rule=`!has(self.config_file) && !has(self.data_directory) && !has(self.hba_file) && !has(self.ident_file)`,message=`cannot change file locations: config_file, data_directory, hba_file, ident_file`
+	//
+	// +mapType=granular
+	// +optional
+	Parameters map[string]intstr.IntOrString `json:"parameters,omitempty"`
+}
+
+// PostgresParametersStatus describes the PostgreSQL parameters of a cluster.
+type PostgresParametersStatus struct {
+	// Parameters in spec.config.parameters and the config of instance sets
+	// that were not applied, each followed by the reason.
+	// +listType=atomic
+	// +optional
+	Rejected []string `json:"rejected,omitempty"`
+
+	// Parameters that have changed but take effect only after PostgreSQL
+	// restarts, as reported by Patroni.
+	// +listType=atomic
+	// +optional
+	PendingRestart []string `json:"pendingRestart,omitempty"`
+}
+
+type PostgresInstanceConfig struct {
+	// PostgreSQL parameters of the instances in this set. These take
+	// precedence over parameters of the cluster. Changing this value causes
+	// PostgreSQL to restart. Parameters that cannot be applied are listed in
+	// the status of the cluster.
+	// More info: https://www.postgresql.org/docs/current/runtime-config.html
+	// ---
+	// +kubebuilder:validation:MaxProperties=50
+	//
+	// The operator and Patroni manage where PostgreSQL files are.
+	// +kubebuilder:validation:XValidation:rule=`!has(self.config_file) && !has(self.data_directory) && !has(self.hba_file) && !has(self.ident_file)`,message=`cannot change file locations: config_file, data_directory, hba_file, ident_file`
+	//
+	// Patroni ignores these unless they are the same on every instance.
+	// - https://patroni.readthedocs.io/en/latest/patroni_configuration.html#important-rules
+	// +kubebuilder:validation:XValidation:rule=`!has(self.hot_standby) && !has(self.max_connections) && !has(self.max_locks_per_transaction) && !has(self.max_prepared_transactions) && !has(self.max_replication_slots) && !has(self.max_wal_senders) && !has(self.max_worker_processes) && !has(self.track_commit_timestamp) && !has(self.wal_keep_size) && !has(self.wal_level) && !has(self.wal_log_hints)`,message=`must be the same on every instance: hot_standby, max_connections, max_locks_per_transaction, max_prepared_transactions, max_replication_slots, max_wal_senders, max_worker_processes, track_commit_timestamp, wal_keep_size, wal_level, wal_log_hints`
+	//
+	// +mapType=granular
+	// +optional
+	Parameters map[string]intstr.IntOrString `json:"parameters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
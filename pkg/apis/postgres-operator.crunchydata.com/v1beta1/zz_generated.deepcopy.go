@@ -351,6 +351,13 @@ func (in *DataSourceVolumes) DeepCopy() *DataSourceVolumes {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseInitSQL) DeepCopyInto(out *DatabaseInitSQL) {
 	*out = *in
+	if in.Scripts != nil {
+		in, out := &in.Scripts, &out.Scripts
+		*out = make([]DatabaseInitSQLScript, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQL.
@@ -363,6 +370,138 @@ func (in *DatabaseInitSQL) DeepCopy() *DatabaseInitSQL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseInitSQLScript) DeepCopyInto(out *DatabaseInitSQLScript) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQLScript.
+func (in *DatabaseInitSQLScript) DeepCopy() *DatabaseInitSQLScript {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseInitSQLScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseInitSQLScriptStatus) DeepCopyInto(out *DatabaseInitSQLScriptStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQLScriptStatus.
+func (in *DatabaseInitSQLScriptStatus) DeepCopy() *DatabaseInitSQLScriptStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseInitSQLScriptStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryEvent) DeepCopyInto(out *DisasterRecoveryEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryEvent.
+func (in *DisasterRecoveryEvent) DeepCopy() *DisasterRecoveryEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryPeer) DeepCopyInto(out *DisasterRecoveryPeer) {
+	*out = *in
+	in.KubeconfigSecret.DeepCopyInto(&out.KubeconfigSecret)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryPeer.
+func (in *DisasterRecoveryPeer) DeepCopy() *DisasterRecoveryPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoverySpec) DeepCopyInto(out *DisasterRecoverySpec) {
+	*out = *in
+	in.Peer.DeepCopyInto(&out.Peer)
+	if in.FailoverAfterSeconds != nil {
+		in, out := &in.FailoverAfterSeconds, &out.FailoverAfterSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoverySpec.
+func (in *DisasterRecoverySpec) DeepCopy() *DisasterRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
+	*out = *in
+	if in.LastPeerContactTime != nil {
+		in, out := &in.LastPeerContactTime, &out.LastPeerContactTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReplicationLagSeconds != nil {
+		in, out := &in.ReplicationLagSeconds, &out.ReplicationLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SiteFailover != nil {
+		in, out := &in.SiteFailover, &out.SiteFailover
+		*out = new(DisasterRecoveryEvent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SiteDemotion != nil {
+		in, out := &in.SiteDemotion, &out.SiteDemotion
+		*out = new(DisasterRecoveryEvent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SitePromotion != nil {
+		in, out := &in.SitePromotion, &out.SitePromotion
+		*out = new(DisasterRecoveryEvent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
+func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
 	*out = *in
@@ -391,6 +530,26 @@ func (in *ExporterSpec) DeepCopy() *ExporterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceFence) DeepCopyInto(out *InstanceFence) {
+	*out = *in
+	if in.StopPostgres != nil {
+		in, out := &in.StopPostgres, &out.StopPostgres
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceFence.
+func (in *InstanceFence) DeepCopy() *InstanceFence {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceFence)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceSidecars) DeepCopyInto(out *InstanceSidecars) {
 	*out = *in
@@ -1447,6 +1606,57 @@ func (in *PGUpgradeStatus) DeepCopy() *PGUpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniInstanceSetSpec) DeepCopyInto(out *PatroniInstanceSetSpec) {
+	*out = *in
+	if in.FailoverPriority != nil {
+		in, out := &in.FailoverPriority, &out.FailoverPriority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NoFailover != nil {
+		in, out := &in.NoFailover, &out.NoFailover
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NoLoadBalance != nil {
+		in, out := &in.NoLoadBalance, &out.NoLoadBalance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NoSync != nil {
+		in, out := &in.NoSync, &out.NoSync
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniInstanceSetSpec.
+func (in *PatroniInstanceSetSpec) DeepCopy() *PatroniInstanceSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniInstanceSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniLeaderChange) DeepCopyInto(out *PatroniLeaderChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniLeaderChange.
+func (in *PatroniLeaderChange) DeepCopy() *PatroniLeaderChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniLeaderChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatroniLogConfig) DeepCopyInto(out *PatroniLogConfig) {
 	*out = *in
@@ -1472,6 +1682,117 @@ func (in *PatroniLogConfig) DeepCopy() *PatroniLogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniLogicalSlot) DeepCopyInto(out *PatroniLogicalSlot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniLogicalSlot.
+func (in *PatroniLogicalSlot) DeepCopy() *PatroniLogicalSlot {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniLogicalSlot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniLogicalSlotStatus) DeepCopyInto(out *PatroniLogicalSlotStatus) {
+	*out = *in
+	if in.LagBytes != nil {
+		in, out := &in.LagBytes, &out.LagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MissingInstances != nil {
+		in, out := &in.MissingInstances, &out.MissingInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniLogicalSlotStatus.
+func (in *PatroniLogicalSlotStatus) DeepCopy() *PatroniLogicalSlotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniLogicalSlotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniPhysicalSlot) DeepCopyInto(out *PatroniPhysicalSlot) {
+	*out = *in
+	if in.RetainedWALThreshold != nil {
+		in, out := &in.RetainedWALThreshold, &out.RetainedWALThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniPhysicalSlot.
+func (in *PatroniPhysicalSlot) DeepCopy() *PatroniPhysicalSlot {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniPhysicalSlot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniPhysicalSlotStatus) DeepCopyInto(out *PatroniPhysicalSlotStatus) {
+	*out = *in
+	if in.RetainedWALBytes != nil {
+		in, out := &in.RetainedWALBytes, &out.RetainedWALBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniPhysicalSlotStatus.
+func (in *PatroniPhysicalSlotStatus) DeepCopy() *PatroniPhysicalSlotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniPhysicalSlotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniReinitialize) DeepCopyInto(out *PatroniReinitialize) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniReinitialize.
+func (in *PatroniReinitialize) DeepCopy() *PatroniReinitialize {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniReinitialize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniReinitializeProgress) DeepCopyInto(out *PatroniReinitializeProgress) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniReinitializeProgress.
+func (in *PatroniReinitializeProgress) DeepCopy() *PatroniReinitializeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniReinitializeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 	*out = *in
@@ -1486,6 +1807,23 @@ func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 		*out = new(PatroniLogConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LogicalSlots != nil {
+		in, out := &in.LogicalSlots, &out.LogicalSlots
+		*out = make([]PatroniLogicalSlot, len(*in))
+		copy(*out, *in)
+	}
+	if in.PhysicalSlots != nil {
+		in, out := &in.PhysicalSlots, &out.PhysicalSlots
+		*out = make([]PatroniPhysicalSlot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Port != nil {
 		in, out := &in.Port, &out.Port
 		*out = new(int32)
@@ -1496,6 +1834,11 @@ func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Reinitialize != nil {
+		in, out := &in.Reinitialize, &out.Reinitialize
+		*out = new(PatroniReinitialize)
+		**out = **in
+	}
 	if in.Switchover != nil {
 		in, out := &in.Switchover, &out.Switchover
 		*out = new(PatroniSwitchover)
@@ -1516,6 +1859,42 @@ func (in *PatroniSpec) DeepCopy() *PatroniSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatroniStatus) DeepCopyInto(out *PatroniStatus) {
 	*out = *in
+	if in.LeaderHistory != nil {
+		in, out := &in.LeaderHistory, &out.LeaderHistory
+		*out = make([]PatroniLeaderChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogicalSlots != nil {
+		in, out := &in.LogicalSlots, &out.LogicalSlots
+		*out = make([]PatroniLogicalSlotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PhysicalSlots != nil {
+		in, out := &in.PhysicalSlots, &out.PhysicalSlots
+		*out = make([]PatroniPhysicalSlotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Reinitialize != nil {
+		in, out := &in.Reinitialize, &out.Reinitialize
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReinitializeProgress != nil {
+		in, out := &in.ReinitializeProgress, &out.ReinitializeProgress
+		*out = new(PatroniReinitializeProgress)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Switchover != nil {
 		in, out := &in.Switchover, &out.Switchover
 		*out = new(string)
@@ -1568,6 +1947,13 @@ func (in *PostgresAdditionalConfig) DeepCopyInto(out *PostgresAdditionalConfig)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAdditionalConfig.
@@ -1581,11 +1967,73 @@ func (in *PostgresAdditionalConfig) DeepCopy() *PostgresAdditionalConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresCluster) DeepCopyInto(out *PostgresCluster) {
+func (in *PostgresAuthenticationSpec) DeepCopyInto(out *PostgresAuthenticationSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PostgresHBARule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAuthenticationSpec.
+func (in *PostgresAuthenticationSpec) DeepCopy() *PostgresAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresAuthenticationStatus) DeepCopyInto(out *PostgresAuthenticationStatus) {
+	*out = *in
+	if in.Rejected != nil {
+		in, out := &in.Rejected, &out.Rejected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAuthenticationStatus.
+func (in *PostgresAuthenticationStatus) DeepCopy() *PostgresAuthenticationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresAuthenticationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresCertificateAuthentication) DeepCopyInto(out *PostgresCertificateAuthentication) {
+	*out = *in
+	if in.CommonNames != nil {
+		in, out := &in.CommonNames, &out.CommonNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresCertificateAuthentication.
+func (in *PostgresCertificateAuthentication) DeepCopy() *PostgresCertificateAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresCertificateAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresCluster) DeepCopyInto(out *PostgresCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1690,6 +2138,11 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(DataSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PostgresAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Backups.DeepCopyInto(&out.Backups)
 	if in.CustomTLSSecret != nil {
 		in, out := &in.CustomTLSSecret, &out.CustomTLSSecret
@@ -1701,10 +2154,17 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(corev1.SecretProjection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresDatabaseSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.DatabaseInitSQL != nil {
 		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
 		*out = new(DatabaseInitSQL)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.DisableDefaultPodScheduling != nil {
 		in, out := &in.DisableDefaultPodScheduling, &out.DisableDefaultPodScheduling
@@ -1768,6 +2228,10 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(ServiceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RestartRequestedAt != nil {
+		in, out := &in.RestartRequestedAt, &out.RestartRequestedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Shutdown != nil {
 		in, out := &in.Shutdown, &out.Shutdown
 		*out = new(bool)
@@ -1778,6 +2242,11 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(PostgresStandbySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoverySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupplementalGroups != nil {
 		in, out := &in.SupplementalGroups, &out.SupplementalGroups
 		*out = make([]int64, len(*in))
@@ -1806,6 +2275,13 @@ func (in *PostgresClusterSpec) DeepCopy() *PostgresClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.InstanceSets != nil {
 		in, out := &in.InstanceSets, &out.InstanceSets
 		*out = make([]PostgresInstanceSetStatus, len(*in))
@@ -1824,7 +2300,27 @@ func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 		*out = new(RegistrationRequirementStatus)
 		**out = **in
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(PostgresParametersStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PostgresAuthenticationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Proxy = in.Proxy
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(PostgresStandbyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UserInterface != nil {
 		in, out := &in.UserInterface, &out.UserInterface
 		*out = new(PostgresUserInterfaceStatus)
@@ -1836,6 +2332,15 @@ func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DatabaseInitSQLScripts != nil {
+		in, out := &in.DatabaseInitSQLScripts, &out.DatabaseInitSQLScripts
+		*out = make([]DatabaseInitSQLScriptStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartRequestedAt != nil {
+		in, out := &in.RestartRequestedAt, &out.RestartRequestedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1855,6 +2360,193 @@ func (in *PostgresClusterStatus) DeepCopy() *PostgresClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseGrant) DeepCopyInto(out *PostgresDatabaseGrant) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
+		*out = make([]PostgresSchemaGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseGrant.
+func (in *PostgresDatabaseGrant) DeepCopy() *PostgresDatabaseGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseSpec) DeepCopyInto(out *PostgresDatabaseSpec) {
+	*out = *in
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]PostgresExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseSpec.
+func (in *PostgresDatabaseSpec) DeepCopy() *PostgresDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseStatus) DeepCopyInto(out *PostgresDatabaseStatus) {
+	*out = *in
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]PostgresExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseStatus.
+func (in *PostgresDatabaseStatus) DeepCopy() *PostgresDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresExtensionSpec) DeepCopyInto(out *PostgresExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresExtensionSpec.
+func (in *PostgresExtensionSpec) DeepCopy() *PostgresExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresExtensionStatus) DeepCopyInto(out *PostgresExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresExtensionStatus.
+func (in *PostgresExtensionStatus) DeepCopy() *PostgresExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresGSSAuthentication) DeepCopyInto(out *PostgresGSSAuthentication) {
+	*out = *in
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Keytab.DeepCopyInto(&out.Keytab)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresGSSAuthentication.
+func (in *PostgresGSSAuthentication) DeepCopy() *PostgresGSSAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresGSSAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresHBARule) DeepCopyInto(out *PostgresHBARule) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresHBARule.
+func (in *PostgresHBARule) DeepCopy() *PostgresHBARule {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresHBARule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceConfig) DeepCopyInto(out *PostgresInstanceConfig) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceConfig.
+func (in *PostgresInstanceConfig) DeepCopy() *PostgresInstanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresInstanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 	*out = *in
@@ -1863,6 +2555,11 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 		*out = new(Metadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ApplyDelaySeconds != nil {
+		in, out := &in.ApplyDelaySeconds, &out.ApplyDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(corev1.Affinity)
@@ -1875,12 +2572,29 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(PostgresInstanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fence != nil {
+		in, out := &in.Fence, &out.Fence
+		*out = make([]InstanceFence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
 	if in.PriorityClassName != nil {
 		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
 		**out = **in
 	}
+	if in.Patroni != nil {
+		in, out := &in.Patroni, &out.Patroni
+		*out = new(PatroniInstanceSetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
@@ -1938,6 +2652,16 @@ func (in *PostgresInstanceSetSpec) DeepCopy() *PostgresInstanceSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresInstanceSetStatus) DeepCopyInto(out *PostgresInstanceSetStatus) {
 	*out = *in
+	if in.ConfiguredApplyDelaySeconds != nil {
+		in, out := &in.ConfiguredApplyDelaySeconds, &out.ConfiguredApplyDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FencedInstances != nil {
+		in, out := &in.FencedInstances, &out.FencedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.DesiredPGDataVolume != nil {
 		in, out := &in.DesiredPGDataVolume, &out.DesiredPGDataVolume
 		*out = make(map[string]string, len(*in))
@@ -1957,9 +2681,123 @@ func (in *PostgresInstanceSetStatus) DeepCopy() *PostgresInstanceSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresLDAPAuthentication) DeepCopyInto(out *PostgresLDAPAuthentication) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTLS != nil {
+		in, out := &in.StartTLS, &out.StartTLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresLDAPAuthentication.
+func (in *PostgresLDAPAuthentication) DeepCopy() *PostgresLDAPAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresLDAPAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresParametersStatus) DeepCopyInto(out *PostgresParametersStatus) {
+	*out = *in
+	if in.Rejected != nil {
+		in, out := &in.Rejected, &out.Rejected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRestart != nil {
+		in, out := &in.PendingRestart, &out.PendingRestart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresParametersStatus.
+func (in *PostgresParametersStatus) DeepCopy() *PostgresParametersStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresParametersStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresPasswordRotationSpec) DeepCopyInto(out *PostgresPasswordRotationSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RequestedAt != nil {
+		in, out := &in.RequestedAt, &out.RequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordRotationSpec.
+func (in *PostgresPasswordRotationSpec) DeepCopy() *PostgresPasswordRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresPasswordRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresPasswordSource) DeepCopyInto(out *PostgresPasswordSource) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordSource.
+func (in *PostgresPasswordSource) DeepCopy() *PostgresPasswordSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresPasswordSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresPasswordSpec) DeepCopyInto(out *PostgresPasswordSpec) {
 	*out = *in
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(PostgresPasswordRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PostgresPasswordSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordSpec.
@@ -2008,6 +2846,56 @@ func (in *PostgresProxyStatus) DeepCopy() *PostgresProxyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresSchemaGrant) DeepCopyInto(out *PostgresSchemaGrant) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Creators != nil {
+		in, out := &in.Creators, &out.Creators
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sequences != nil {
+		in, out := &in.Sequences, &out.Sequences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSchemaGrant.
+func (in *PostgresSchemaGrant) DeepCopy() *PostgresSchemaGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresSchemaGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresStandbyPromotionSpec) DeepCopyInto(out *PostgresStandbyPromotionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresStandbyPromotionSpec.
+func (in *PostgresStandbyPromotionSpec) DeepCopy() *PostgresStandbyPromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresStandbyPromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresStandbySpec) DeepCopyInto(out *PostgresStandbySpec) {
 	*out = *in
@@ -2016,6 +2904,11 @@ func (in *PostgresStandbySpec) DeepCopyInto(out *PostgresStandbySpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Promotion != nil {
+		in, out := &in.Promotion, &out.Promotion
+		*out = new(PostgresStandbyPromotionSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresStandbySpec.
@@ -2028,6 +2921,56 @@ func (in *PostgresStandbySpec) DeepCopy() *PostgresStandbySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresStandbyStatus) DeepCopyInto(out *PostgresStandbyStatus) {
+	*out = *in
+	if in.PromotionTimeline != nil {
+		in, out := &in.PromotionTimeline, &out.PromotionTimeline
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresStandbyStatus.
+func (in *PostgresStandbyStatus) DeepCopy() *PostgresStandbyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresStandbyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserAuthenticationSpec) DeepCopyInto(out *PostgresUserAuthenticationSpec) {
+	*out = *in
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(PostgresCertificateAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GSS != nil {
+		in, out := &in.GSS, &out.GSS
+		*out = new(PostgresGSSAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(PostgresLDAPAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserAuthenticationSpec.
+func (in *PostgresUserAuthenticationSpec) DeepCopy() *PostgresUserAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresUserInterfaceStatus) DeepCopyInto(out *PostgresUserInterfaceStatus) {
 	*out = *in
@@ -2052,10 +2995,27 @@ func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
 		*out = make([]PostgresIdentifier, len(*in))
 		copy(*out, *in)
 	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresDatabaseGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemberOf != nil {
+		in, out := &in.MemberOf, &out.MemberOf
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
 	if in.Password != nil {
 		in, out := &in.Password, &out.Password
 		*out = new(PostgresPasswordSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PostgresUserAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -2215,12 +3175,12 @@ func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	}
 	if in.InternalTrafficPolicy != nil {
 		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
-		*out = new(corev1.ServiceInternalTrafficPolicy)
+		*out = new(corev1.ServiceInternalTrafficPolicyType)
 		**out = **in
 	}
 	if in.ExternalTrafficPolicy != nil {
 		in, out := &in.ExternalTrafficPolicy, &out.ExternalTrafficPolicy
-		*out = new(corev1.ServiceExternalTrafficPolicy)
+		*out = new(corev1.ServiceExternalTrafficPolicyType)
 		**out = **in
 	}
 }